@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/api"
+	"github.com/masa-finance/masa-oracle/pkg/config"
+)
+
+// handleExport implements "masa-node export": it downloads an export of the results in the export spool of
+// a running node through its API, since the spool cannot be opened while the node runs. The API token from
+// the node's /auth endpoint is sent with the request, so nodes that require one can be exported from. With
+// a cursor file the export resumes after the previous one, and the cursor is only updated once an export is
+// complete, so running the same command every night exports each result once.
+// exportTokenEnv is the environment variable holding the API token sent by "masa-node export".
+const exportTokenEnv = "MASA_API_TOKEN"
+
+func handleExport(args []string) error {
+	apiAddress := os.Getenv(config.APIListenAddress)
+	if apiAddress == "" {
+		apiAddress = "127.0.0.1:8080"
+	}
+
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	node := flags.String("node", "http://"+apiAddress, "URL of the API of the node")
+	format := flags.String("format", "jsonl", "Export format: jsonl, csv or parquet")
+	workType := flags.String("type", "", "Only export results of this work type, e.g. twitter or web")
+	from := flags.String("from", "", "Only export results stored at or after this RFC 3339 time")
+	to := flags.String("to", "", "Only export results stored before this RFC 3339 time")
+	cursorFile := flags.String("cursor-file", "", "File holding the cursor of the previous export, updated after each complete export")
+	out := flags.String("out", "", "File to write the export to, the standard output by default")
+	token := flags.String("token", "", "API token of the node, "+exportTokenEnv+" by default")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *token == "" {
+		*token = os.Getenv(exportTokenEnv)
+	}
+
+	query := url.Values{"format": {*format}}
+	for name, value := range map[string]string{"workType": *workType, "from": *from, "to": *to} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if *cursorFile != "" {
+		cursor, err := os.ReadFile(*cursorFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if c := strings.TrimSpace(string(cursor)); c != "" {
+			query.Set("cursor", c)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(*node, "/")+"/api/v1/export?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if *token != "" {
+		req.Header.Set("Authorization", "Bearer "+*token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("export failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	// The export is written next to the output file first, so an incomplete export does not replace it
+	var output io.Writer = os.Stdout
+	var tmp *os.File
+	if *out != "" {
+		if tmp, err = os.CreateTemp(filepath.Dir(*out), filepath.Base(*out)+".*.tmp"); err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		output = tmp
+	}
+	if _, err := io.Copy(output, resp.Body); err != nil {
+		return err
+	}
+	// Trailers are only available once the body has been read
+	if status := resp.Trailer.Get(api.HeaderExportStatus); status != "complete" {
+		return fmt.Errorf("export incomplete: %s", status)
+	}
+	if tmp != nil {
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), *out); err != nil {
+			return err
+		}
+	}
+
+	cursor := resp.Header.Get(api.HeaderExportCursor)
+	if *cursorFile != "" {
+		if err := os.WriteFile(*cursorFile, []byte(cursor+"\n"), 0600); err != nil {
+			return err
+		}
+	}
+	logrus.Infof("[+] Export complete, next export resumes at cursor %s", cursor)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/masa-finance/masa-oracle/internal/versioning"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/masa-finance/masa-oracle/node"
@@ -16,6 +17,7 @@ import (
 	"github.com/masa-finance/masa-oracle/pkg/config"
 	"github.com/masa-finance/masa-oracle/pkg/db"
 	"github.com/masa-finance/masa-oracle/pkg/staking"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
 )
 
 func main() {
@@ -34,6 +36,14 @@ func main() {
 		os.Exit(0)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		logrus.SetLevel(logrus.InfoLevel)
+		if err := handleExport(os.Args[2:]); err != nil {
+			logrus.Fatalf("[-] %v", err)
+		}
+		os.Exit(0)
+	}
+
 	cfg, err := config.GetConfig()
 	if err != nil {
 		logrus.Fatalf("[-] %v", err)
@@ -69,22 +79,33 @@ func main() {
 	}
 
 	// Verify the staking event
-	isStaked, err := staking.VerifyStakingEvent(cfg.RpcUrl, cfg.KeyManager.EthAddress)
+	stakeAmount, err := staking.GetStakeAmount(cfg.RpcUrl, cfg.KeyManager.EthAddress)
 	if err != nil {
 		logrus.Error(err)
 	}
+	isStaked := stakeAmount != nil && stakeAmount.Sign() > 0
 
 	if !isStaked {
 		logrus.Warn("No staking event found for this address")
 	}
 
 	masaNodeOptions, workHandlerManager, pubKeySub := config.InitOptions(cfg)
+	if err := workHandlerManager.CheckAttestation(); err != nil {
+		logrus.Fatalf("[-] Cannot require attestations for %s: %v", cfg.RequireAttestation, err)
+	}
+	if isStaked {
+		masaNodeOptions = append(masaNodeOptions, node.WithStakeAmount(stakeAmount.String()))
+	}
 	// Create a new OracleNode
 	masaNode, err := node.NewOracleNode(ctx, masaNodeOptions...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
 
+	// Advertise the work handlers' capabilities, and stop advertising work whose handler keeps crashing
+	masaNode.SetCapabilityProvider(workHandlerManager.Capabilities)
+	workHandlerManager.SetCapabilityListener(masaNode.SetWorkCategoryEnabled)
+
 	if onlyPrintPubKey {
 		fmt.Print(masaNode.Host.ID())
 		os.Exit(0)
@@ -109,6 +130,9 @@ func main() {
 	go handleSignals(cancel, masaNode, cfg)
 
 	if cfg.APIEnabled {
+		prometheus.MustRegister(node.NewMetricsCollector(masaNode, func(workType string) bool {
+			return data_types.WorkerTypeToCategory(data_types.WorkerType(workType)) >= 0
+		}))
 		router := api.SetupRoutes(masaNode, workHandlerManager, pubKeySub)
 		go func() {
 			if err := router.Run(cfg.APIListenAddress); err != nil {
@@ -129,6 +153,9 @@ func main() {
 	}
 
 	<-ctx.Done()
+	if err := workHandlerManager.Close(); err != nil {
+		logrus.Errorf("[-] Failed to close the worker stores: %v", err)
+	}
 }
 
 func handleSignals(cancel context.CancelFunc, masaNode *node.OracleNode, cfg *config.AppConfig) {
@@ -0,0 +1,113 @@
+package node
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/masa-finance/masa-oracle/pkg/metrics"
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+)
+
+var (
+	connectedPeersDesc = prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "", "connected_peers"),
+		"Peers the node is connected to.", nil, nil)
+	routingTableSizeDesc = prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "dht", "routing_table_size"),
+		"Peers in the DHT routing table.", nil, nil)
+	nodesDesc = prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "", "nodes"),
+		"Nodes known to the node tracker, by state (known, active or staked).", []string{"state"}, nil)
+	nodesByCategoryDesc = prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "", "nodes_by_category"),
+		"Active staked nodes able to do work of a category.", []string{"category"}, nil)
+	nodesByWorkTypeDesc = prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "", "nodes_by_work_type"),
+		"Active nodes advertising a capability for a work type, with unknown work types counted as other.", []string{"work_type"}, nil)
+	chainHeightDesc = prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "chain", "height"),
+		"Number of the last block added to the chain by this node.", nil, nil)
+)
+
+// otherWorkType is the work_type label of the capabilities of work types that are not known.
+const otherWorkType = "other"
+
+// metricsCollector collects the network and node tracker gauges of a node when it is scraped.
+type metricsCollector struct {
+	node          *OracleNode
+	knownWorkType func(workType string) bool
+}
+
+// NewMetricsCollector returns a Prometheus collector of the connected peers, the DHT routing table size,
+// the nodes known to the node tracker and the chain height of the node. Work types are advertised by peers,
+// so only those that knownWorkType recognises get their own series, to keep peers from adding series.
+func NewMetricsCollector(node *OracleNode, knownWorkType func(workType string) bool) prometheus.Collector {
+	return &metricsCollector{node: node, knownWorkType: knownWorkType}
+}
+
+// Describe implements prometheus.Collector.
+func (mc *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- connectedPeersDesc
+	ch <- routingTableSizeDesc
+	ch <- nodesDesc
+	ch <- nodesByCategoryDesc
+	ch <- nodesByWorkTypeDesc
+	ch <- chainHeightDesc
+}
+
+// Collect implements prometheus.Collector.
+func (mc *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	node := mc.node
+	if node.Host != nil {
+		ch <- prometheus.MustNewConstMetric(connectedPeersDesc, prometheus.GaugeValue, float64(len(node.Host.Network().Peers())))
+	}
+	if node.DHT != nil {
+		ch <- prometheus.MustNewConstMetric(routingTableSizeDesc, prometheus.GaugeValue, float64(node.DHT.RoutingTable().Size()))
+	}
+	if node.Blockchain != nil {
+		ch <- prometheus.MustNewConstMetric(chainHeightDesc, prometheus.GaugeValue, float64(node.Blockchain.CurrentBlock))
+	}
+	if node.NodeTracker == nil {
+		return
+	}
+
+	categories := []pubsub.WorkerCategory{pubsub.CategoryDiscord, pubsub.CategoryTelegram, pubsub.CategoryTwitter, pubsub.CategoryWeb}
+	var active, staked int
+	byCategory := make(map[pubsub.WorkerCategory]int, len(categories))
+	byWorkType := make(map[string]int)
+	known := make(map[string]bool)
+	nodes := node.NodeTracker.GetAllNodeData()
+	for _, nd := range nodes {
+		if nd.IsStaked {
+			staked++
+		}
+		if !nd.IsActive {
+			continue
+		}
+		active++
+		for _, category := range categories {
+			if nd.CanDoWork(category) {
+				byCategory[category]++
+			}
+		}
+		workTypes := make(map[string]bool, len(nd.Capabilities))
+		for _, capability := range nd.Capabilities {
+			workType := capability.WorkerType
+			isKnown, checked := known[workType]
+			if !checked {
+				isKnown = mc.knownWorkType(workType)
+				known[workType] = isKnown
+			}
+			if !isKnown {
+				workType = otherWorkType
+			}
+			workTypes[workType] = true
+		}
+		for workType := range workTypes {
+			byWorkType[workType]++
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(nodesDesc, prometheus.GaugeValue, float64(len(nodes)), "known")
+	ch <- prometheus.MustNewConstMetric(nodesDesc, prometheus.GaugeValue, float64(active), "active")
+	ch <- prometheus.MustNewConstMetric(nodesDesc, prometheus.GaugeValue, float64(staked), "staked")
+	for _, category := range categories {
+		ch <- prometheus.MustNewConstMetric(nodesByCategoryDesc, prometheus.GaugeValue, float64(byCategory[category]), category.String())
+	}
+	for workType, count := range byWorkType {
+		ch <- prometheus.MustNewConstMetric(nodesByWorkTypeDesc, prometheus.GaugeValue, float64(count), workType)
+	}
+}
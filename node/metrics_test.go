@@ -0,0 +1,52 @@
+package node
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	node, err := NewOracleNode(context.Background(), EnableRandomIdentity, EnableUDP, WithPort(0), EnableStaked, IsWebScraper)
+	require.NoError(t, err)
+	defer node.Host.Close()
+	// Work types other than the known ones are advertised by peers, and counted under one label
+	nodeData := node.getNodeData()
+	nodeData.Capabilities = []pubsub.Capability{
+		{WorkerType: "web", Category: pubsub.CategoryWeb},
+		{WorkerType: "forged-1", Category: pubsub.CategoryWeb},
+		{WorkerType: "forged-2", Category: pubsub.CategoryWeb},
+	}
+	node.NodeTracker.HandleNodeData(*nodeData)
+
+	expected := `
+# HELP masa_nodes Nodes known to the node tracker, by state (known, active or staked).
+# TYPE masa_nodes gauge
+masa_nodes{state="active"} 1
+masa_nodes{state="known"} 1
+masa_nodes{state="staked"} 1
+# HELP masa_nodes_by_category Active staked nodes able to do work of a category.
+# TYPE masa_nodes_by_category gauge
+masa_nodes_by_category{category="Discord"} 0
+masa_nodes_by_category{category="Telegram"} 0
+masa_nodes_by_category{category="Twitter"} 0
+masa_nodes_by_category{category="Web"} 1
+# HELP masa_nodes_by_work_type Active nodes advertising a capability for a work type, with unknown work types counted as other.
+# TYPE masa_nodes_by_work_type gauge
+masa_nodes_by_work_type{work_type="other"} 1
+masa_nodes_by_work_type{work_type="web"} 1
+# HELP masa_connected_peers Peers the node is connected to.
+# TYPE masa_connected_peers gauge
+masa_connected_peers 0
+`
+	collector := NewMetricsCollector(node, func(workType string) bool { return workType == "web" })
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "masa_nodes", "masa_nodes_by_category", "masa_nodes_by_work_type", "masa_connected_peers"))
+	// The DHT routing table size is only reported once the node is started
+	assert.Equal(t, 8, testutil.CollectAndCount(collector, "masa_nodes", "masa_nodes_by_category", "masa_chain_height", "masa_dht_routing_table_size"))
+}
@@ -12,6 +12,7 @@ import (
 
 type NodeOption struct {
 	IsStaked    bool
+	StakeAmount string
 	UDP         bool
 	TCP         bool
 	IsValidator bool
@@ -38,7 +39,10 @@ type NodeOption struct {
 	NodeGossipTopic      string
 	Rendezvous           string
 	WorkerProtocol       string
-	PageSize             int
+	// WorkerEnvelopeProtocol carries work requests in versioned frames, see pkg/workers/wire.
+	// Peers that do not support it are sent work over WorkerProtocol.
+	WorkerEnvelopeProtocol string
+	PageSize               int
 
 	KeyManager *masacrypto.KeyManager
 }
@@ -206,6 +210,19 @@ func WithWorkerProtocol(s string) Option {
 	}
 }
 
+func WithWorkerEnvelopeProtocol(s string) Option {
+	return func(o *NodeOption) {
+		o.WorkerEnvelopeProtocol = s
+	}
+}
+
+// WithStakeAmount sets the amount of tokens staked by the node, in wei, as advertised to other nodes.
+func WithStakeAmount(amount string) Option {
+	return func(o *NodeOption) {
+		o.StakeAmount = amount
+	}
+}
+
 func WithPageSize(size int) Option {
 	return func(o *NodeOption) {
 		o.PageSize = size
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	ethereumCrypto "github.com/ethereum/go-ethereum/crypto"
@@ -44,6 +45,10 @@ type OracleNode struct {
 	Blockchain    *chain.Chain
 	Options       NodeOption
 	Context       context.Context
+
+	disabledMu         sync.RWMutex
+	disabledCategories map[pubsub.WorkerCategory]bool
+	capabilities       func() []pubsub.Capability
 }
 
 // GetP2PMultiAddrs returns the multiaddresses for the host in P2P format.
@@ -168,8 +173,16 @@ func (node *OracleNode) getNodeData() *pubsub.NodeData {
 
 	nodeData := pubsub.NewNodeData(node.Host.Addrs(), node.Host.ID(), publicEthAddress, pubsub.ActivityJoined)
 	nodeData.IsStaked = node.Options.IsStaked
-	nodeData.IsTwitterScraper = node.Options.IsTwitterScraper
-	nodeData.IsWebScraper = node.Options.IsWebScraper
+	nodeData.StakeAmount = node.Options.StakeAmount
+	if node.capabilities != nil {
+		// The provider leaves out the work it cannot do, such as disabled handlers
+		nodeData.SetCapabilities(node.capabilities())
+	} else {
+		nodeData.IsTwitterScraper = node.Options.IsTwitterScraper && !node.isCategoryDisabled(pubsub.CategoryTwitter)
+		nodeData.IsDiscordScraper = node.Options.IsDiscordScraper && !node.isCategoryDisabled(pubsub.CategoryDiscord)
+		nodeData.IsTelegramScraper = node.Options.IsTelegramScraper && !node.isCategoryDisabled(pubsub.CategoryTelegram)
+		nodeData.IsWebScraper = node.Options.IsWebScraper && !node.isCategoryDisabled(pubsub.CategoryWeb)
+	}
 	nodeData.IsValidator = node.Options.IsValidator
 	nodeData.IsActive = true
 	nodeData.Version = versioning.ProtocolVersion
@@ -312,6 +325,48 @@ func (node *OracleNode) handleStream(stream network.Stream) {
 	logrus.Infof("[+] nodeStream -> Received data from: %s", remotePeer.String())
 }
 
+// SetCapabilityProvider registers the function that lists the work the node can do. Its capabilities
+// are advertised in the node data as they are, and the legacy capability flags derived from them; without
+// a provider only the legacy flags are advertised, as set by the node options and SetWorkCategoryEnabled.
+func (node *OracleNode) SetCapabilityProvider(capabilities func() []pubsub.Capability) {
+	node.capabilities = capabilities
+}
+
+// SetWorkCategoryEnabled stops or resumes advertising a category of work the node is configured for in the
+// legacy capability flags of a node without a capability provider, and gossips the updated node data so
+// other nodes stop or resume sending it that work. The capabilities of a provider are advertised as they are,
+// so the node data is gossiped whenever it is called, for the capabilities of the provider to be updated.
+func (node *OracleNode) SetWorkCategoryEnabled(category pubsub.WorkerCategory, enabled bool) {
+	node.disabledMu.Lock()
+	if node.disabledCategories == nil {
+		node.disabledCategories = make(map[pubsub.WorkerCategory]bool)
+	}
+	changed := node.disabledCategories[category] == enabled
+	if enabled {
+		delete(node.disabledCategories, category)
+	} else {
+		node.disabledCategories[category] = true
+	}
+	node.disabledMu.Unlock()
+
+	switch {
+	case changed && enabled:
+		logrus.Infof("[+] Advertising %s work again", category)
+	case changed:
+		logrus.Warnf("[-] No longer advertising %s work", category)
+	}
+	if err := node.NodeTracker.AddOrUpdateNodeData(node.getNodeData(), true); err != nil {
+		logrus.Errorf("[-] Error updating node data: %v", err)
+	}
+}
+
+// isCategoryDisabled reports whether the category was withdrawn with SetWorkCategoryEnabled.
+func (node *OracleNode) isCategoryDisabled(category pubsub.WorkerCategory) bool {
+	node.disabledMu.RLock()
+	defer node.disabledMu.RUnlock()
+	return node.disabledCategories[category]
+}
+
 // IsWorker determines if the OracleNode is configured to act as an actor.
 // An actor node is one that has at least one of the following scrapers enabled:
 // TwitterScraper, DiscordScraper, TelegramScraper or WebScraper.
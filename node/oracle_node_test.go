@@ -100,3 +100,21 @@ func TestOracleNode_GetNodeData(t *testing.T) {
 		})
 	}
 }
+
+func TestOracleNode_GetNodeDataWithCapabilityProvider(t *testing.T) {
+	node, err := NewOracleNode(context.Background(), EnableRandomIdentity, EnableUDP, WithPort(0), IsTwitterScraper)
+	require.NoError(t, err)
+	defer node.Host.Close()
+	capabilities := []pubsub.Capability{
+		{WorkerType: "twitter", Category: pubsub.CategoryTwitter},
+		{WorkerType: "twitter-followers", Category: pubsub.CategoryTwitter},
+	}
+	node.SetCapabilityProvider(func() []pubsub.Capability { return capabilities })
+
+	// Withdrawing a category only changes the legacy flags of nodes without a provider, the provider
+	// leaves out the handlers that were disabled
+	node.disabledCategories = map[pubsub.WorkerCategory]bool{pubsub.CategoryTwitter: true}
+	nodeData := node.getNodeData()
+	assert.Equal(t, capabilities, nodeData.Capabilities)
+	assert.True(t, nodeData.IsTwitterScraper)
+}
@@ -33,8 +33,20 @@ func (node *OracleNode) topicWithVersion(protocolName string) string {
 	return fmt.Sprintf("%s/%s/%s-%s", masaPrefix, protocolName, node.Options.Version, node.Options.Environment)
 }
 
-func (node *OracleNode) ProtocolStream(ctx context.Context, peerID peer.ID, protocolName string) (network.Stream, error) {
-	return node.Host.NewStream(ctx, peerID, node.protocolWithVersion(protocolName))
+// ProtocolStream opens a stream to the peer for the given protocol. If fallbacks are given, the first
+// of the protocols that the peer supports is used; check the stream's Protocol against ProtocolID to
+// find out which one.
+func (node *OracleNode) ProtocolStream(ctx context.Context, peerID peer.ID, protocolName string, fallbacks ...string) (network.Stream, error) {
+	pids := []protocol.ID{node.protocolWithVersion(protocolName)}
+	for _, name := range fallbacks {
+		pids = append(pids, node.protocolWithVersion(name))
+	}
+	return node.Host.NewStream(ctx, peerID, pids...)
+}
+
+// ProtocolID returns the libp2p protocol ID used for the given protocol name.
+func (node *OracleNode) ProtocolID(protocolName string) protocol.ID {
+	return node.protocolWithVersion(protocolName)
 }
 
 // SubscribeToTopics handles the subscription to various topics for an OracleNode.
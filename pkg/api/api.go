@@ -17,6 +17,8 @@ type API struct {
 	Node                      *node.OracleNode
 	EventTracker              *event.EventTracker
 	WorkManager               *workers.WorkHandlerManager
+	JobManager                *workers.JobManager
+	Scheduler                 *workers.Scheduler
 	PubKeySubscriptionHandler *pubsub.PublicKeySubscriptionHandler
 }
 
@@ -29,10 +31,13 @@ func NewAPI(node *node.OracleNode, workManager *workers.WorkHandlerManager, pubk
 		logrus.Debug("EventTracker created successfully")
 	}
 
+	jobManager := workers.NewJobManager(node, workManager)
 	api := &API{
 		Node:                      node,
 		EventTracker:              eventTracker,
 		WorkManager:               workManager,
+		JobManager:                jobManager,
+		Scheduler:                 workers.NewScheduler(node, workManager, jobManager),
 		PubKeySubscriptionHandler: pubkeySubscriptionHandler,
 	}
 
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// contextKeyClient is the gin context key of the identity of a client authenticated with an API token.
+const contextKeyClient = "masa.client"
+
+// bearerSchema is the prefix of API tokens in the Authorization header.
+const bearerSchema = "Bearer "
+
+// Errors of API token authentication, returned to the client.
+var (
+	errTokenRequired = errors.New("API token required")
+	errTokenInvalid  = errors.New("Invalid JWT token")
+	errTokenExpired  = errors.New("JWT token has expired")
+)
+
+// authenticate checks the API token in the Authorization header, which is signed with the peer ID of the
+// node, and returns the identity of the client holding it: the subject of the token, which is kept when the
+// token is renewed through /auth.
+func authenticate(c *gin.Context, signingKey string) (string, error) {
+	authHeader := c.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, bearerSchema) {
+		return "", errTokenRequired
+	}
+	token := authHeader[len(bearerSchema):]
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(signingKey), nil
+	}); err != nil {
+		return "", errTokenInvalid
+	}
+	if exp, ok := claims["exp"].(float64); ok && int64(exp) < time.Now().Unix() {
+		return "", errTokenExpired
+	}
+	// Tokens issued before they named a subject have to be renewed through /auth
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return "", errTokenInvalid
+	}
+	return subject, nil
+}
+
+// clientID returns the identity of the client if it was authenticated with an API token, and "" otherwise.
+// Sealed results are only issued to and unsealed for authenticated clients.
+func clientID(c *gin.Context) string {
+	return c.GetString(contextKeyClient)
+}
+
+// requireToken returns a middleware that rejects clients without a valid API token, on staked nodes too,
+// where the API is otherwise open.
+func requireToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if clientID(c) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": errTokenRequired.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// trustedProxies returns the proxies whose X-Forwarded-For headers are trusted for the client IP, from the
+// comma-separated API_TRUSTED_PROXIES. None are trusted by default.
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("API_TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/consensus"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+const testPeerID = "16Uiu2HAm9Nkz9kEMnL1YqPTtXZHQZ1E9rhquwSqKNsUViqTojLZt"
+
+// testContext returns a gin context for a request with the given headers.
+func testContext(headers map[string]string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.RemoteAddr = "203.0.113.7:4321"
+	for name, value := range headers {
+		c.Request.Header.Set(name, value)
+	}
+	return c
+}
+
+func TestAuthenticate(t *testing.T) {
+	token, err := consensus.GenerateJWTToken(testPeerID, "client-1")
+	require.NoError(t, err)
+	renewed, err := consensus.GenerateJWTToken(testPeerID, "client-1")
+	require.NoError(t, err)
+	other, err := consensus.GenerateJWTToken(testPeerID, "client-2")
+	require.NoError(t, err)
+
+	client, err := authenticate(testContext(map[string]string{"Authorization": "Bearer " + token}), testPeerID)
+	require.NoError(t, err)
+	assert.Equal(t, "client-1", client)
+	renewedClient, err := authenticate(testContext(map[string]string{"Authorization": "Bearer " + renewed}), testPeerID)
+	require.NoError(t, err)
+	assert.Equal(t, client, renewedClient, "renewed tokens identify the same client")
+	otherClient, err := authenticate(testContext(map[string]string{"Authorization": "Bearer " + other}), testPeerID)
+	require.NoError(t, err)
+	assert.NotEqual(t, client, otherClient)
+
+	_, err = authenticate(testContext(nil), testPeerID)
+	assert.ErrorIs(t, err, errTokenRequired)
+	_, err = authenticate(testContext(map[string]string{"Authorization": "Bearer " + token}), "another-node")
+	assert.ErrorIs(t, err, errTokenInvalid)
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix(), "sub": "client-1"}).SignedString([]byte(testPeerID))
+	require.NoError(t, err)
+	_, err = authenticate(testContext(map[string]string{"Authorization": "Bearer " + expired}), testPeerID)
+	assert.Error(t, err)
+
+	anonymous, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()}).SignedString([]byte(testPeerID))
+	require.NoError(t, err)
+	_, err = authenticate(testContext(map[string]string{"Authorization": "Bearer " + anonymous}), testPeerID)
+	assert.ErrorIs(t, err, errTokenInvalid, "tokens without a subject do not identify a client")
+}
+
+func TestRequesterID(t *testing.T) {
+	// The Authorization header of unauthenticated clients does not make them another client
+	anonymous := requesterID(testContext(nil))
+	assert.True(t, strings.HasPrefix(anonymous, data_types.AnonymousRequesterPrefix))
+	assert.Equal(t, anonymous, requesterID(testContext(map[string]string{"Authorization": "Bearer forged"})))
+
+	authenticated := testContext(nil)
+	authenticated.Set(contextKeyClient, "client-1")
+	assert.Equal(t, "client-1", requesterID(authenticated))
+}
+
+func TestKeepSealed(t *testing.T) {
+	t.Setenv("KEEP_SEALED_DATA", "true")
+
+	// KEEP_SEALED_DATA applies to clients with and without a token
+	keep, err := keepSealed(testContext(nil))
+	require.NoError(t, err)
+	assert.True(t, keep)
+
+	authenticated := testContext(map[string]string{HeaderSealed: "false"})
+	authenticated.Set(contextKeyClient, "client-1")
+	keep, err = keepSealed(authenticated)
+	require.NoError(t, err)
+	assert.False(t, keep)
+
+	_, err = keepSealed(testContext(map[string]string{HeaderSealed: "maybe"}))
+	assert.Error(t, err)
+}
+
+func TestRequireToken(t *testing.T) {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "Bearer valid" {
+			c.Set(contextKeyClient, "client-1")
+		}
+	})
+	router.GET("/admin/crashes", requireToken(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/crashes", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/admin/crashes", nil)
+	request.Header.Set("Authorization", "Bearer valid")
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestTrustedProxies(t *testing.T) {
+	t.Setenv("API_TRUSTED_PROXIES", "")
+	assert.Empty(t, trustedProxies())
+
+	t.Setenv("API_TRUSTED_PROXIES", " 10.0.0.1, 192.168.0.0/24,")
+	assert.Equal(t, []string{"10.0.0.1", "192.168.0.0/24"}, trustedProxies())
+
+	// Without trusted proxies the client IP cannot be forged with X-Forwarded-For
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies(nil))
+	var clientIP string
+	router.GET("/", func(c *gin.Context) { clientIP = c.ClientIP() })
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.RemoteAddr = "203.0.113.7:4321"
+	request.Header.Set("X-Forwarded-For", "198.51.100.1")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	assert.Equal(t, "203.0.113.7", clientIP)
+}
@@ -5,11 +5,19 @@ import "time"
 // APIConfig contains configuration settings for the API
 type APIConfig struct {
 	WorkerResponseTimeout time.Duration
+	BatchMaxItems         int // maximum number of work items in a batch request
+	BatchParallelism      int // number of batch items dispatched at once when the request does not say
+	BatchMaxParallelism   int // upper bound of the parallelism a batch request may ask for
+	BatchStreamThreshold  int // batches with more items than this are answered as NDJSON
 	// Add other API-specific configuration fields here
 }
 
 var DefaultConfig = APIConfig{
 	WorkerResponseTimeout: 120 * time.Second,
+	BatchMaxItems:         1000,
+	BatchParallelism:      10,
+	BatchMaxParallelism:   50,
+	BatchStreamThreshold:  100,
 	// Set default values for other fields here
 }
 
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/masa-finance/masa-oracle/pkg/workers"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// GetCrashes returns a gin.HandlerFunc that lists the most recent work handler crashes, newest first,
+// with the stack trace of each. The optional "workType" query parameter limits the list to one work type.
+func (api *API) GetCrashes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		crashes := api.WorkManager.Crashes()
+		if workType := c.Query("workType"); workType != "" {
+			filtered := crashes[:0]
+			for _, crash := range crashes {
+				if crash.WorkType == data_types.WorkerType(workType) {
+					filtered = append(filtered, crash)
+				}
+			}
+			crashes = filtered
+		}
+		c.JSON(http.StatusOK, crashes)
+	}
+}
+
+// GetWorkHandlers returns a gin.HandlerFunc that lists the work handlers of this node, whether they are
+// enabled and how often they were called and crashed.
+func (api *API) GetWorkHandlers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, api.WorkManager.HandlerStatuses())
+	}
+}
+
+// EnableWorkHandler returns a gin.HandlerFunc that re-enables the work handler given by the "workType" URL
+// parameter after it was disabled for crashing repeatedly, so the node advertises its capability again.
+func (api *API) EnableWorkHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workType := data_types.WorkerType(c.Param("workType"))
+		if err := api.WorkManager.EnableWorkHandler(workType); err != nil {
+			if errors.Is(err, workers.ErrHandlerNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "No handler for this work type"})
+				return
+			}
+			handleError(c, "Failed to enable work handler", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"workType": workType, "enabled": true})
+	}
+}
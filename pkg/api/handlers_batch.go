@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// ndjsonContentType is the content type of streamed batch results, one JSON object per line.
+const ndjsonContentType = "application/x-ndjson"
+
+// BatchItem is a work item of a batch request.
+type BatchItem struct {
+	ID       string                `json:"id,omitempty"` // client chosen, echoed in the item's result
+	WorkType data_types.WorkerType `json:"workType"`
+	Data     json.RawMessage       `json:"data"`
+}
+
+// BatchItemResult is the outcome of a work item of a batch request. Status is the HTTP status the item
+// would have been answered with as a single request.
+type BatchItemResult struct {
+	Index        int                     `json:"index"`
+	ID           string                  `json:"id,omitempty"`
+	WorkType     data_types.WorkerType   `json:"workType"`
+	Status       int                     `json:"status"`
+	Data         interface{}             `json:"data,omitempty"`
+	WorkerPeerId string                  `json:"workerPeerId,omitempty"`
+	CacheStatus  string                  `json:"cacheStatus,omitempty"`
+	Verification tee.AttestationStatus   `json:"verification,omitempty"`
+	Error        string                  `json:"error,omitempty"`
+	Details      string                  `json:"details,omitempty"`
+	Code         data_types.ErrorCode    `json:"code,omitempty"`
+	Retryable    bool                    `json:"retryable,omitempty"`
+	Fields       []data_types.FieldError `json:"fields,omitempty"`
+}
+
+// SubmitBatch returns a gin.HandlerFunc that runs many work items, of any mix of work types, in one call.
+// It expects a JSON body with an "items" array, each with a "workType", the "data" the matching data endpoint
+// accepts and an optional "id", and an optional "parallelism", the number of items dispatched at once.
+// Items are validated and dispatched independently, so one bad or failed item does not fail the batch.
+//
+// The results are returned as a JSON object with a "results" array in item order. If the client accepts
+// application/x-ndjson, or the batch is larger than the configured stream threshold, each result is instead
+// streamed as a line of NDJSON as soon as its item finishes, so results arrive in completion order.
+func (api *API) SubmitBatch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg, err := LoadConfig()
+		if err != nil {
+			handleError(c, "Failed to load API cfg", err)
+			return
+		}
+
+		var reqBody struct {
+			Items       []BatchItem `json:"items"`
+			Parallelism int         `json:"parallelism"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		if len(reqBody.Items) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Items must be provided"})
+			return
+		}
+		if len(reqBody.Items) > cfg.BatchMaxItems {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("A batch may hold at most %d items", cfg.BatchMaxItems)})
+			return
+		}
+		parallelism := reqBody.Parallelism
+		if parallelism <= 0 {
+			parallelism = cfg.BatchParallelism
+		}
+		if parallelism > cfg.BatchMaxParallelism {
+			parallelism = cfg.BatchMaxParallelism
+		}
+
+		results := make(chan BatchItemResult)
+		go func() {
+			runBatch(c.Request.Context(), reqBody.Items, parallelism, func(ctx context.Context, item BatchItem) BatchItemResult {
+				return api.runBatchItem(ctx, c, cfg, item)
+			}, results)
+			close(results)
+		}()
+
+		if len(reqBody.Items) > cfg.BatchStreamThreshold || strings.Contains(c.GetHeader("Accept"), ndjsonContentType) {
+			streamBatchResults(c, results)
+			return
+		}
+		ordered := make([]BatchItemResult, len(reqBody.Items))
+		succeeded := 0
+		for result := range results {
+			ordered[result.Index] = result
+			if result.Error == "" {
+				succeeded++
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"results":   ordered,
+			"succeeded": succeeded,
+			"failed":    len(ordered) - succeeded,
+		})
+	}
+}
+
+// streamBatchResults writes each result as a line of NDJSON as it arrives.
+func streamBatchResults(c *gin.Context, results <-chan BatchItemResult) {
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	encoder := json.NewEncoder(c.Writer)
+	for result := range results {
+		if err := encoder.Encode(result); err != nil {
+			logrus.Errorf("[-] Failed to stream batch result %d: %v", result.Index, err)
+			continue
+		}
+		c.Writer.Flush()
+	}
+}
+
+// runBatch runs the items with run, at most parallelism at a time, and sends the result of each to results.
+// Once ctx is done the items still waiting for a slot are answered with a cancelled result without running.
+func runBatch(ctx context.Context, items []BatchItem, parallelism int, run func(context.Context, BatchItem) BatchItemResult, results chan<- BatchItemResult) {
+	slots := make(chan struct{}, parallelism)
+	wg := &sync.WaitGroup{}
+	for i, item := range items {
+		if !acquireSlot(ctx, slots) {
+			results <- itemResult(i, item, cancelledResult())
+			continue
+		}
+		wg.Add(1)
+		go func(index int, item BatchItem) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results <- itemResult(index, item, run(ctx, item))
+		}(i, item)
+	}
+	wg.Wait()
+}
+
+// acquireSlot waits for a free slot and takes it, or returns false without a slot once ctx is done.
+func acquireSlot(ctx context.Context, slots chan struct{}) bool {
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+	// A slot freed by an item that stopped because ctx is done may win over ctx.Done
+	if ctx.Err() != nil {
+		<-slots
+		return false
+	}
+	return true
+}
+
+// itemResult sets the fields identifying the item on its result.
+func itemResult(index int, item BatchItem, result BatchItemResult) BatchItemResult {
+	result.Index = index
+	result.ID = item.ID
+	result.WorkType = item.WorkType
+	return result
+}
+
+// cancelledResult is the result of an item abandoned because the client went away.
+func cancelledResult() BatchItemResult {
+	return responseResult(data_types.NewErrorResponse(data_types.ErrorCancelled, "batch request was cancelled"))
+}
+
+// runBatchItem validates and dispatches a single batch item.
+func (api *API) runBatchItem(ctx context.Context, c *gin.Context, cfg *APIConfig, item BatchItem) BatchItemResult {
+	if ctx.Err() != nil {
+		return cancelledResult()
+	}
+	if data_types.WorkerTypeToCategory(item.WorkType) < 0 {
+		return BatchItemResult{Status: http.StatusBadRequest, Error: "Unknown work type", Code: data_types.ErrorInvalidRequest}
+	}
+	data, err := data_types.NormalizeRequestData(item.WorkType, item.Data)
+	if err != nil {
+		result := BatchItemResult{Status: http.StatusBadRequest, Error: err.Error(), Code: data_types.ErrorInvalidRequest}
+		var validationErr *data_types.ValidationError
+		if errors.As(err, &validationErr) {
+			result.Fields = validationErr.Fields
+		}
+		return result
+	}
+	workRequest, err := api.newWorkRequest(c, item.WorkType, data)
+	if err != nil {
+		return BatchItemResult{Status: http.StatusBadRequest, Error: err.Error(), Code: data_types.ErrorInvalidRequest}
+	}
+
+	api.sendTrackingEvent(item.WorkType, data)
+	ctx, cancel := context.WithTimeout(ctx, cfg.WorkerResponseTimeout)
+	defer cancel()
+	response := api.WorkManager.DistributeWork(ctx, api.Node, workRequest)
+	if response.Error == "" {
+		if err := response.UnsealDataIfNeeded(workRequest, api.nodeKey()); err != nil {
+			response = data_types.NewErrorResponse(data_types.ErrorInternal, "failed to get response data: %v", err)
+		}
+	}
+	return responseResult(response)
+}
+
+// responseResult turns a work response into a batch item result with the status a single request would get.
+func responseResult(response data_types.WorkResponse) BatchItemResult {
+	result := BatchItemResult{
+		WorkerPeerId: response.WorkerPeerId,
+		CacheStatus:  response.CacheStatus,
+		Verification: response.Verification,
+	}
+	switch {
+	case response.Error != "":
+		code := response.Code()
+		result.Status, result.Error = errorStatus(response)
+		result.Details = response.Error
+		result.Code = code
+		result.Retryable = code.Retryable()
+	case response.Data == "":
+		result.Status = http.StatusNotFound
+		result.Error = "No data returned"
+	default:
+		result.Status = http.StatusOK
+		result.Data = response.Data
+	}
+	return result
+}
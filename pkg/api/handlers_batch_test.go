@@ -0,0 +1,173 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// collectBatch runs the batch and returns its results by item index, failing if it does not finish.
+func collectBatch(t *testing.T, ctx context.Context, items []BatchItem, parallelism int, run func(context.Context, BatchItem) BatchItemResult) []BatchItemResult {
+	results := make(chan BatchItemResult)
+	go func() {
+		runBatch(ctx, items, parallelism, run, results)
+		close(results)
+	}()
+	collected := make([]BatchItemResult, len(items))
+	received := 0
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				require.Equal(t, len(items), received)
+				return collected
+			}
+			collected[result.Index] = result
+			received++
+		case <-timeout:
+			require.FailNow(t, "the batch did not finish")
+		}
+	}
+}
+
+func batchItems(n int) []BatchItem {
+	items := make([]BatchItem, n)
+	for i := range items {
+		items[i] = BatchItem{ID: string(rune('a' + i)), WorkType: data_types.Web}
+	}
+	return items
+}
+
+func TestRunBatch(t *testing.T) {
+	t.Run("no more than parallelism items run at once", func(t *testing.T) {
+		var running, peak atomic.Int32
+		results := collectBatch(t, context.Background(), batchItems(12), 3, func(ctx context.Context, item BatchItem) BatchItemResult {
+			now := running.Add(1)
+			for {
+				seen := peak.Load()
+				if now <= seen || peak.CompareAndSwap(seen, now) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return BatchItemResult{Status: http.StatusOK, Data: item.ID}
+		})
+
+		assert.Equal(t, int32(3), peak.Load())
+		for i, result := range results {
+			assert.Equal(t, i, result.Index)
+			assert.Equal(t, string(rune('a'+i)), result.ID)
+			assert.Equal(t, data_types.Web, result.WorkType)
+			assert.Equal(t, result.ID, result.Data)
+		}
+	})
+
+	t.Run("items waiting when the client goes away are cancelled without running", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{}, 10)
+		var ran atomic.Int32
+		var once sync.Once
+		results := collectBatch(t, ctx, batchItems(10), 2, func(ctx context.Context, item BatchItem) BatchItemResult {
+			ran.Add(1)
+			started <- struct{}{}
+			if len(started) == 2 {
+				once.Do(cancel)
+			}
+			<-ctx.Done()
+			return cancelledResult()
+		})
+
+		assert.Equal(t, int32(2), ran.Load())
+		for _, result := range results {
+			assert.Equal(t, data_types.ErrorCancelled, result.Code)
+		}
+	})
+}
+
+func TestSubmitBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := &API{}
+	router := gin.New()
+	router.POST("/batch", api.SubmitBatch())
+	// Items with an unknown work type or invalid data are answered without being dispatched
+	body := `{"items": [
+		{"id": "unknown", "workType": "mastodon", "data": {}},
+		{"id": "invalid", "workType": "web", "data": {"depth": 1}}
+	]}`
+
+	t.Run("results are returned in item order", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Results   []BatchItemResult `json:"results"`
+			Succeeded int               `json:"succeeded"`
+			Failed    int               `json:"failed"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		require.Len(t, response.Results, 2)
+		assert.Equal(t, "unknown", response.Results[0].ID)
+		assert.Equal(t, "Unknown work type", response.Results[0].Error)
+		assert.Equal(t, "invalid", response.Results[1].ID)
+		assert.Equal(t, data_types.ErrorInvalidRequest, response.Results[1].Code)
+		assert.Equal(t, 2, response.Failed)
+	})
+
+	t.Run("clients accepting NDJSON get a line per result", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+		request.Header.Set("Accept", ndjsonContentType)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, ndjsonContentType, recorder.Header().Get("Content-Type"))
+		ids := map[string]bool{}
+		scanner := bufio.NewScanner(recorder.Body)
+		for scanner.Scan() {
+			var result BatchItemResult
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+			assert.Equal(t, http.StatusBadRequest, result.Status)
+			ids[result.ID] = true
+		}
+		assert.Equal(t, map[string]bool{"unknown": true, "invalid": true}, ids)
+	})
+
+	t.Run("batches of a client that went away are cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		request := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body)).WithContext(ctx)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		var response struct {
+			Results []BatchItemResult `json:"results"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		require.Len(t, response.Results, 2)
+		for _, result := range response.Results {
+			assert.Equal(t, data_types.ErrorCancelled, result.Code)
+		}
+	})
+
+	t.Run("empty batches are rejected", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(`{"items": []}`)))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
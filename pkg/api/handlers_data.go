@@ -1,11 +1,15 @@
 package api
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +24,131 @@ import (
 	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
 )
 
+// Headers that let API clients tune how a data request is dispatched to remote workers.
+const (
+	HeaderDispatchFanOut     = "X-Masa-Fanout"
+	HeaderDispatchHedgeDelay = "X-Masa-Hedge-Delay"
+	HeaderQuorum             = "X-Masa-Quorum"
+)
+
+// HeaderSealed asks for the result to be returned sealed in a SealedResult, "true", or decrypted, "false".
+// Requests without it follow KEEP_SEALED_DATA. Sealed results can be unsealed later through /unseal.
+const HeaderSealed = "X-Masa-Sealed"
+
+// HeaderCacheStatus reports whether a data response was served from the result cache.
+// Clients can bypass the cache by sending "Cache-Control: no-cache".
+const HeaderCacheStatus = "X-Masa-Cache"
+
+// requesterID returns an opaque identifier for the API client, used to route its requests to the same
+// workers when sticky worker selection is configured, and to name the client a sealed result is issued to.
+// It is the identity of clients authenticated with an API token, and derived from the client IP otherwise.
+func requesterID(c *gin.Context) string {
+	if client := clientID(c); client != "" {
+		return client
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP()))
+	return data_types.AnonymousRequesterPrefix + hex.EncodeToString(sum[:8])
+}
+
+// bypassCache reports whether the client asked for a fresh result through the Cache-Control header.
+func bypassCache(c *gin.Context) bool {
+	for _, directive := range strings.Split(c.GetHeader("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache", "no-store":
+			return true
+		}
+	}
+	return false
+}
+
+// keepSealed reports whether the client asked for sealed results through the X-Masa-Sealed header, or
+// KEEP_SEALED_DATA if it did not say. Sealed results of clients without an API token do not name a
+// requester, so they can only be unsealed through the TEE worker, not through /unseal.
+func keepSealed(c *gin.Context) (bool, error) {
+	sealedHeader := c.GetHeader(HeaderSealed)
+	if sealedHeader == "" {
+		return data_types.KeepSealedByDefault(), nil
+	}
+	keep, err := strconv.ParseBool(sealedHeader)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s header: must be true or false", HeaderSealed)
+	}
+	return keep, nil
+}
+
+// parseQuorum parses the value of the quorum header, either the number of workers "N" or "M/N"
+// for M of N workers having to agree.
+func parseQuorum(value string) (*data_types.QuorumOptions, error) {
+	invalid := fmt.Errorf("invalid %s header: must be N or M/N with 0 < M <= N", HeaderQuorum)
+	agreeStr, workersStr, hasAgree := strings.Cut(value, "/")
+	if !hasAgree {
+		workersStr, agreeStr = agreeStr, ""
+	}
+	workers, err := strconv.Atoi(strings.TrimSpace(workersStr))
+	if err != nil || workers < 1 {
+		return nil, invalid
+	}
+	quorum := &data_types.QuorumOptions{Workers: workers}
+	if hasAgree {
+		agree, err := strconv.Atoi(strings.TrimSpace(agreeStr))
+		if err != nil || agree < 1 || agree > workers {
+			return nil, invalid
+		}
+		quorum.Agree = agree
+	}
+	return quorum, nil
+}
+
+// newWorkRequest creates a work request with a fresh request ID for the given work type and body.
+// Dispatch options are taken from the X-Masa-Fanout (number of workers) and X-Masa-Hedge-Delay
+// (duration such as "500ms") headers when present, and whether to keep the result sealed from X-Masa-Sealed.
+func (api *API) newWorkRequest(c *gin.Context, workType data_types.WorkerType, bodyBytes []byte) (data_types.WorkRequest, error) {
+	request := data_types.WorkRequest{
+		WorkType:  workType,
+		RequestId: uuid.New().String(),
+		Data:      bodyBytes,
+		Requester: requesterID(c),
+		NoCache:   bypassCache(c),
+	}
+
+	keepSealed, err := keepSealed(c)
+	if err != nil {
+		return request, err
+	}
+	request.KeepSealed = keepSealed
+
+	if quorumHeader := c.GetHeader(HeaderQuorum); quorumHeader != "" {
+		quorum, err := parseQuorum(quorumHeader)
+		if err != nil {
+			return request, err
+		}
+		request.Quorum = quorum
+	}
+
+	fanOutHeader := c.GetHeader(HeaderDispatchFanOut)
+	hedgeDelayHeader := c.GetHeader(HeaderDispatchHedgeDelay)
+	if fanOutHeader == "" && hedgeDelayHeader == "" {
+		return request, nil
+	}
+
+	request.Dispatch = &data_types.DispatchOptions{}
+	if fanOutHeader != "" {
+		fanOut, err := strconv.Atoi(fanOutHeader)
+		if err != nil || fanOut < 1 {
+			return request, fmt.Errorf("invalid %s header: must be a positive integer", HeaderDispatchFanOut)
+		}
+		request.Dispatch.FanOut = fanOut
+	}
+	if hedgeDelayHeader != "" {
+		hedgeDelay, err := time.ParseDuration(hedgeDelayHeader)
+		if err != nil || hedgeDelay <= 0 {
+			return request, fmt.Errorf("invalid %s header: must be a positive duration", HeaderDispatchHedgeDelay)
+		}
+		request.Dispatch.HedgeDelayMs = hedgeDelay.Milliseconds()
+	}
+	return request, nil
+}
+
 // sendWorkRequest sends a work request to a worker for processing.
 // It marshals the request details into JSON and sends it over a libp2p stream.
 // It is currently re-using the response channel map for this; however, it could be a simple synchronous call
@@ -27,21 +156,21 @@ import (
 //
 // Parameters:
 // - api: The API instance containing the Node and PubSubManager.
-// - requestID: A unique identifier for the request.
-// - workType: The type of work to be performed by the worker.
-// - bodyBytes: The request body in byte slice format.
+// - request: The work request, as created by newWorkRequest.
 //
 // Returns:
 // - error: An error object if the request could not be sent or processed, otherwise nil.
-func (api *API) sendWorkRequest(requestID string, workType data_types.WorkerType, bodyBytes []byte, wg *sync.WaitGroup) error {
-	request := data_types.WorkRequest{
-		WorkType:  workType,
-		RequestId: requestID,
-		Data:      bodyBytes,
+//
+// Once ctx is done, the workers are told to stop working on the request and no response is sent.
+func (api *API) sendWorkRequest(ctx context.Context, request data_types.WorkRequest) error {
+	requestID := request.RequestId
+	response := api.WorkManager.DistributeWork(ctx, api.Node, request)
+	if ctx.Err() != nil {
+		// The client went away or timed out, handleWorkResponse already answered
+		return nil
 	}
-	response := api.WorkManager.DistributeWork(api.Node, request)
 
-	err := response.UnsealDataIfNeeded()
+	err := response.UnsealDataIfNeeded(request, api.nodeKey())
 	if err != nil {
 		return fmt.Errorf("failed to get response data: %v", err)
 	}
@@ -52,7 +181,6 @@ func (api *API) sendWorkRequest(requestID string, workType data_types.WorkerType
 	}
 	select {
 	case responseChannel <- response:
-		wg.Add(1)
 		// Successfully sent JSON response to the response channel
 	default:
 		// Log an error if the channel is blocking for debugging purposes
@@ -61,33 +189,85 @@ func (api *API) sendWorkRequest(requestID string, workType data_types.WorkerType
 	return nil
 }
 
+// validateRequestData checks bodyBytes against the request schema of workType and returns it with the defaults applied.
+// If it does not match, the client is answered with the invalid fields and ok is false.
+func validateRequestData(c *gin.Context, workType data_types.WorkerType, bodyBytes []byte) (data []byte, ok bool) {
+	data, err := data_types.NormalizeRequestData(workType, bodyBytes)
+	var validationErr *data_types.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "fields": validationErr.Fields})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return data, true
+}
+
+// dispatchWorkRequest sends the work request built from bodyBytes to the workers and answers the client with the response.
+// The workers are told to stop working on the request if the client goes away or the request times out.
+func (api *API) dispatchWorkRequest(c *gin.Context, workType data_types.WorkerType, bodyBytes []byte) {
+	bodyBytes, ok := validateRequestData(c, workType, bodyBytes)
+	if !ok {
+		return
+	}
+
+	workRequest, err := api.newWorkRequest(c, workType, bodyBytes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	api.sendTrackingEvent(workType, bodyBytes)
+	responseCh := workers.GetResponseChannelMap().CreateChannel(workRequest.RequestId)
+	wg := &sync.WaitGroup{}
+	defer workers.GetResponseChannelMap().Delete(workRequest.RequestId)
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	wg.Add(1)
+	go handleWorkResponse(ctx, cancel, c, responseCh, wg)
+
+	err = api.sendWorkRequest(ctx, workRequest)
+	if err != nil {
+		cancel()
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+	wg.Wait()
+}
+
 // handleWorkResponse processes the response from a worker and sends it back to the client.
 // It listens on the provided response channel for a response or a timeout signal.
 // If a response is received within the timeout period, it unmarshals the JSON response and sends it back to the client.
 // If no response is received within the timeout period, it sends a timeout error to the client.
 //
 // Parameters:
+// - ctx: The context of the work request, done when the client goes away.
+// - cancel: Cancels the work request, called on timeout so the workers stop working on it.
 // - c: The gin.Context object, which provides the context for the HTTP request.
 // - responseCh: A channel that receives the worker's response as a byte slice.
-func handleWorkResponse(c *gin.Context, responseCh <-chan data_types.WorkResponse, wg *sync.WaitGroup) {
+// - wg: Marked done once the client has been answered.
+func handleWorkResponse(ctx context.Context, cancel context.CancelFunc, c *gin.Context, responseCh <-chan data_types.WorkResponse, wg *sync.WaitGroup) {
+	defer wg.Done()
 	cfg, err := LoadConfig()
 	if err != nil {
+		cancel()
 		handleError(c, "Failed to load API cfg", err)
 		return
 	}
 
 	select {
 	case response := <-responseCh:
-		handleResponse(c, response, wg)
+		handleResponse(c, response)
 	case <-time.After(cfg.WorkerResponseTimeout):
+		cancel()
 		handleTimeout(c)
-	case <-c.Done():
-		// Context cancelled, no action needed
+	case <-ctx.Done():
+		// The client went away, the workers are told to stop
 	}
 }
 
-func handleResponse(c *gin.Context, response data_types.WorkResponse, wg *sync.WaitGroup) {
-	defer wg.Done()
+func handleResponse(c *gin.Context, response data_types.WorkResponse) {
 
 	if response.Error != "" {
 		handleErrorResponse(c, response)
@@ -102,27 +282,64 @@ func handleResponse(c *gin.Context, response data_types.WorkResponse, wg *sync.W
 		return
 	}
 
+	if response.CacheStatus != "" {
+		c.Header(HeaderCacheStatus, response.CacheStatus)
+	}
 	c.JSON(http.StatusOK, response)
 }
 
+// handleErrorResponse answers a failed work request with the HTTP status matching its error code.
+// The body carries the code and whether retrying may help, so that clients do not have to parse the message.
 func handleErrorResponse(c *gin.Context, response data_types.WorkResponse) {
 	logrus.Errorf("[+] Work error: %s", response.Error)
 
-	errorResponse := func(status int, message string) {
-		c.JSON(status, gin.H{
-			"error":        message,
-			"details":      response.Error,
-			"workerPeerId": response.WorkerPeerId,
-		})
+	code := response.Code()
+	status, message := errorStatus(response)
+	if (code == data_types.ErrorRateLimited || code == data_types.ErrorWorkerBusy) && response.RetryAfterMs > 0 {
+		c.Header("Retry-After", strconv.FormatInt((response.RetryAfterMs+999)/1000, 10))
+	}
+	body := gin.H{
+		"error":        message,
+		"details":      response.Error,
+		"code":         code,
+		"retryable":    code.Retryable(),
+		"workerPeerId": response.WorkerPeerId,
 	}
+	if response.Quorum != nil {
+		body["quorum"] = response.Quorum
+	}
+	if response.CrashId != "" {
+		body["crashId"] = response.CrashId
+	}
+	c.JSON(status, body)
+}
 
+// errorStatus returns the HTTP status and message matching the error code of a failed work response.
+func errorStatus(response data_types.WorkResponse) (int, string) {
+	code := response.Code()
 	switch {
-	case strings.Contains(response.Error, "Twitter API rate limit exceeded (429 error)"):
-		errorResponse(http.StatusTooManyRequests, "Twitter API rate limit exceeded")
-	case strings.Contains(response.Error, "no workers could process"):
-		errorResponse(http.StatusServiceUnavailable, "No available workers to process the request")
+	case response.Quorum != nil && code == data_types.ErrorInternal:
+		return http.StatusBadGateway, "Workers did not agree on the result"
+	case code == data_types.ErrorRateLimited:
+		return http.StatusTooManyRequests, "Data source rate limit exceeded"
+	case code == data_types.ErrorWorkerBusy:
+		return http.StatusServiceUnavailable, "All workers are busy, please retry later"
+	case code == data_types.ErrorUpstreamUnavailable:
+		return http.StatusServiceUnavailable, "No available workers to process the request"
+	case code == data_types.ErrorTimeout:
+		return http.StatusGatewayTimeout, "The request timed out"
+	case code == data_types.ErrorInvalidRequest:
+		return http.StatusBadRequest, "Invalid request"
+	case code == data_types.ErrorNotFound:
+		return http.StatusNotFound, "The requested data was not found"
+	case code == data_types.ErrorAuthFailed:
+		return http.StatusBadGateway, "Workers could not authenticate with the data source"
+	case code == data_types.ErrorAttestationFailed:
+		return http.StatusBadGateway, "Workers could not prove their results come from a trusted enclave"
+	case code == data_types.ErrorUnsupported:
+		return http.StatusNotImplemented, "Unsupported work type: no worker in the network offers it"
 	default:
-		errorResponse(http.StatusInternalServerError, "An error occurred while processing the request")
+		return http.StatusInternalServerError, "An error occurred while processing the request"
 	}
 }
 
@@ -137,80 +354,38 @@ func handleTimeout(c *gin.Context) {
 
 // SearchTweetsProfile returns a gin.HandlerFunc that processes a request to search for tweets from a specific user profile.
 // It expects a URL parameter "username" representing the Twitter username to search for.
-// The handler validates the username, ensuring it is provided.
+// The request is validated against the schema of data_types.TwitterProfileRequest.
 // If the request is valid, it attempts to scrape the user's profile and tweets.
 // On success, it returns the scraped profile information in a JSON response. On failure, it returns an appropriate error message and HTTP status code.
 func (api *API) SearchTweetsProfile() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var reqBody struct {
-			Username string `json:"username"`
-		}
-		if c.Param("username") == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Username must be provided and valid"})
-			return
-		}
-		reqBody.Username = c.Param("username")
+		reqBody := data_types.TwitterProfileRequest{Username: c.Param("username")}
 
 		// worker handler implementation
 		bodyBytes, err := json.Marshal(reqBody)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 
-		api.sendTrackingEvent(data_types.TwitterProfile, bodyBytes)
-		requestID := uuid.New().String()
-		responseCh := workers.GetResponseChannelMap().CreateChannel(requestID)
-		wg := &sync.WaitGroup{}
-		defer workers.GetResponseChannelMap().Delete(requestID)
-		go handleWorkResponse(c, responseCh, wg)
-
-		err = api.sendWorkRequest(requestID, data_types.TwitterProfile, bodyBytes, wg)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		}
-		wg.Wait()
+		api.dispatchWorkRequest(c, data_types.TwitterProfile, bodyBytes)
 	}
 }
 
 // SearchTweetsRecent returns a gin.HandlerFunc that processes a request to search for tweets based on a query and count.
 // It expects a JSON body with fields "query" (string) and "count" (int), representing the search query and the number of tweets to return, respectively.
-// The handler validates the request body, ensuring the query is not empty and the count is positive.
+// The request body is validated against the schema of data_types.TwitterQueryRequest, which bounds the count.
 // If the request is valid, it attempts to scrape tweets using the specified query and count.
 // On success, it returns the scraped tweets in a JSON response. On failure, it returns an appropriate error message and HTTP status code.
 func (api *API) SearchTweetsRecent() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var reqBody struct {
-			Query string `json:"query"`
-			Count int    `json:"count"`
-		}
-
-		if err := c.ShouldBindJSON(&reqBody); err != nil {
+		bodyBytes, err := c.GetRawData()
+		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 			return
 		}
 
-		if reqBody.Query == "" || reqBody.Count <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Query and count must be provided and valid"})
-			return
-		}
-
-		bodyBytes, err := json.Marshal(reqBody)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		}
-
-		api.sendTrackingEvent(data_types.Twitter, bodyBytes)
-		requestID := uuid.New().String()
-		responseCh := workers.GetResponseChannelMap().CreateChannel(requestID)
-		wg := &sync.WaitGroup{}
-		defer workers.GetResponseChannelMap().Delete(requestID)
-		go handleWorkResponse(c, responseCh, wg)
-
-		err = api.sendWorkRequest(requestID, data_types.Twitter, bodyBytes, wg)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		}
-		wg.Wait()
+		api.dispatchWorkRequest(c, data_types.Twitter, bodyBytes)
 	}
 }
 
@@ -218,48 +393,25 @@ func (api *API) SearchTweetsRecent() gin.HandlerFunc {
 //
 // Dev Notes:
 // - This function uses URL parameters to get the username.
-// - The default count is set to 20 if not provided.
+// - The default count of 20 is applied by the schema of data_types.TwitterFollowersRequest.
 func (api *API) SearchTwitterFollowers() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var reqBody struct {
-			Username string `json:"username"`
-			Count    int    `json:"count"`
-		}
-
-		username := c.Param("username")
-		if username == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Username parameter is missing"})
-			return
-		}
-		reqBody.Username = username
-		if reqBody.Count == 0 {
-			reqBody.Count = 20
-		}
+		reqBody := data_types.TwitterFollowersRequest{Username: c.Param("username")}
 
 		// worker handler implementation
 		bodyBytes, err := json.Marshal(reqBody)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 
-		api.sendTrackingEvent(data_types.TwitterFollowers, bodyBytes)
-		requestID := uuid.New().String()
-		responseCh := workers.GetResponseChannelMap().CreateChannel(requestID)
-		wg := &sync.WaitGroup{}
-		defer workers.GetResponseChannelMap().Delete(requestID)
-		go handleWorkResponse(c, responseCh, wg)
-
-		err = api.sendWorkRequest(requestID, data_types.TwitterFollowers, bodyBytes, wg)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		}
-		wg.Wait()
+		api.dispatchWorkRequest(c, data_types.TwitterFollowers, bodyBytes)
 	}
 }
 
 // WebData returns a gin.HandlerFunc that processes web scraping requests.
 // It expects a JSON body with fields "url" (string) and "depth" (int), representing the URL to scrape and the depth of the scrape, respectively.
-// The handler validates the request body, ensuring the URL is not empty and the depth is positive.
+// The request body is validated against the schema of data_types.WebRequest, which bounds the depth.
 // If the node has not staked, it returns an error indicating the node cannot participate.
 // On a valid request, it attempts to scrape web data using the specified URL and depth.
 // On success, it returns the scraped data in a sanitized JSON response. On failure, it returns an appropriate error message and HTTP status code.
@@ -269,41 +421,113 @@ func (api *API) WebData() gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Node has not staked and cannot participate"})
 			return
 		}
-		var reqBody struct {
-			Url   string `json:"url"`
-			Depth int    `json:"depth"`
-		}
-		if err := c.ShouldBindJSON(&reqBody); err != nil {
+		bodyBytes, err := c.GetRawData()
+		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 			return
 		}
 
-		if reqBody.Url == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "URL parameter is missing"})
+		api.dispatchWorkRequest(c, data_types.Web, bodyBytes)
+	}
+}
+
+// SearchDiscordProfile returns a gin.HandlerFunc that retrieves the profile of a Discord user.
+// It expects a URL parameter "userID" representing the Discord user ID to look up.
+func (api *API) SearchDiscordProfile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqBody := data_types.DiscordProfileRequest{UserID: c.Param("userID")}
+
+		bodyBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		if reqBody.Depth <= 0 {
-			reqBody.Depth = 1 // Default count
+
+		api.dispatchWorkRequest(c, data_types.DiscordProfile, bodyBytes)
+	}
+}
+
+// SearchDiscordChannelMessages returns a gin.HandlerFunc that retrieves the messages of a Discord channel.
+// It expects a URL parameter "channelID" and accepts the optional query parameters "limit" and "before"
+// to page back through the channel history.
+func (api *API) SearchDiscordChannelMessages() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqBody := data_types.DiscordChannelMessagesRequest{
+			ChannelID: c.Param("channelID"),
+			Before:    c.Query("before"),
+		}
+		if limit := c.Query("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil {
+				fields := []data_types.FieldError{{Field: "limit", Message: "must be of type integer"}}
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be an integer", "fields": fields})
+				return
+			}
+			reqBody.Limit = n
 		}
 
-		// worker handler implementation
 		bodyBytes, err := json.Marshal(reqBody)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 
-		api.sendTrackingEvent(data_types.Web, bodyBytes)
-		requestID := uuid.New().String()
-		responseCh := workers.GetResponseChannelMap().CreateChannel(requestID)
-		wg := &sync.WaitGroup{}
-		defer workers.GetResponseChannelMap().Delete(requestID)
-		go handleWorkResponse(c, responseCh, wg)
+		api.dispatchWorkRequest(c, data_types.DiscordChannelMessages, bodyBytes)
+	}
+}
 
-		err = api.sendWorkRequest(requestID, data_types.Web, bodyBytes, wg)
+// SearchDiscordGuildChannels returns a gin.HandlerFunc that retrieves the channels of a Discord guild.
+// It expects a URL parameter "guildID" representing the Discord guild to look up.
+func (api *API) SearchDiscordGuildChannels() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqBody := data_types.DiscordGuildChannelsRequest{GuildID: c.Param("guildID")}
+
+		bodyBytes, err := json.Marshal(reqBody)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		api.dispatchWorkRequest(c, data_types.DiscordGuildChannels, bodyBytes)
+	}
+}
+
+// SearchDiscordUserGuilds returns a gin.HandlerFunc that retrieves the guilds the worker's Discord bot is a member of.
+func (api *API) SearchDiscordUserGuilds() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		api.dispatchWorkRequest(c, data_types.DiscordUserGuilds, []byte("{}"))
+	}
+}
+
+// SearchTelegramChannelMessages returns a gin.HandlerFunc that retrieves the messages of a Telegram channel.
+// It expects a JSON body with the field "username" (string) naming the channel, and accepts the optional fields
+// "limit" (int), "offsetID" (int) to page back from a message ID, and "since" and "until" (RFC 3339 timestamps)
+// to restrict the messages to a date range.
+// The request body is validated against the schema of data_types.TelegramChannelMessagesRequest.
+func (api *API) SearchTelegramChannelMessages() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bodyBytes, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
 		}
-		wg.Wait()
+
+		api.dispatchWorkRequest(c, data_types.TelegramChannelMessages, bodyBytes)
+	}
+}
+
+// GetCacheStats returns a gin.HandlerFunc that reports the result cache counters.
+func (api *API) GetCacheStats() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, api.WorkManager.ResultCacheStats())
+	}
+}
+
+// GetCircuitBreakers returns a gin.HandlerFunc that lists the circuit breakers of the remote workers that failed
+// since their last success. Workers with an open breaker are not sent any work until their breaker half-opens.
+func (api *API) GetCircuitBreakers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, api.WorkManager.CircuitBreakers())
 	}
 }
 
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/masa-finance/masa-oracle/pkg/workers"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// SubmitJob returns a gin.HandlerFunc that queues a work request for asynchronous execution.
+// It expects a JSON body with fields "workType" (string) and "data" (object), the latter being
+// the same body the matching synchronous data endpoint accepts and is validated against the request
+// schema of the work type, an optional "dispatch" object with the fan-out width and hedge delay to use,
+// an optional "quorum" object with the number of workers to ask and how many of them have to agree, and an
+// optional "sealed" flag to keep the result sealed, see HeaderSealed.
+// On success it returns 202 Accepted with the job ID and its initial status, which can then be
+// polled through GetJob and GetJobResult.
+func (api *API) SubmitJob() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			WorkType data_types.WorkerType       `json:"workType"`
+			Data     json.RawMessage             `json:"data"`
+			Dispatch *data_types.DispatchOptions `json:"dispatch"`
+			Quorum   *data_types.QuorumOptions   `json:"quorum"`
+			Sealed   *bool                       `json:"sealed"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		if data_types.WorkerTypeToCategory(reqBody.WorkType) < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown work type"})
+			return
+		}
+		if len(reqBody.Data) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data must be provided"})
+			return
+		}
+		data, ok := validateRequestData(c, reqBody.WorkType, reqBody.Data)
+		if !ok {
+			return
+		}
+
+		workRequest, err := api.newWorkRequest(c, reqBody.WorkType, data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if reqBody.Dispatch != nil {
+			workRequest.Dispatch = reqBody.Dispatch
+		}
+		if reqBody.Quorum != nil {
+			workRequest.Quorum = reqBody.Quorum
+		}
+		if reqBody.Sealed != nil {
+			workRequest.KeepSealed = *reqBody.Sealed
+		}
+
+		api.sendTrackingEvent(reqBody.WorkType, data)
+		job, err := api.JobManager.Submit(workRequest)
+		if err != nil {
+			if errors.Is(err, workers.ErrJobQueueFull) {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+				return
+			}
+			handleError(c, "Failed to submit job", err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"jobId":  job.ID,
+			"status": job.Status,
+		})
+	}
+}
+
+// GetJob returns a gin.HandlerFunc that reports the status of the job identified by the "id" URL parameter.
+func (api *API) GetJob() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, exists := api.JobManager.Get(c.Param("id"))
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// GetJobResult returns a gin.HandlerFunc that returns the WorkResponse of the job identified by the "id" URL parameter.
+// While the job is still pending it returns 202 Accepted with the current status. A failed or dead-lettered
+// job is reported the same way the synchronous data endpoints report worker errors.
+func (api *API) GetJobResult() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, exists := api.JobManager.Get(c.Param("id"))
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		if !job.IsDone() {
+			c.JSON(http.StatusAccepted, gin.H{
+				"jobId":  job.ID,
+				"status": job.Status,
+			})
+			return
+		}
+		if job.Status == workers.JobFailed {
+			handleErrorResponse(c, *job.Response)
+			return
+		}
+		if job.Status == workers.JobDeadLettered {
+			handleErrorResponse(c, data_types.NewErrorResponse(job.ErrorCode, "%s", job.Error))
+			return
+		}
+		if job.Response.CacheStatus != "" {
+			c.Header(HeaderCacheStatus, job.Response.CacheStatus)
+		}
+		c.JSON(http.StatusOK, job.Response)
+	}
+}
+
+// GetDeadLetterJobs returns a gin.HandlerFunc that lists the jobs that were dead-lettered because they did not
+// finish within the maximum number of delivery attempts, oldest first.
+func (api *API) GetDeadLetterJobs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobs := api.JobManager.DeadLettered()
+		if jobs == nil {
+			jobs = []workers.Job{}
+		}
+		c.JSON(http.StatusOK, jobs)
+	}
+}
+
+// RequeueJob returns a gin.HandlerFunc that queues the dead-lettered job identified by the "id" URL parameter
+// again, with its delivery attempts reset. It returns 202 Accepted with the job ID and its new status.
+func (api *API) RequeueJob() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := api.JobManager.Get(c.Param("id")); !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		job, err := api.JobManager.Requeue(c.Param("id"))
+		if err != nil {
+			if errors.Is(err, workers.ErrJobNotDeadLettered) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			handleError(c, "Failed to requeue job", err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"jobId":  job.ID,
+			"status": job.Status,
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"github.com/masa-finance/masa-oracle/node"
 	"github.com/masa-finance/masa-oracle/pkg/config"
@@ -57,9 +58,13 @@ func (api *API) GetNodeDataHandler() gin.HandlerFunc {
 			TotalPages:   totalPages,
 			TotalRecords: totalRecords,
 		}
+		data := make([]nodeDataResponse, len(nodeDataPage.Data))
+		for i, nd := range nodeDataPage.Data {
+			data[i] = newNodeDataResponse(nd)
+		}
 		c.JSON(http.StatusOK, gin.H{
 			"success":      true,
-			"data":         nodeDataPage.Data,
+			"data":         data,
 			"pageNbr":      nodeDataPage.PageNumber,
 			"total":        nodeDataPage.TotalPages,
 			"totalRecords": nodeDataPage.TotalRecords,
@@ -67,6 +72,17 @@ func (api *API) GetNodeDataHandler() gin.HandlerFunc {
 	}
 }
 
+// nodeDataResponse is the node data returned by the API, with this node's record of the reliability of the
+// node, which is not part of the gossiped node data.
+type nodeDataResponse struct {
+	pubsub.NodeData
+	Reliability map[string]pubsub.Reliability `json:"reliability,omitempty"`
+}
+
+func newNodeDataResponse(nd pubsub.NodeData) nodeDataResponse {
+	return nodeDataResponse{NodeData: nd, Reliability: nd.Reliability}
+}
+
 // GetNodeHandler handles GET requests to retrieve node data for a specific peer ID.
 // It extracts the peer ID from the request URL parameters, retrieves the node data
 // from the node tracker, calculates additional uptime info, and returns the node
@@ -97,7 +113,7 @@ func (api *API) GetNodeHandler() gin.HandlerFunc {
 
 		c.JSON(http.StatusOK, gin.H{
 			"success": true,
-			"data":    nd,
+			"data":    newNodeDataResponse(nd),
 		})
 	}
 }
@@ -398,6 +414,8 @@ func (api *API) NodeStatusPageHandler() gin.HandlerFunc {
 				nd := *nodeData
 				templateData["IsStaked"] = nd.IsStaked
 				templateData["IsTwitterScraper"] = nd.IsTwitterScraper
+				templateData["IsDiscordScraper"] = nd.IsDiscordScraper
+				templateData["IsTelegramScraper"] = nd.IsTelegramScraper
 				templateData["IsWebScraper"] = nd.IsWebScraper
 				templateData["FirstJoined"] = fromUnixTime(nd.FirstJoinedUnix)
 				templateData["LastJoined"] = fromUnixTime(nd.LastJoinedUnix)
@@ -413,11 +431,18 @@ func (api *API) NodeStatusPageHandler() gin.HandlerFunc {
 
 // GetNodeApiKey returns a gin.HandlerFunc that generates and returns a JWT token for the node.
 // The JWT token is signed using the node's host ID as the secret key.
+// Clients presenting a valid token are issued a new one for the same identity, so that they can still
+// unseal their results once the old token expires; other clients are issued a token for a new identity.
 // On success, it returns the generated JWT token in a JSON response.
 // On failure, it returns an appropriate error message and HTTP status code.
 func (api *API) GetNodeApiKey() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		jwtToken, err := consensus.GenerateJWTToken(api.Node.Host.ID().String())
+		signingKey := api.Node.Host.ID().String()
+		subject, err := authenticate(c, signingKey)
+		if err != nil {
+			subject = uuid.NewString()
+		}
+		jwtToken, err := consensus.GenerateJWTToken(signingKey, subject)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"success": false,
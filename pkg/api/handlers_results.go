@@ -0,0 +1,211 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/workers"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// Number of stored results QueryResults returns without a "limit" query parameter, and at most.
+const (
+	defaultStoredResults = 100
+	maxStoredResults     = 1000
+)
+
+// StoredResultResponse is a stored result as returned by the API. Its data is unsealed for the client that
+// requested it unless it asked to keep it sealed, and wrapped into a SealedResult issued to the requester
+// otherwise. Error is set if the data could not be unsealed, in which case it is left sealed.
+type StoredResultResponse struct {
+	workers.StoredResult
+	Error string `json:"error,omitempty"`
+}
+
+// QueryResults returns a gin.HandlerFunc that lists the results kept in the result store, newest first.
+// They can be filtered with the "workType", "worker" (peer ID), "q" (text the request has to contain),
+// "from" and "to" (RFC 3339 times) query parameters, and their number limited with "limit". Results the
+// client requested are unsealed unless it sends "X-Masa-Sealed: true", other results are kept sealed.
+func (api *API) QueryResults() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		store := api.WorkManager.Results()
+		if store == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "The result store is not enabled"})
+			return
+		}
+		query, err := parseResultQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		keep, err := keepSealed(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		results, err := store.Query(query)
+		if err != nil {
+			handleError(c, "Failed to query results", err)
+			return
+		}
+		responses := make([]StoredResultResponse, len(results))
+		for i, result := range results {
+			responses[i] = api.storedResultResponse(c, result, keep)
+		}
+		c.JSON(http.StatusOK, responses)
+	}
+}
+
+// GetStoredResult returns a gin.HandlerFunc that returns the stored result of the request identified by the
+// "id" URL parameter, unsealed if the client requested it and does not send "X-Masa-Sealed: true".
+func (api *API) GetStoredResult() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		store := api.WorkManager.Results()
+		if store == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "The result store is not enabled"})
+			return
+		}
+		keep, err := keepSealed(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		result, err := store.Get(c.Param("id"))
+		if err != nil {
+			if errors.Is(err, workers.ErrResultNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Result not found"})
+				return
+			}
+			handleError(c, "Failed to get result", err)
+			return
+		}
+		c.JSON(http.StatusOK, api.storedResultResponse(c, result, keep))
+	}
+}
+
+// parseResultQuery returns the result query of the query parameters.
+func parseResultQuery(c *gin.Context) (workers.ResultQuery, error) {
+	query := workers.ResultQuery{
+		WorkType: data_types.WorkerType(c.Query("workType")),
+		Worker:   c.Query("worker"),
+		Query:    c.Query("q"),
+		Limit:    defaultStoredResults,
+	}
+	if err := checkWorkType(query.WorkType); err != nil {
+		return query, err
+	}
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > maxStoredResults {
+			return query, fmt.Errorf("limit must be an integer between 1 and %d", maxStoredResults)
+		}
+		query.Limit = limit
+	}
+	var err error
+	query.From, query.To, err = parseTimeRange(c)
+	return query, err
+}
+
+// checkWorkType returns an error if the work type is neither empty nor known.
+func checkWorkType(workType data_types.WorkerType) error {
+	if workType != "" && data_types.WorkerTypeToCategory(workType) < 0 {
+		return fmt.Errorf("unknown work type %q", workType)
+	}
+	return nil
+}
+
+// parseTimeRange returns the "from" and "to" query parameters as times, zero if they are not set.
+func parseTimeRange(c *gin.Context) (from, to time.Time, err error) {
+	for name, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		if param := c.Query(name); param != "" {
+			parsed, err := time.Parse(time.RFC3339, param)
+			if err != nil {
+				return from, to, fmt.Errorf("%s must be an RFC 3339 time", name)
+			}
+			*t = parsed
+		}
+	}
+	return from, to, nil
+}
+
+// storedResultResponse unseals the data of a stored result for the client if it may, see
+// StoredResult.UnsealableBy, and did not ask to keep it sealed. Otherwise the data is wrapped into a
+// SealedResult issued to the requester of the result, so that only it can unseal it through /unseal.
+func (api *API) storedResultResponse(c *gin.Context, result workers.StoredResult, keepSealed bool) StoredResultResponse {
+	response := result.Response()
+	request := data_types.WorkRequest{
+		WorkType:   result.WorkType,
+		RequestId:  result.ID,
+		Requester:  result.Requester,
+		KeepSealed: keepSealed || !result.UnsealableBy(clientID(c)),
+	}
+	// The requester identifies the client, which other clients are not told
+	result.Requester = ""
+	if err := response.UnsealDataIfNeeded(request, api.nodeKey()); err != nil {
+		return StoredResultResponse{StoredResult: result, Error: fmt.Sprintf("failed to get result data: %v", err)}
+	}
+	result.Data = response.Data
+	return StoredResultResponse{StoredResult: result}
+}
+
+// HeaderExportCursor is the response header holding the cursor that resumes the next export after this one.
+const HeaderExportCursor = "X-Masa-Export-Cursor"
+
+// HeaderExportStatus is the trailer of an export response, "complete" once every result was written or the
+// reason the export failed. The cursor of an export must only be used if it is complete.
+const HeaderExportStatus = "X-Masa-Export-Status"
+
+// ExportResults returns a gin.HandlerFunc that streams the results kept in the export spool as a JSON lines,
+// CSV or Parquet file, oldest first. The "format" query parameter selects the format, JSON lines by default,
+// and "workType", "from" and "to" (RFC 3339 times) the results. Passing the X-Masa-Export-Cursor header of
+// a previous export as the "cursor" query parameter only exports the results stored since. Only the results
+// the client requested are unsealed, other results are exported sealed.
+func (api *API) ExportResults() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		spool := api.WorkManager.ExportSpool()
+		if spool == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "The export spool is not enabled"})
+			return
+		}
+		format, err := workers.ParseExportFormat(c.DefaultQuery("format", string(workers.ExportJSONL)))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query := workers.ExportQuery{WorkType: data_types.WorkerType(c.Query("workType")), Cursor: c.Query("cursor"), Client: clientID(c)}
+		if err := checkWorkType(query.WorkType); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if query.From, query.To, err = parseTimeRange(c); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		export, err := spool.Export(query, time.Now())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Header("Content-Type", format.ContentType())
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=results-%s.%s", export.Cursor, format))
+		c.Header(HeaderExportCursor, export.Cursor)
+		c.Header("Trailer", HeaderExportStatus)
+		c.Status(http.StatusOK)
+		written, err := workers.WriteExport(c.Writer, export, format)
+		if err != nil {
+			logrus.Errorf("[-] Export of results failed after %d results: %v", written, err)
+			c.Writer.Header().Set(HeaderExportStatus, "failed: "+err.Error())
+			return
+		}
+		logrus.Infof("[+] Exported %d results up to cursor %s", written, export.Cursor)
+		c.Writer.Header().Set(HeaderExportStatus, "complete")
+	}
+}
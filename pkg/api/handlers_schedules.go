@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/masa-finance/masa-oracle/pkg/workers"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// defaultScheduleResults is the number of results GetScheduleResults returns without a "limit" query parameter.
+const defaultScheduleResults = 20
+
+// CreateSchedule returns a gin.HandlerFunc that creates a schedule. It expects a JSON body with fields
+// "cron" (string), "workType" (string) and "payload" (object), the latter being validated against the
+// request schema of the work type, an optional "name" and an optional "sink" object whose "type" is
+// "store" (the default), "file" with an optional relative "path", or "webhook" with a "url".
+// On success it returns 201 Created with the schedule.
+func (api *API) CreateSchedule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqBody struct {
+			Name     string                `json:"name"`
+			Cron     string                `json:"cron"`
+			WorkType data_types.WorkerType `json:"workType"`
+			Payload  json.RawMessage       `json:"payload"`
+			Sink     workers.Sink          `json:"sink"`
+		}
+		if err := c.ShouldBindJSON(&reqBody); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		if data_types.WorkerTypeToCategory(reqBody.WorkType) < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown work type"})
+			return
+		}
+		if len(reqBody.Payload) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Payload must be provided"})
+			return
+		}
+
+		schedule, err := api.Scheduler.Create(workers.Schedule{
+			Name:     reqBody.Name,
+			Cron:     reqBody.Cron,
+			WorkType: reqBody.WorkType,
+			Payload:  reqBody.Payload,
+			Sink:     reqBody.Sink,
+		})
+		var validationErr *data_types.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "fields": validationErr.Fields})
+		case errors.Is(err, workers.ErrInvalidSchedule):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err != nil:
+			handleError(c, "Failed to create schedule", err)
+		default:
+			c.JSON(http.StatusCreated, schedule)
+		}
+	}
+}
+
+// ListSchedules returns a gin.HandlerFunc that lists every schedule, oldest first.
+func (api *API) ListSchedules() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, api.Scheduler.List())
+	}
+}
+
+// GetSchedule returns a gin.HandlerFunc that returns the schedule identified by the "id" URL parameter.
+func (api *API) GetSchedule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schedule, exists := api.Scheduler.Get(c.Param("id"))
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+			return
+		}
+		c.JSON(http.StatusOK, schedule)
+	}
+}
+
+// PauseSchedule returns a gin.HandlerFunc that pauses the schedule identified by the "id" URL parameter.
+func (api *API) PauseSchedule() gin.HandlerFunc {
+	return api.setSchedulePaused(true)
+}
+
+// ResumeSchedule returns a gin.HandlerFunc that resumes the schedule identified by the "id" URL parameter.
+func (api *API) ResumeSchedule() gin.HandlerFunc {
+	return api.setSchedulePaused(false)
+}
+
+func (api *API) setSchedulePaused(paused bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schedule, err := api.Scheduler.SetPaused(c.Param("id"), paused)
+		if err != nil {
+			if errors.Is(err, workers.ErrScheduleNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+				return
+			}
+			handleError(c, "Failed to update schedule", err)
+			return
+		}
+		c.JSON(http.StatusOK, schedule)
+	}
+}
+
+// DeleteSchedule returns a gin.HandlerFunc that deletes the schedule identified by the "id" URL parameter
+// together with its stored results.
+func (api *API) DeleteSchedule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := api.Scheduler.Delete(c.Param("id")); err != nil {
+			if errors.Is(err, workers.ErrScheduleNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+				return
+			}
+			handleError(c, "Failed to delete schedule", err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetScheduleResults returns a gin.HandlerFunc that lists the results stored by the schedule identified by
+// the "id" URL parameter, newest first. The optional "limit" query parameter caps the number of results.
+func (api *API) GetScheduleResults() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultScheduleResults
+		if limitParam := c.Query("limit"); limitParam != "" {
+			var err error
+			limit, err = strconv.Atoi(limitParam)
+			if err != nil || limit < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+				return
+			}
+		}
+		results, err := api.Scheduler.Results(c.Param("id"), limit)
+		if err != nil {
+			if errors.Is(err, workers.ErrScheduleNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+				return
+			}
+			handleError(c, "Failed to get schedule results", err)
+			return
+		}
+		if results == nil {
+			results = []workers.ScheduleResult{}
+		}
+		c.JSON(http.StatusOK, results)
+	}
+}
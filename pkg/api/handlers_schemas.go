@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// GetRequestSchemas returns a gin.HandlerFunc that lists the JSON Schema of the request data of every work type,
+// keyed by work type. These are the bodies accepted by the data endpoints and in the "data" field of a job.
+func (api *API) GetRequestSchemas() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schemas := make(map[data_types.WorkerType]*data_types.JSONSchema)
+		for _, workType := range data_types.RequestWorkerTypes() {
+			schemas[workType], _ = data_types.RequestJSONSchema(workType)
+		}
+		c.JSON(http.StatusOK, schemas)
+	}
+}
+
+// GetRequestSchema returns a gin.HandlerFunc that returns the JSON Schema of the request data of the work type
+// given by the "workType" URL parameter.
+func (api *API) GetRequestSchema() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schema, ok := data_types.RequestJSONSchema(data_types.WorkerType(c.Param("workType")))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown work type"})
+			return
+		}
+		c.JSON(http.StatusOK, schema)
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/workers"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// nodeKey returns the private key of the node, which signs the sealed results it issues.
+func (api *API) nodeKey() crypto.PrivKey {
+	return api.Node.Host.Peerstore().PrivKey(api.Node.Host.ID())
+}
+
+// UnsealResult returns a gin.HandlerFunc that decrypts a result kept sealed at the request of the client.
+// It expects the SealedResult returned by a data endpoint, a job or a schedule as the JSON body. Only
+// results issued by this node are unsealed, and only for the authenticated client that requested them;
+// results of schedules can be unsealed by any authenticated client, like the schedules themselves. Results
+// issued to clients without an API token name no requester and can only be unsealed through the TEE worker.
+func (api *API) UnsealResult() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var sealed data_types.SealedResult
+		if err := c.ShouldBindJSON(&sealed); err != nil || sealed.Data == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sealed result"})
+			return
+		}
+		if err := sealed.Verify(api.nodeKey().GetPublic()); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "The sealed result was not issued by this node", "details": err.Error()})
+			return
+		}
+		if sealed.Requester == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "The sealed result was issued to a client without an API token and can only be unsealed through the TEE worker"})
+			return
+		}
+		client := clientID(c)
+		if client == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "An API token is required to unseal results"})
+			return
+		}
+		if sealed.Requester != client && !strings.HasPrefix(sealed.Requester, workers.ScheduleRequesterPrefix) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "The sealed result was issued to another client"})
+			return
+		}
+
+		data, err := sealed.Unseal()
+		if errors.Is(err, data_types.ErrUnsupportedSeal) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			logrus.Errorf("[-] Failed to unseal result of request %s: %v", sealed.RequestId, err)
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to unseal the result", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"data":         data,
+			"workType":     sealed.WorkType,
+			"requestId":    sealed.RequestId,
+			"workerPeerId": sealed.WorkerPeerId,
+		})
+	}
+}
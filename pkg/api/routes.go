@@ -6,10 +6,10 @@ import (
 	"net/http"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
 
 	"github.com/masa-finance/masa-oracle/docs"
 	"github.com/masa-finance/masa-oracle/pkg/pubsub"
@@ -36,27 +36,39 @@ var htmlTemplates embed.FS
 func SetupRoutes(node *node.OracleNode, workerManager *workers.WorkHandlerManager, pubkeySubscriptionHandler *pubsub.PublicKeySubscriptionHandler) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
+	// The client IP is only taken from X-Forwarded-For headers set by trusted proxies
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		logrus.Errorf("[-] Invalid API_TRUSTED_PROXIES, not trusting any proxy: %v", err)
+		_ = router.SetTrustedProxies(nil)
+	}
 
 	API := NewAPI(node, workerManager, pubkeySubscriptionHandler)
 
 	// Initialize CORS middleware with a configuration that allows all origins and specifies
 	// the HTTP methods and headers that can be used in requests.
 	router.Use(cors.New(cors.Config{
-		AllowAllOrigins:     true,                                      // Allow requests from any origin
-		AllowMethods:        []string{"GET", "POST", "PUT", "OPTIONS"}, // Specify allowed methods
-		AllowHeaders:        []string{"Origin", "Authorization"},       // Specify allowed headers
+		AllowAllOrigins:     true,                                                                                                                             // Allow requests from any origin
+		AllowMethods:        []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},                                                                              // Specify allowed methods
+		AllowHeaders:        []string{"Origin", "Authorization", "Cache-Control", HeaderDispatchFanOut, HeaderDispatchHedgeDelay, HeaderQuorum, HeaderSealed}, // Specify allowed headers
+		ExposeHeaders:       []string{HeaderCacheStatus, HeaderExportCursor},                                                                                  // Let browsers read the cache status of data responses and export cursors
 		AllowPrivateNetwork: true,
 	}))
 
 	// Define a list of routes that should not require authentication.
 	ignoredRoutes := []string{
 		"/status",
+		"/metrics",
 	}
 
-	// Middleware to enforce API token authentication, excluding ignored routes.
+	// Middleware to enforce API token authentication, excluding ignored routes. Clients presenting a valid
+	// API token are authenticated on staked nodes too, so that sealed results can be unsealed for them.
 	router.Use(func(c *gin.Context) {
+		signingKey := API.Node.Host.ID().String()
 
 		if API.Node.Options.IsStaked {
+			if client, err := authenticate(c, signingKey); err == nil {
+				c.Set(contextKeyClient, client)
+			}
 			c.Next() // Proceed to the next middleware or handler as a staked node.
 			return
 		}
@@ -81,49 +93,17 @@ func SetupRoutes(node *node.OracleNode, workerManager *workers.WorkHandlerManage
 			}
 		}
 
-		// Define the prefix expected in the Authorization header.
-		const BearerSchema = "Bearer "
-		// Retrieve the Authorization header from the request.
-		authHeader := c.GetHeader("Authorization")
-		// If the Authorization header is missing, abort the request with an unauthorized status code and message.
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API token required"})
-			return
-		}
-		// Extract the token from the Authorization header by removing the Bearer schema prefix.
-		token := authHeader[len(BearerSchema):]
-
 		// Validate the token against the expected API key stored in environment variables.
-		// logrus.Info(os.Getenv("API_KEY"))
-		if os.Getenv("API_KEY") == "" {
+		if c.GetHeader("Authorization") != "" && os.Getenv("API_KEY") == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "JWT Token required"})
 			return
 		}
-
-		// Validate the JWT token
-		claims := jwt.MapClaims{}
-		_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-			//@todo decode the token get the apiKey, hash and compare it
-			//if token != ???? {
-			//	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API token"})
-			//	return
-			//} else {
-			//	c.Next()
-			//	return
-			//}
-			return []byte(API.Node.Host.ID().String()), nil
-		})
+		client, err := authenticate(c, signingKey)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid JWT token"})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
-		// Check if the token has expired
-		if exp, ok := claims["exp"].(float64); ok {
-			if int64(exp) < time.Now().Unix() {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "JWT token has expired"})
-				return
-			}
-		}
+		c.Set(contextKeyClient, client)
 		c.Next()
 	})
 
@@ -220,6 +200,324 @@ func SetupRoutes(node *node.OracleNode, workerManager *workers.WorkHandlerManage
 		// @Router /data/web [post]
 		v1.POST("/data/web", API.WebData())
 
+		// @Summary Search Discord Profile
+		// @Description Retrieves the profile of a Discord user
+		// @Tags Discord
+		// @Accept  json
+		// @Produce  json
+		// @Param   userID   path    string  true  "Discord User ID"
+		// @Success 200 {object} object "Discord user profile"
+		// @Failure 400 {object} ErrorResponse "Invalid user ID or error fetching profile"
+		// @Failure 501 {object} ErrorResponse "No worker in the network offers the work type, the TEE worker has no Discord jobs yet"
+		// @Router /data/discord/profile/{userID} [get]
+		v1.GET("/data/discord/profile/:userID", API.SearchDiscordProfile())
+
+		// @Summary Search Discord Channel Messages
+		// @Description Retrieves messages from a Discord channel
+		// @Tags Discord
+		// @Accept  json
+		// @Produce  json
+		// @Param   channelID   path    string  true  "Discord Channel ID"
+		// @Param   limit   query   int     false  "Maximum number of messages to return"
+		// @Param   before   query   string     false  "Only return messages sent before this message ID"
+		// @Success 200 {array} object "List of channel messages"
+		// @Failure 400 {object} ErrorResponse "Invalid channel ID or error fetching messages"
+		// @Failure 501 {object} ErrorResponse "No worker in the network offers the work type, the TEE worker has no Discord jobs yet"
+		// @Router /data/discord/channels/{channelID}/messages [get]
+		v1.GET("/data/discord/channels/:channelID/messages", API.SearchDiscordChannelMessages())
+
+		// @Summary Search Discord Guild Channels
+		// @Description Retrieves the channels of a Discord guild
+		// @Tags Discord
+		// @Accept  json
+		// @Produce  json
+		// @Param   guildID   path    string  true  "Discord Guild ID"
+		// @Success 200 {array} object "List of guild channels"
+		// @Failure 400 {object} ErrorResponse "Invalid guild ID or error fetching channels"
+		// @Failure 501 {object} ErrorResponse "No worker in the network offers the work type, the TEE worker has no Discord jobs yet"
+		// @Router /data/discord/guilds/{guildID}/channels [get]
+		v1.GET("/data/discord/guilds/:guildID/channels", API.SearchDiscordGuildChannels())
+
+		// @Summary Search Discord User Guilds
+		// @Description Retrieves the guilds the worker's Discord bot is a member of
+		// @Tags Discord
+		// @Accept  json
+		// @Produce  json
+		// @Success 200 {array} object "List of guilds"
+		// @Failure 400 {object} ErrorResponse "Error fetching guilds"
+		// @Failure 501 {object} ErrorResponse "No worker in the network offers the work type, the TEE worker has no Discord jobs yet"
+		// @Router /data/discord/user/guilds [get]
+		v1.GET("/data/discord/user/guilds", API.SearchDiscordUserGuilds())
+
+		// @Summary Search Telegram Channel Messages
+		// @Description Retrieves messages from a Telegram channel, paging back by message ID and optionally restricted to a date range
+		// @Tags Telegram
+		// @Accept  json
+		// @Produce  json
+		// @Param   body   body    object  true  "Channel Messages Request"  example({"username": "masafinance", "limit": 50, "offsetID": 1200, "since": "2024-01-01T00:00:00Z", "until": "2024-01-31T00:00:00Z"})
+		// @Success 200 {array} object "List of channel messages"
+		// @Failure 400 {object} ErrorResponse "Invalid request or error fetching messages"
+		// @Failure 501 {object} ErrorResponse "No worker in the network offers the work type, the TEE worker has no Telegram jobs yet"
+		// @Router /data/telegram/channel/messages [post]
+		v1.POST("/data/telegram/channel/messages", API.SearchTelegramChannelMessages())
+
+		// @Summary Batch Data Request
+		// @Description Runs many work items of any mix of work types concurrently and returns the result of each. Batches larger than the stream threshold, or requested with "Accept: application/x-ndjson", are answered with one NDJSON line per item in completion order
+		// @Tags Data
+		// @Accept  json
+		// @Produce  json
+		// @Produce  application/x-ndjson
+		// @Param   batch   body    object  true  "Batch Request"  example({"parallelism": 10, "items": [{"id": "masa", "workType": "twitter", "data": {"query": "#masa", "count": 10}}, {"workType": "web", "data": {"url": "https://masa.ai"}}]})
+		// @Success 200 {array} BatchItemResult "Per-item results"
+		// @Failure 400 {object} ErrorResponse "Invalid request body"
+		// @Failure 413 {object} ErrorResponse "Too many items"
+		// @Router /data/batch [post]
+		v1.POST("/data/batch", API.SubmitBatch())
+
+		// @Summary Query Stored Results
+		// @Description Lists the results kept in the node's result store, newest first. Data is unsealed for the client that requested it unless the X-Masa-Sealed header is true, and returned in a sealed result issued to its requester otherwise
+		// @Tags Results
+		// @Produce  json
+		// @Param   workType   query   string  false  "Work type"
+		// @Param   worker     query   string  false  "Peer ID of the worker that produced the result"
+		// @Param   q          query   string  false  "Text the request has to contain"
+		// @Param   from       query   string  false  "Stored at or after, RFC 3339"
+		// @Param   to         query   string  false  "Stored before, RFC 3339"
+		// @Param   limit      query   int     false  "Maximum number of results, 100 by default"
+		// @Success 200 {array} StoredResultResponse "Stored results"
+		// @Failure 400 {object} ErrorResponse "Invalid query"
+		// @Failure 404 {object} ErrorResponse "The result store is not enabled"
+		// @Router /results [get]
+		v1.GET("/results", API.QueryResults())
+
+		// @Summary Get Stored Result
+		// @Description Retrieves the stored result of a request by its request ID. Data is unsealed for the client that requested it unless the X-Masa-Sealed header is true, and returned in a sealed result issued to its requester otherwise
+		// @Tags Results
+		// @Produce  json
+		// @Param   id   path    string  true  "Request ID"
+		// @Success 200 {object} StoredResultResponse "Stored result"
+		// @Failure 404 {object} ErrorResponse "Result not found or the result store is not enabled"
+		// @Router /results/{id} [get]
+		v1.GET("/results/:id", API.GetStoredResult())
+
+		// @Summary Export Results
+		// @Description Streams the results kept in the node's export spool, oldest first, as a JSON lines, CSV or Parquet file. Only the results the client requested are unsealed. CSV and Parquet exports of twitter or web results have one row per tweet or page section, leaving out sealed results. The X-Masa-Export-Cursor response header resumes the next export after this one, once the X-Masa-Export-Status trailer says it is complete
+		// @Tags Results
+		// @Produce  application/x-ndjson,text/csv,application/vnd.apache.parquet
+		// @Param   format     query   string  false  "jsonl (default), csv or parquet"
+		// @Param   workType   query   string  false  "Work type"
+		// @Param   from       query   string  false  "Stored at or after, RFC 3339"
+		// @Param   to         query   string  false  "Stored before, RFC 3339"
+		// @Param   cursor     query   string  false  "X-Masa-Export-Cursor of the previous export"
+		// @Success 200 {file} file "Exported results"
+		// @Failure 400 {object} ErrorResponse "Invalid query or cursor"
+		// @Failure 404 {object} ErrorResponse "The export spool is not enabled"
+		// @Router /export [get]
+		v1.GET("/export", API.ExportResults())
+
+		// @Summary Unseal Result
+		// @Description Decrypts a result that was kept sealed with the X-Masa-Sealed header or the sealed job option. Only results issued by this node are unsealed, and only for the client that requested them
+		// @Tags Data
+		// @Accept  json
+		// @Produce  json
+		// @Param   sealed   body    object  true  "Sealed result, as returned in the data of a sealed response"
+		// @Success 200 {object} object "Unsealed data"
+		// @Failure 400 {object} ErrorResponse "Invalid or unsupported sealed result"
+		// @Failure 403 {object} ErrorResponse "Sealed result issued by another node, to another client or to a client without an API token"
+		// @Failure 401 {object} ErrorResponse "An API token is required to unseal results"
+		// @Failure 502 {object} ErrorResponse "The TEE could not unseal the result"
+		// @Router /unseal [post]
+		v1.POST("/unseal", API.UnsealResult())
+
+		// @Summary Submit Job
+		// @Description Queues a work request for asynchronous execution and returns its job ID
+		// @Tags Jobs
+		// @Accept  json
+		// @Produce  json
+		// @Param   job   body    object  true  "Job Request"  example({"workType": "web", "data": {"url": "https://hedgey.finance/", "depth": 1}})
+		// @Success 202 {object} JobResponse "Job queued"
+		// @Failure 400 {object} ErrorResponse "Invalid work type or data"
+		// @Failure 503 {object} ErrorResponse "Job queue is full"
+		// @Router /jobs [post]
+		v1.POST("/jobs", API.SubmitJob())
+
+		// @Summary List Dead-Lettered Jobs
+		// @Description Retrieves the jobs that did not finish within the maximum number of delivery attempts, oldest first
+		// @Tags Jobs
+		// @Produce  json
+		// @Success 200 {array} Job "Dead-lettered jobs"
+		// @Router /jobs/dead-letter [get]
+		v1.GET("/jobs/dead-letter", API.GetDeadLetterJobs())
+
+		// @Summary Requeue Job
+		// @Description Queues a dead-lettered job again, with its delivery attempts reset
+		// @Tags Jobs
+		// @Produce  json
+		// @Param   id   path    string  true  "Job ID"
+		// @Success 202 {object} JobResponse "Job queued"
+		// @Failure 404 {object} ErrorResponse "Job not found"
+		// @Failure 409 {object} ErrorResponse "Job is not dead-lettered"
+		// @Router /jobs/{id}/requeue [post]
+		v1.POST("/jobs/:id/requeue", API.RequeueJob())
+
+		// @Summary Get Job Status
+		// @Description Retrieves the status of an asynchronous job
+		// @Tags Jobs
+		// @Accept  json
+		// @Produce  json
+		// @Param   id   path    string  true  "Job ID"
+		// @Success 200 {object} Job "Job status"
+		// @Failure 404 {object} ErrorResponse "Job not found"
+		// @Router /jobs/{id} [get]
+		v1.GET("/jobs/:id", API.GetJob())
+
+		// @Summary Get Job Result
+		// @Description Retrieves the work response of a finished asynchronous job
+		// @Tags Jobs
+		// @Accept  json
+		// @Produce  json
+		// @Param   id   path    string  true  "Job ID"
+		// @Success 200 {object} WorkResponse "Job result"
+		// @Success 202 {object} JobResponse "Job is still pending"
+		// @Failure 404 {object} ErrorResponse "Job not found"
+		// @Router /jobs/{id}/result [get]
+		v1.GET("/jobs/:id/result", API.GetJobResult())
+
+		// @Summary Create Schedule
+		// @Description Creates a schedule that submits a work request as a job whenever its cron expression fires, and delivers the results to a sink: the node's store (default), a JSON lines file in the schedule results directory, or a webhook, which has to be public unless its host is listed in WEBHOOK_ALLOWED_HOSTS
+		// @Tags Schedules
+		// @Accept  json
+		// @Produce  json
+		// @Param   schedule   body    object  true  "Schedule"  example({"name": "masa tweets", "cron": "*/15 * * * *", "workType": "twitter", "payload": {"query": "$MASA", "count": 50}, "sink": {"type": "webhook", "url": "https://example.com/hook"}})
+		// @Success 201 {object} Schedule "Schedule created"
+		// @Failure 400 {object} ErrorResponse "Invalid schedule"
+		// @Router /schedules [post]
+		v1.POST("/schedules", API.CreateSchedule())
+
+		// @Summary List Schedules
+		// @Description Retrieves every schedule with its next run and the status of its last run, oldest first
+		// @Tags Schedules
+		// @Produce  json
+		// @Success 200 {array} Schedule "Schedules"
+		// @Router /schedules [get]
+		v1.GET("/schedules", API.ListSchedules())
+
+		// @Summary Get Schedule
+		// @Description Retrieves a schedule with its next run and the status of its last run
+		// @Tags Schedules
+		// @Produce  json
+		// @Param   id   path    string  true  "Schedule ID"
+		// @Success 200 {object} Schedule "Schedule"
+		// @Failure 404 {object} ErrorResponse "Schedule not found"
+		// @Router /schedules/{id} [get]
+		v1.GET("/schedules/:id", API.GetSchedule())
+
+		// @Summary Pause Schedule
+		// @Description Stops a schedule from starting new runs, a run in progress is still finished and delivered
+		// @Tags Schedules
+		// @Produce  json
+		// @Param   id   path    string  true  "Schedule ID"
+		// @Success 200 {object} Schedule "Schedule paused"
+		// @Failure 404 {object} ErrorResponse "Schedule not found"
+		// @Router /schedules/{id}/pause [post]
+		v1.POST("/schedules/:id/pause", API.PauseSchedule())
+
+		// @Summary Resume Schedule
+		// @Description Resumes a paused schedule, which next runs when its cron expression next fires
+		// @Tags Schedules
+		// @Produce  json
+		// @Param   id   path    string  true  "Schedule ID"
+		// @Success 200 {object} Schedule "Schedule resumed"
+		// @Failure 404 {object} ErrorResponse "Schedule not found"
+		// @Router /schedules/{id}/resume [post]
+		v1.POST("/schedules/:id/resume", API.ResumeSchedule())
+
+		// @Summary Delete Schedule
+		// @Description Deletes a schedule and the results it stored
+		// @Tags Schedules
+		// @Produce  json
+		// @Param   id   path    string  true  "Schedule ID"
+		// @Success 204 "Schedule deleted"
+		// @Failure 404 {object} ErrorResponse "Schedule not found"
+		// @Router /schedules/{id} [delete]
+		v1.DELETE("/schedules/:id", API.DeleteSchedule())
+
+		// @Summary List Schedule Results
+		// @Description Retrieves the results a schedule with a store sink delivered, newest first
+		// @Tags Schedules
+		// @Produce  json
+		// @Param   id      path    string  true   "Schedule ID"
+		// @Param   limit   query   int     false  "Maximum number of results, 20 by default"
+		// @Success 200 {array} ScheduleResult "Schedule results"
+		// @Failure 404 {object} ErrorResponse "Schedule not found"
+		// @Router /schedules/{id}/results [get]
+		v1.GET("/schedules/:id/results", API.GetScheduleResults())
+
+		// @Summary List Request Schemas
+		// @Description Retrieves the JSON Schema of the request data of every work type, keyed by work type
+		// @Tags Schemas
+		// @Produce  json
+		// @Success 200 {object} map[string]JSONSchema "Request schemas"
+		// @Router /schemas [get]
+		v1.GET("/schemas", API.GetRequestSchemas())
+
+		// @Summary Get Request Schema
+		// @Description Retrieves the JSON Schema of the request data of a work type
+		// @Tags Schemas
+		// @Produce  json
+		// @Param   workType   path    string  true  "Work Type"
+		// @Success 200 {object} JSONSchema "Request schema"
+		// @Failure 404 {object} ErrorResponse "Unknown work type"
+		// @Router /schemas/{workType} [get]
+		v1.GET("/schemas/:workType", API.GetRequestSchema())
+
+		// @Summary Result Cache Statistics
+		// @Description Retrieves the hit, miss and coalesced request counters of the result cache
+		// @Tags Data
+		// @Produce  json
+		// @Success 200 {object} CacheStats "Result cache statistics"
+		// @Router /cache/stats [get]
+		v1.GET("/cache/stats", API.GetCacheStats())
+
+		// @Summary Worker Circuit Breakers
+		// @Description Retrieves the circuit breaker state of every remote worker that failed since its last success, workers that are not listed are closed
+		// @Tags Workers
+		// @Produce  json
+		// @Success 200 {array} BreakerStatus "Circuit breakers"
+		// @Router /workers/breakers [get]
+		v1.GET("/workers/breakers", API.GetCircuitBreakers())
+
+		// @Summary List Work Handler Crashes
+		// @Description Retrieves the most recent work handler crashes, newest first, with their stack traces
+		// @Tags Admin
+		// @Produce  json
+		// @Param   workType   query   string  false  "Only list crashes of this work type"
+		// @Success 200 {array} Crash "Handler crashes"
+		// @Failure 401 {object} ErrorResponse "API token required"
+		// @Router /admin/crashes [get]
+		admin := v1.Group("/admin", requireToken())
+		admin.GET("/crashes", API.GetCrashes())
+
+		// @Summary List Work Handlers
+		// @Description Retrieves the work handlers of this node, whether they are enabled and their call and crash counts
+		// @Tags Admin
+		// @Produce  json
+		// @Success 200 {array} HandlerStatus "Work handlers"
+		// @Failure 401 {object} ErrorResponse "API token required"
+		// @Router /admin/handlers [get]
+		admin.GET("/handlers", API.GetWorkHandlers())
+
+		// @Summary Enable Work Handler
+		// @Description Re-enables a work handler that was disabled after crashing repeatedly, so the node advertises the capability again
+		// @Tags Admin
+		// @Produce  json
+		// @Param   workType   path    string  true  "Work Type"
+		// @Success 200 {object} map[string]interface{} "Handler enabled"
+		// @Failure 401 {object} ErrorResponse "API token required"
+		// @Failure 404 {object} ErrorResponse "No handler for this work type"
+		// @Router /admin/handlers/{workType}/enable [post]
+		admin.POST("/handlers/:workType/enable", API.EnableWorkHandler())
+
 		// @Summary Get DHT Data
 		// @Description Retrieves data from the DHT (Distributed Hash Table)
 		// @Tags DHT
@@ -242,7 +540,7 @@ func SetupRoutes(node *node.OracleNode, workerManager *workers.WorkHandlerManage
 		v1.POST("/dht", API.PostToDHT())
 
 		// @Summary Node Data
-		// @Description Retrieves data from the node
+		// @Description Retrieves data from the node, including its per-category reliability record for each known node
 		// @Tags Node
 		// @Accept  json
 		// @Produce  json
@@ -252,7 +550,7 @@ func SetupRoutes(node *node.OracleNode, workerManager *workers.WorkHandlerManage
 		v1.GET("/node/data", API.GetNodeDataHandler())
 
 		// @Summary Get Node Data by Peer ID
-		// @Description Retrieves data for a specific node identified by peer ID
+		// @Description Retrieves data for a specific node identified by peer ID, including this node's per-category reliability record for it
 		// @Tags Node
 		// @Accept  json
 		// @Produce  json
@@ -365,7 +663,7 @@ func SetupRoutes(node *node.OracleNode, workerManager *workers.WorkHandlerManage
 	router.GET("/chat", API.ChatPageHandler())
 
 	// @Summary Get Node API Key
-	// @Description Retrieves the API key for the node
+	// @Description Retrieves an API token for the node. Requests with a valid token get a renewed token for the same client
 	// @Tags Authentication
 	// @Accept  json
 	// @Produce  json
@@ -374,6 +672,14 @@ func SetupRoutes(node *node.OracleNode, workerManager *workers.WorkHandlerManage
 	// @Router /auth [get]
 	router.GET("/auth", API.GetNodeApiKey())
 
+	// @Summary Prometheus Metrics
+	// @Description Exposes work, dispatch, network, node tracker and chain metrics in the Prometheus text format
+	// @Tags Health
+	// @Produce  plain
+	// @Success 200 {string} string "Metrics in the Prometheus text format"
+	// @Router /metrics [get]
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// @Summary Health Check
 	// @Description Checks the health status of the API
 	// @Tags Health
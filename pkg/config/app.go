@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/masa-finance/masa-oracle/internal/versioning"
 	"github.com/masa-finance/masa-oracle/pkg/masacrypto"
@@ -49,16 +50,29 @@ type AppConfig struct {
 	Faucet               bool     `mapstructure:"faucet"`
 
 	// These may be moved to a separate struct
-	TwitterCookiesPath string `mapstructure:"twitterCookiesPath"`
-	TwitterUsername    string `mapstructure:"twitterUsername"`
-	TwitterPassword    string `mapstructure:"twitterPassword"`
-	Twitter2FaCode     string `mapstructure:"twitter2FaCode"`
-	DiscordBotToken    string `mapstructure:"discordBotToken"`
-	TwitterScraper     bool   `mapstructure:"twitterScraper"`
-	DiscordScraper     bool   `mapstructure:"discordScraper"`
-	TelegramScraper    bool   `mapstructure:"telegramScraper"`
-	WebScraper         bool   `mapstructure:"webScraper"`
-	APIEnabled         bool   `mapstructure:"api_enabled"`
+	TwitterCookiesPath      string        `mapstructure:"twitterCookiesPath"`
+	TwitterUsername         string        `mapstructure:"twitterUsername"`
+	TwitterPassword         string        `mapstructure:"twitterPassword"`
+	Twitter2FaCode          string        `mapstructure:"twitter2FaCode"`
+	DiscordBotToken         string        `mapstructure:"discordBotToken"`
+	TwitterScraper          bool          `mapstructure:"twitterScraper"`
+	DiscordScraper          bool          `mapstructure:"discordScraper"`
+	TelegramScraper         bool          `mapstructure:"telegramScraper"`
+	WebScraper              bool          `mapstructure:"webScraper"`
+	APIEnabled              bool          `mapstructure:"api_enabled"`
+	WorkerSelection         string        `mapstructure:"workerSelection"`
+	WorkerConcurrency       string        `mapstructure:"workerConcurrency"`
+	JobExecutors            string        `mapstructure:"jobExecutors"`
+	JobFixturesDir          string        `mapstructure:"jobFixturesDir"`
+	AttestationMeasurements string        `mapstructure:"attestationMeasurements"`
+	RequireAttestation      string        `mapstructure:"requireAttestation"`
+	ResultStore             bool          `mapstructure:"resultStore"`
+	ResultStoreMaxAge       time.Duration `mapstructure:"resultStoreMaxAge"`
+	ResultStoreMaxSizeMB    int           `mapstructure:"resultStoreMaxSizeMB"`
+	ExportSpool             bool          `mapstructure:"exportSpool"`
+	ExportSpoolMaxAge       time.Duration `mapstructure:"exportSpoolMaxAge"`
+	ExportSpoolMaxSizeMB    int           `mapstructure:"exportSpoolMaxSizeMB"`
+	WebhookAllowedHosts     string        `mapstructure:"webhookAllowedHosts"`
 
 	KeyManager   *masacrypto.KeyManager
 	TelegramStop bg.StopFunc
@@ -193,6 +207,19 @@ func (c *AppConfig) setCommandLineConfig() error {
 	pflag.BoolVar(&c.Faucet, "faucet", viper.GetBool(Faucet), "Faucet")
 	pflag.BoolVar(&c.APIEnabled, "api-enabled", viper.GetBool(APIEnabled), "Enable API server")
 	pflag.StringVar(&c.APIListenAddress, "api-port", viper.GetString(APIListenAddress), "API Listening address")
+	pflag.StringVar(&c.WorkerSelection, "workerSelection", viper.GetString(WorkerSelection), "Comma-separated worker selection strategy per category, e.g. twitter=reliability,web=least-loaded")
+	pflag.StringVar(&c.WorkerConcurrency, "workerConcurrency", viper.GetString(WorkerConcurrency), "Comma-separated maximum number of concurrent requests per work type, e.g. twitter=2,web=8")
+	pflag.StringVar(&c.JobExecutors, "jobExecutors", viper.GetString(JobExecutors), "Comma-separated job executor per work type (remote, fixture or local), e.g. web=local; unlisted work types use the remote TEE worker")
+	pflag.StringVar(&c.JobFixturesDir, "jobFixturesDir", viper.GetString(JobFixturesDir), "Directory the fixture job executor loads recorded results from, defaults to fixtures in the masa directory")
+	pflag.StringVar(&c.AttestationMeasurements, "attestationMeasurements", viper.GetString(AttestationMeasurements), "Comma-separated hex measurements of the enclaves whose attested results are trusted")
+	pflag.StringVar(&c.RequireAttestation, "requireAttestation", viper.GetString(RequireAttestation), "Comma-separated work types whose results are rejected unless their attestation is verified, e.g. twitter,web")
+	pflag.BoolVar(&c.ResultStore, "resultStore", viper.GetBool(ResultStore), "Keep successful results in the local result store, where they can be queried through the API")
+	pflag.DurationVar(&c.ResultStoreMaxAge, "resultStoreMaxAge", viper.GetDuration(ResultStoreMaxAge), "How long the result store keeps results, defaults to 7 days")
+	pflag.IntVar(&c.ResultStoreMaxSizeMB, "resultStoreMaxSizeMB", viper.GetInt(ResultStoreMaxSizeMB), "Size in MB the result store is pruned down to, defaults to 1024")
+	pflag.StringVar(&c.WebhookAllowedHosts, "webhookAllowedHosts", viper.GetString(WebhookAllowedHosts), "Comma-separated internal hosts, IPs and CIDR networks that schedule webhooks may be sent to")
+	pflag.BoolVar(&c.ExportSpool, "exportSpool", viper.GetBool(ExportSpool), "Keep successful results in the export spool, from which they are exported through the API or masa-node export")
+	pflag.DurationVar(&c.ExportSpoolMaxAge, "exportSpoolMaxAge", viper.GetDuration(ExportSpoolMaxAge), "How long the export spool keeps results, defaults to 30 days")
+	pflag.IntVar(&c.ExportSpoolMaxSizeMB, "exportSpoolMaxSizeMB", viper.GetInt(ExportSpoolMaxSizeMB), "Size in MB the export spool is pruned down to, defaults to 1024")
 
 	pflag.Parse()
 
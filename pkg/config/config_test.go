@@ -39,28 +39,29 @@ var _ = Describe("InitOptions", func() {
 		actual.MasaProtocolHandlers = nil
 
 		expected := node.NodeOption{
-			IsStaked:             true,
-			UDP:                  conf.UDP,
-			TCP:                  conf.TCP,
-			IsValidator:          conf.Validator,
-			PortNbr:              conf.PortNbr,
-			IsTwitterScraper:     conf.TwitterScraper,
-			IsDiscordScraper:     conf.DiscordScraper,
-			IsTelegramScraper:    conf.TelegramScraper,
-			IsWebScraper:         conf.WebScraper,
-			Bootnodes:            conf.Bootnodes,
-			RandomIdentity:       false,
-			ProtocolHandlers:     nil,
-			Environment:          conf.Environment,
-			Version:              conf.Version,
-			MasaDir:              conf.MasaDir,
-			CachePath:            conf.CachePath,
-			OracleProtocol:       OracleProtocol,
-			NodeDataSyncProtocol: NodeDataSyncProtocol,
-			NodeGossipTopic:      NodeGossipTopic,
-			Rendezvous:           Rendezvous,
-			WorkerProtocol:       WorkerProtocol,
-			PageSize:             PageSize,
+			IsStaked:               true,
+			UDP:                    conf.UDP,
+			TCP:                    conf.TCP,
+			IsValidator:            conf.Validator,
+			PortNbr:                conf.PortNbr,
+			IsTwitterScraper:       conf.TwitterScraper,
+			IsDiscordScraper:       conf.DiscordScraper,
+			IsTelegramScraper:      conf.TelegramScraper,
+			IsWebScraper:           conf.WebScraper,
+			Bootnodes:              conf.Bootnodes,
+			RandomIdentity:         false,
+			ProtocolHandlers:       nil,
+			Environment:            conf.Environment,
+			Version:                conf.Version,
+			MasaDir:                conf.MasaDir,
+			CachePath:              conf.CachePath,
+			OracleProtocol:         OracleProtocol,
+			NodeDataSyncProtocol:   NodeDataSyncProtocol,
+			NodeGossipTopic:        NodeGossipTopic,
+			Rendezvous:             Rendezvous,
+			WorkerProtocol:         WorkerProtocol,
+			WorkerEnvelopeProtocol: WorkerEnvelope,
+			PageSize:               PageSize,
 
 			// Set these to the same values we set above
 			Services:             actual.Services,
@@ -23,6 +23,7 @@ const (
 
 	OracleProtocol       = "oracle_protocol"
 	WorkerProtocol       = "worker_protocol"
+	WorkerEnvelope       = "worker_envelope"
 	NodeDataSyncProtocol = "nodeDataSync"
 	NodeGossipTopic      = "gossip"
 	PublicKeyTopic       = "bootNodePublicKey"
@@ -31,15 +32,28 @@ const (
 	Rendezvous           = "masa-mdns"
 	PageSize             = 25
 
-	TwitterUsername    = "TWITTER_USERNAME"
-	TwitterPassword    = "TWITTER_PASSWORD"
-	Twitter2FaCode     = "TWITTER_2FA_CODE"
-	DiscordBotToken    = "DISCORD_BOT_TOKEN"
-	TwitterScraper     = "TWITTER_SCRAPER"
-	DiscordScraper     = "DISCORD_SCRAPER"
-	TelegramScraper    = "TELEGRAM_SCRAPER"
-	WebScraper         = "WEB_SCRAPER"
-	APIEnabled         = "API_ENABLED"
-	APIListenAddress   = "API_LISTEN_ADDRESS"
-	DefaultPrivKeyFile = "masa_oracle_key"
+	TwitterUsername         = "TWITTER_USERNAME"
+	TwitterPassword         = "TWITTER_PASSWORD"
+	Twitter2FaCode          = "TWITTER_2FA_CODE"
+	DiscordBotToken         = "DISCORD_BOT_TOKEN"
+	TwitterScraper          = "TWITTER_SCRAPER"
+	DiscordScraper          = "DISCORD_SCRAPER"
+	TelegramScraper         = "TELEGRAM_SCRAPER"
+	WebScraper              = "WEB_SCRAPER"
+	APIEnabled              = "API_ENABLED"
+	APIListenAddress        = "API_LISTEN_ADDRESS"
+	WorkerSelection         = "WORKER_SELECTION"
+	WorkerConcurrency       = "WORKER_CONCURRENCY"
+	JobExecutors            = "JOB_EXECUTORS"
+	JobFixturesDir          = "JOB_FIXTURES_DIR"
+	AttestationMeasurements = "ATTESTATION_MEASUREMENTS"
+	RequireAttestation      = "REQUIRE_ATTESTATION"
+	ResultStore             = "RESULT_STORE"
+	ResultStoreMaxAge       = "RESULT_STORE_MAX_AGE"
+	ResultStoreMaxSizeMB    = "RESULT_STORE_MAX_SIZE_MB"
+	ExportSpool             = "EXPORT_SPOOL"
+	ExportSpoolMaxAge       = "EXPORT_SPOOL_MAX_AGE"
+	ExportSpoolMaxSizeMB    = "EXPORT_SPOOL_MAX_SIZE_MB"
+	WebhookAllowedHosts     = "WEBHOOK_ALLOWED_HOSTS"
+	DefaultPrivKeyFile      = "masa_oracle_key"
 )
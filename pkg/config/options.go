@@ -21,9 +21,30 @@ func WithConstantOptions(nodes ...node.Option) []node.Option {
 }
 
 func InitOptions(cfg *AppConfig) ([]node.Option, *workers.WorkHandlerManager, *pubsub.PublicKeySubscriptionHandler) {
+	fixturesDir := cfg.JobFixturesDir
+	if fixturesDir == "" {
+		fixturesDir = cfg.MasaDir + "/fixtures"
+	}
+
 	// WorkerManager configuration
 	workerManagerOptions := []workers.WorkerOptionFunc{
 		workers.WithMasaDir(cfg.MasaDir),
+		workers.WithWorkerSelection(cfg.WorkerSelection),
+		workers.WithWorkerConcurrency(cfg.WorkerConcurrency),
+		workers.WithJobExecutors(cfg.JobExecutors),
+		workers.WithJobFixturesDir(fixturesDir),
+		workers.WithAttestationMeasurements(cfg.AttestationMeasurements),
+		workers.WithRequiredAttestation(cfg.RequireAttestation),
+		workers.WithResultRetention(cfg.ResultStoreMaxAge, int64(cfg.ResultStoreMaxSizeMB)<<20),
+		workers.WithWebhookAllowlist(cfg.WebhookAllowedHosts),
+		workers.WithExportSpoolRetention(cfg.ExportSpoolMaxAge, int64(cfg.ExportSpoolMaxSizeMB)<<20),
+	}
+
+	if cfg.ResultStore {
+		workerManagerOptions = append(workerManagerOptions, workers.EnableResultStore)
+	}
+	if cfg.ExportSpool {
+		workerManagerOptions = append(workerManagerOptions, workers.EnableExportSpool)
 	}
 
 	cachePath := cfg.CachePath
@@ -42,6 +63,7 @@ func InitOptions(cfg *AppConfig) ([]node.Option, *workers.WorkHandlerManager, *p
 		node.WithCachePath(cachePath),
 		node.WithKeyManager(cfg.KeyManager),
 		node.WithWorkerProtocol(WorkerProtocol),
+		node.WithWorkerEnvelopeProtocol(WorkerEnvelope),
 	)
 
 	if cfg.TwitterScraper {
@@ -50,7 +72,7 @@ func InitOptions(cfg *AppConfig) ([]node.Option, *workers.WorkHandlerManager, *p
 	}
 
 	if cfg.TelegramScraper {
-		// TODO: Telegram scraper is not implemented yet in the worker (?)
+		workerManagerOptions = append(workerManagerOptions, workers.EnableTelegramScraperWorker)
 		masaNodeOptions = append(masaNodeOptions, node.IsTelegramScraper)
 	}
 
@@ -74,6 +96,10 @@ func InitOptions(cfg *AppConfig) ([]node.Option, *workers.WorkHandlerManager, *p
 			WorkerProtocol,
 			workHandlerManager.HandleWorkerStream,
 		),
+		node.WithMasaProtocolHandler(
+			WorkerEnvelope,
+			workHandlerManager.HandleWorkerEnvelopeStream,
+		),
 		node.WithPubSubHandler(PublicKeyTopic, pubKeySub, false),
 		node.WithPubSubHandler(BlockTopic, blockChainEventTracker, true),
 	}...)
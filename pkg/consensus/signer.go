@@ -17,7 +17,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GenerateJWTToken(peerId string) (string, error) {
+// GenerateJWTToken returns an API token of the node for the client identified by subject, which stays the
+// same across the tokens the client is issued.
+func GenerateJWTToken(peerId string, subject string) (string, error) {
 	// Set the expiration time for the token (e.g., 24 hours from now)
 	expirationTime := time.Now().Add(24 * time.Hour)
 
@@ -31,6 +33,7 @@ func GenerateJWTToken(peerId string) (string, error) {
 	claims := jwt.MapClaims{
 		"apiKey": apiKey,
 		"exp":    expirationTime.Unix(),
+		"sub":    subject,
 	}
 
 	// Create a new JWT token with the claims
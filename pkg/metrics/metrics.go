@@ -0,0 +1,99 @@
+// Package metrics holds the Prometheus instruments of the node. They are registered with the default
+// registry, which the API serves at /metrics together with the Go runtime and libp2p metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Namespace prefixes the name of every metric of the node.
+const Namespace = "masa"
+
+// Label values of the execution label.
+const (
+	ExecutionLocal  = "local"
+	ExecutionRemote = "remote"
+)
+
+// Label values of the direction label of PubSubMessages.
+const (
+	DirectionReceived  = "received"
+	DirectionPublished = "published"
+)
+
+// OutcomeSuccess is the outcome label value of work that succeeded. Failed work is labelled with its error code.
+const OutcomeSuccess = "success"
+
+// latencyBuckets cover work taking from a few milliseconds, such as cached results, up to the worker timeouts.
+var latencyBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 45, 60, 120}
+
+var (
+	// WorkRequests counts the work requests distributed by this node, by work type and outcome.
+	WorkRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "work_requests_total",
+		Help:      "Work requests distributed by this node, by work type and outcome.",
+	}, []string{"work_type", "outcome"})
+
+	// WorkRequestDuration observes how long distributed work requests take, retries and fallbacks included.
+	WorkRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "work_request_duration_seconds",
+		Help:      "Time taken by work requests distributed by this node, by work type and outcome.",
+		Buckets:   latencyBuckets,
+	}, []string{"work_type", "outcome"})
+
+	// WorkExecutions counts the attempts at distributed work requests, by whether the work was executed
+	// by this node or sent to a remote worker.
+	WorkExecutions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "work_executions_total",
+		Help:      "Attempts at work requests distributed by this node, by work type, execution (local or remote) and outcome.",
+	}, []string{"work_type", "execution", "outcome"})
+
+	// WorkExecutionDuration observes how long the attempts at distributed work requests take.
+	WorkExecutionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "work_execution_duration_seconds",
+		Help:      "Time taken by attempts at work requests distributed by this node, by work type and execution (local or remote).",
+		Buckets:   latencyBuckets,
+	}, []string{"work_type", "execution"})
+
+	// HandlerExecutions counts the runs of the work handlers of this node, for itself or for other nodes.
+	HandlerExecutions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "work_handler_executions_total",
+		Help:      "Runs of the work handlers of this node, for itself or other nodes, by work type and outcome.",
+	}, []string{"work_type", "outcome"})
+
+	// HandlerDuration observes how long the work handlers of this node take.
+	HandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "work_handler_duration_seconds",
+		Help:      "Time taken by the work handlers of this node, by work type.",
+		Buckets:   latencyBuckets,
+	}, []string{"work_type"})
+
+	// DispatchFailures counts the work requests that failed on a remote worker through its fault, by peer.
+	DispatchFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "dispatch_failures_total",
+		Help:      "Work requests sent to remote workers that failed through the worker's fault, by peer and error code.",
+	}, []string{"peer", "code"})
+
+	// PubSubMessages counts the messages received and published on each pubsub topic.
+	PubSubMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "pubsub_messages_total",
+		Help:      "Messages received and published on pubsub topics, by topic and direction.",
+	}, []string{"topic", "direction"})
+)
+
+// Outcome returns the outcome label value of work that failed with the given error code, or succeeded if code is empty.
+func Outcome(code string) string {
+	if code == "" {
+		return OutcomeSuccess
+	}
+	return code
+}
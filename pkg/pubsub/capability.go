@@ -0,0 +1,82 @@
+package pubsub
+
+import (
+	"slices"
+	"time"
+)
+
+// RateLimit is a limit on how much work of a type a node accepts: Requests within every Period.
+type RateLimit struct {
+	Requests int           `json:"requests"`
+	Period   time.Duration `json:"period"`
+}
+
+// Capability describes a type of work a node can do. Options are the request options its handler
+// supports, so that requests using newer options are only sent to nodes that understand them.
+type Capability struct {
+	WorkerType     string         `json:"workerType"`
+	Category       WorkerCategory `json:"category"`
+	HandlerVersion string         `json:"handlerVersion,omitempty"`
+	MaxConcurrency int            `json:"maxConcurrency,omitempty"`
+	Options        []string       `json:"options,omitempty"`
+	RateLimits     []RateLimit    `json:"rateLimits,omitempty"`
+}
+
+// WorkRequirement is what a node has to be capable of to be sent a work request: the work type, its
+// category for peers that only advertise the legacy capability flags, and the request options in use.
+type WorkRequirement struct {
+	WorkerType string
+	Category   WorkerCategory
+	Options    []string
+}
+
+// SetCapabilities sets the capabilities of the node, and derives the legacy capability flags from them
+// for peers on older protocol versions, which do not read the capability list.
+func (n *NodeData) SetCapabilities(capabilities []Capability) {
+	n.Capabilities = capabilities
+	n.IsDiscordScraper = n.hasCategory(CategoryDiscord)
+	n.IsTelegramScraper = n.hasCategory(CategoryTelegram)
+	n.IsTwitterScraper = n.hasCategory(CategoryTwitter)
+	n.IsWebScraper = n.hasCategory(CategoryWeb)
+}
+
+// Capability returns the capability of the node for the given work type.
+func (n *NodeData) Capability(workerType string) (Capability, bool) {
+	for _, capability := range n.Capabilities {
+		if capability.WorkerType == workerType {
+			return capability, true
+		}
+	}
+	return Capability{}, false
+}
+
+func (n *NodeData) hasCategory(category WorkerCategory) bool {
+	for _, capability := range n.Capabilities {
+		if capability.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
+// CanDo checks if the node can perform work with the given requirement. Nodes that advertise a capability
+// list must list the work type with every option in use; nodes on older protocol versions, which only
+// advertise the legacy flags, are matched by category.
+func (n *NodeData) CanDo(requirement WorkRequirement) bool {
+	if !n.IsStaked {
+		return false
+	}
+	if len(n.Capabilities) == 0 {
+		return n.CanDoWork(requirement.Category)
+	}
+	capability, ok := n.Capability(requirement.WorkerType)
+	if !ok {
+		return false
+	}
+	for _, option := range requirement.Options {
+		if !slices.Contains(capability.Options, option) {
+			return false
+		}
+	}
+	return true
+}
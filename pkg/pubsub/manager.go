@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/masa-finance/masa-oracle/node/types"
+	"github.com/masa-finance/masa-oracle/pkg/metrics"
 
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/host"
@@ -89,6 +90,7 @@ func (sm *Manager) AddSubscription(topicName string, handler types.SubscriptionH
 				}
 				continue
 			}
+			metrics.PubSubMessages.WithLabelValues(topicName, metrics.DirectionReceived).Inc()
 			if !includeSelf {
 				// if !includeSelf && msg.ReceivedFrom == sm.host.ID() {
 				// if msg.ReceivedFrom == sm.host.ID() {
@@ -138,7 +140,11 @@ func (sm *Manager) Publish(topic string, data []byte) error {
 	if !ok {
 		return fmt.Errorf("no topic named %s", topic)
 	}
-	return t.Publish(sm.ctx, data)
+	if err := t.Publish(sm.ctx, data); err != nil {
+		return err
+	}
+	metrics.PubSubMessages.WithLabelValues(topic, metrics.DirectionPublished).Inc()
+	return nil
 }
 
 // GetHandler returns the types.SubscriptionHandler for the given topic name.
@@ -191,18 +197,15 @@ func (sm *Manager) PublishMessage(topicName, message string) error {
 	data := []byte(message)
 
 	// Check if the topic exists
-	t, ok := sm.topics[topicName]
-	if !ok {
+	if _, ok := sm.topics[topicName]; !ok {
 		// Optionally, create the topic if it doesn't exist
-		var err error
-		t, err = sm.createTopic(topicName)
-		if err != nil {
+		if _, err := sm.createTopic(topicName); err != nil {
 			return fmt.Errorf("[-] Failed to create topic %s: %w", topicName, err)
 		}
 	}
 
 	// Use the existing Publish method to publish the message
-	return t.Publish(sm.ctx, data)
+	return sm.Publish(topicName, data)
 }
 
 // Subscribe registers a subscription handler to receive messages for the
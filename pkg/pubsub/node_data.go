@@ -39,35 +39,45 @@ func (m *JSONMultiaddr) UnmarshalJSON(b []byte) error {
 }
 
 type NodeData struct {
-	Multiaddrs           []JSONMultiaddr `json:"multiaddrs,omitempty"`
-	MultiaddrsString     string          `json:"multiaddrsString,omitempty"`
-	PeerId               peer.ID         `json:"peerId"`
-	FirstJoinedUnix      int64           `json:"firstJoined,omitempty"`
-	LastJoinedUnix       int64           `json:"lastJoined,omitempty"`
-	LastLeftUnix         int64           `json:"-"`
-	LastUpdatedUnix      int64           `json:"lastUpdated,omitempty"`
-	CurrentUptime        time.Duration   `json:"uptime,omitempty"`
-	CurrentUptimeStr     string          `json:"uptimeStr,omitempty"`
-	AccumulatedUptime    time.Duration   `json:"accumulatedUptime,omitempty"`
-	AccumulatedUptimeStr string          `json:"accumulatedUptimeStr,omitempty"`
-	EthAddress           string          `json:"ethAddress,omitempty"`
-	Activity             int             `json:"activity,omitempty"`
-	IsActive             bool            `json:"isActive"`
-	IsStaked             bool            `json:"isStaked"`
-	SelfIdentified       bool            `json:"-"`
-	IsValidator          bool            `json:"isValidator"`
-	IsTwitterScraper     bool            `json:"isTwitterScraper"`
-	IsWebScraper         bool            `json:"isWebScraper"`
-	Records              any             `json:"records,omitempty"`
-	Version              string          `json:"version"`
-	WorkerTimeout        time.Time       `json:"workerTimeout,omitempty"`
-	ReturnedTweets       int             `json:"returnedTweets"` // a running count of the number of tweets returned
-	LastReturnedTweet    time.Time       `json:"lastReturnedTweet"`
-	TweetTimeout         bool            `json:"tweetTimeout"`
-	TweetTimeouts        int             `json:"tweetTimeouts"` // a running countthe number of times a tweet request times out
-	LastTweetTimeout     time.Time       `json:"lastTweetTimeout"`
-	LastNotFoundTime     time.Time       `json:"lastNotFoundTime"`
-	NotFoundCount        int             `json:"notFoundCount"` // a running count of the number of times a node is not found
+	Multiaddrs             []JSONMultiaddr `json:"multiaddrs,omitempty"`
+	MultiaddrsString       string          `json:"multiaddrsString,omitempty"`
+	PeerId                 peer.ID         `json:"peerId"`
+	FirstJoinedUnix        int64           `json:"firstJoined,omitempty"`
+	LastJoinedUnix         int64           `json:"lastJoined,omitempty"`
+	LastLeftUnix           int64           `json:"-"`
+	LastUpdatedUnix        int64           `json:"lastUpdated,omitempty"`
+	CurrentUptime          time.Duration   `json:"uptime,omitempty"`
+	CurrentUptimeStr       string          `json:"uptimeStr,omitempty"`
+	AccumulatedUptime      time.Duration   `json:"accumulatedUptime,omitempty"`
+	AccumulatedUptimeStr   string          `json:"accumulatedUptimeStr,omitempty"`
+	EthAddress             string          `json:"ethAddress,omitempty"`
+	Activity               int             `json:"activity,omitempty"`
+	IsActive               bool            `json:"isActive"`
+	IsStaked               bool            `json:"isStaked"`
+	StakeAmount            string          `json:"stakeAmount,omitempty"` // the staked amount in wei, as a decimal string
+	SelfIdentified         bool            `json:"-"`
+	IsValidator            bool            `json:"isValidator"`
+	IsTwitterScraper       bool            `json:"isTwitterScraper"`
+	IsDiscordScraper       bool            `json:"isDiscordScraper"`
+	IsTelegramScraper      bool            `json:"isTelegramScraper"`
+	IsWebScraper           bool            `json:"isWebScraper"`
+	Capabilities           []Capability    `json:"capabilities,omitempty"` // the legacy Is*Scraper flags are derived from it
+	Records                any             `json:"records,omitempty"`
+	Version                string          `json:"version"`
+	WorkerTimeout          time.Time       `json:"workerTimeout,omitempty"`
+	ReturnedTweets         int             `json:"returnedTweets"` // a running count of the number of tweets returned
+	LastReturnedTweet      time.Time       `json:"lastReturnedTweet"`
+	TweetTimeout           bool            `json:"tweetTimeout"`
+	TweetTimeouts          int             `json:"tweetTimeouts"` // a running countthe number of times a tweet request times out
+	LastTweetTimeout       time.Time       `json:"lastTweetTimeout"`
+	LastNotFoundTime       time.Time       `json:"lastNotFoundTime"`
+	NotFoundCount          int             `json:"notFoundCount"`       // a running count of the number of times a node is not found
+	QuorumAgreements       int             `json:"quorumAgreements"`    // a running count of quorum requests where the node returned the agreed result
+	QuorumDisagreements    int             `json:"quorumDisagreements"` // a running count of quorum requests where the node returned divergent data
+	LastQuorumDisagreement time.Time       `json:"lastQuorumDisagreement"`
+	// Reliability is this node's record of how the node performed, by worker category name. It is never
+	// gossiped, so that peers cannot rank themselves first
+	Reliability map[string]Reliability `json:"-"`
 }
 
 // NewNodeData creates a new NodeData struct initialized with the given
@@ -122,11 +132,19 @@ func (wc WorkerCategory) String() string {
 
 // CanDoWork checks if the node can perform work of the specified WorkerType.
 // It returns true if the node is configured for the given worker type, false otherwise.
+// Use CanDo to also match the work type and options against the node's capability list.
 func (n *NodeData) CanDoWork(workerType WorkerCategory) bool {
 	if !n.IsStaked {
 		return false
 	}
+	if len(n.Capabilities) > 0 {
+		return n.hasCategory(workerType)
+	}
 	switch workerType {
+	case CategoryDiscord:
+		return n.IsDiscordScraper
+	case CategoryTelegram:
+		return n.IsTelegramScraper
 	case CategoryTwitter:
 		return n.IsTwitterScraper
 	case CategoryWeb:
@@ -142,6 +160,16 @@ func (n *NodeData) TwitterScraper() bool {
 	return n.IsTwitterScraper
 }
 
+// DiscordScraper checks if the current node is configured as a Discord scraper.
+func (n *NodeData) DiscordScraper() bool {
+	return n.IsDiscordScraper
+}
+
+// TelegramScraper checks if the current node is configured as a Telegram scraper.
+func (n *NodeData) TelegramScraper() bool {
+	return n.IsTelegramScraper
+}
+
 // WebScraper checks if the current node is configured as a Web scraper.
 // It retrieves the configuration instance and returns the value of the WebScraper field.
 func (n *NodeData) WebScraper() bool {
@@ -255,6 +283,29 @@ func (n *NodeData) MergeMultiaddresses(addr multiaddr.Multiaddr) {
 	}
 }
 
+// divergenceWindow is how long a node that returned divergent quorum results stays deprioritized.
+const divergenceWindow = 24 * time.Hour
+
+// RecordQuorumResult records whether the node agreed with the quorum on a result.
+func (nd *NodeData) RecordQuorumResult(agreed bool) {
+	if agreed {
+		nd.QuorumAgreements++
+	} else {
+		nd.QuorumDisagreements++
+		nd.LastQuorumDisagreement = time.Now()
+	}
+	nd.LastUpdatedUnix = time.Now().Unix()
+}
+
+// IsDivergent reports whether the node recently returned data that disagreed with the quorum
+// in at least a fifth of the quorum requests it took part in.
+func (nd *NodeData) IsDivergent() bool {
+	if nd.QuorumDisagreements == 0 || time.Since(nd.LastQuorumDisagreement) > divergenceWindow {
+		return false
+	}
+	return nd.QuorumDisagreements*5 >= nd.QuorumAgreements+nd.QuorumDisagreements
+}
+
 func (nd *NodeData) UpdateTwitterFields(fields NodeData) {
 	if fields.ReturnedTweets != 0 {
 		nd.ReturnedTweets += fields.ReturnedTweets
@@ -141,11 +141,25 @@ func TestNodeData(t *testing.T) {
 				setup:    func() {},
 				expected: false,
 			},
+			{
+				category: CategoryDiscord,
+				setup: func() {
+					nodeData.IsDiscordScraper = true
+				},
+				expected: true,
+			},
 			{
 				category: CategoryTelegram,
 				setup:    func() {},
 				expected: false,
 			},
+			{
+				category: CategoryTelegram,
+				setup: func() {
+					nodeData.IsTelegramScraper = true
+				},
+				expected: true,
+			},
 			{
 				category: CategoryTwitter,
 				setup: func() {
@@ -169,6 +183,29 @@ func TestNodeData(t *testing.T) {
 		}
 	})
 
+	t.Run("SetCapabilities derives the legacy flags", func(t *testing.T) {
+		nodeData := NewNodeData([]multiaddr.Multiaddr{testAddr}, testPeerID, "0x123", ActivityJoined)
+		nodeData.IsStaked = true
+		nodeData.IsDiscordScraper = true
+
+		nodeData.SetCapabilities([]Capability{
+			{WorkerType: "twitter", Category: CategoryTwitter, HandlerVersion: "1.0.0", MaxConcurrency: 4},
+			{WorkerType: "web", Category: CategoryWeb},
+		})
+		assert.True(t, nodeData.IsTwitterScraper)
+		assert.True(t, nodeData.IsWebScraper)
+		assert.False(t, nodeData.IsDiscordScraper)
+		assert.False(t, nodeData.IsTelegramScraper)
+		assert.True(t, nodeData.CanDoWork(CategoryTwitter))
+		assert.False(t, nodeData.CanDoWork(CategoryDiscord))
+
+		capability, ok := nodeData.Capability("twitter")
+		assert.True(t, ok)
+		assert.Equal(t, 4, capability.MaxConcurrency)
+		assert.False(t, nodeData.CanDo(WorkRequirement{WorkerType: "twitter-profile", Category: CategoryTwitter}))
+		assert.True(t, nodeData.CanDo(WorkRequirement{WorkerType: "twitter", Category: CategoryTwitter}))
+	})
+
 	t.Run("GetCurrentUptime with various states", func(t *testing.T) {
 		nodeData := NewNodeData(
 			[]multiaddr.Multiaddr{testAddr},
@@ -217,4 +254,26 @@ func TestNodeData(t *testing.T) {
 		}
 		assert.Equal(t, 3, len(nodeData.Multiaddrs))
 	})
+
+	t.Run("Divergent nodes are deprioritized", func(t *testing.T) {
+		reliable := NodeData{PeerId: peer.ID("reliable")}
+		divergent := NodeData{PeerId: peer.ID("divergent")}
+		divergent.RecordQuorumResult(true)
+		divergent.RecordQuorumResult(false)
+		assert.True(t, divergent.IsDivergent())
+
+		occasional := NodeData{PeerId: peer.ID("occasional")}
+		for i := 0; i < 9; i++ {
+			occasional.RecordQuorumResult(true)
+		}
+		occasional.RecordQuorumResult(false)
+		assert.False(t, occasional.IsDivergent())
+
+		stale := NodeData{PeerId: peer.ID("stale"), QuorumDisagreements: 3, LastQuorumDisagreement: time.Now().Add(-48 * time.Hour)}
+		assert.False(t, stale.IsDivergent())
+
+		nodes := []NodeData{divergent, reliable, occasional, stale}
+		DeprioritizeDivergentNodes(nodes)
+		assert.Equal(t, []peer.ID{"reliable", "occasional", "stale", "divergent"}, []peer.ID{nodes[0].PeerId, nodes[1].PeerId, nodes[2].PeerId, nodes[3].PeerId})
+	})
 }
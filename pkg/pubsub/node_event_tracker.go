@@ -56,6 +56,8 @@ func (s NodeSorter) Less(i, j int) bool { return s.less(s.nodes[i], s.nodes[j])
 //  6. Finally, sorts by PeerId for stability when no performance data is available
 //
 // The function modifies the input slice in-place, sorting the nodes from most to least reliable.
+//
+// Deprecated: use SortNodesByReliability, which ranks the nodes of every category by their decayed track record.
 func SortNodesByTwitterReliability(nodes []NodeData) {
 	now := time.Now()
 	sorter := NodeSorter{
@@ -90,6 +92,14 @@ func SortNodesByTwitterReliability(nodes []NodeData) {
 	sort.Sort(sorter)
 }
 
+// DeprioritizeDivergentNodes moves the nodes that recently returned divergent quorum results to the
+// end of the slice, keeping the relative order of all other nodes. It modifies the input slice in-place.
+func DeprioritizeDivergentNodes(nodes []NodeData) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return !nodes[i].IsDivergent() && nodes[j].IsDivergent()
+	})
+}
+
 // NewNodeEventTracker creates a new NodeEventTracker instance.
 // It initializes the node data map, node data channel, node data file path,
 // connect buffer map. It loads existing node data from file, starts a goroutine
@@ -373,25 +383,18 @@ func GetEthAddress(remotePeer peer.ID, n network.Network) string {
 	return publicKeyHex
 }
 
-// GetEligibleWorkerNodes returns a slice of NodeData for nodes that are eligible to perform a specific category of work.
-func (net *NodeEventTracker) GetEligibleWorkerNodes(category WorkerCategory) []NodeData {
-	logrus.Debugf("Getting eligible worker nodes for category: %s", category)
+// GetEligibleWorkerNodes returns a slice of NodeData for nodes that are eligible to perform work with the given requirement.
+func (net *NodeEventTracker) GetEligibleWorkerNodes(requirement WorkRequirement) []NodeData {
+	logrus.Debugf("Getting eligible worker nodes for %s work of category: %s", requirement.WorkerType, requirement.Category)
 	result := make([]NodeData, 0)
 	for _, nodeData := range net.GetAllNodeData() {
-		if nodeData.CanDoWork(category) {
+		if nodeData.CanDo(requirement) {
 			result = append(result, nodeData)
 		}
 	}
 
-	// Sort the eligible nodes based on the worker category
-	switch category {
-	case CategoryTwitter:
-		SortNodesByTwitterReliability(result)
-		// Add cases for other categories as needed such as
-		// web
-		// discord
-		// telegram
-	}
+	// Sort the eligible nodes by their track record for the worker category
+	SortNodesByReliability(result, requirement.Category)
 
 	return result
 }
@@ -426,8 +429,12 @@ func (net *NodeEventTracker) AddOrUpdateNodeData(nodeData *NodeData, forceGossip
 			nd.SelfIdentified = true
 		}
 		nd.IsStaked = nodeData.IsStaked
+		nd.StakeAmount = nodeData.StakeAmount
 		nd.IsTwitterScraper = nodeData.IsTwitterScraper
+		nd.IsDiscordScraper = nodeData.IsDiscordScraper
+		nd.IsTelegramScraper = nodeData.IsTelegramScraper
 		nd.IsWebScraper = nodeData.IsWebScraper
+		nd.Capabilities = nodeData.Capabilities
 		nd.Records = nodeData.Records
 		nd.Multiaddrs = nodeData.Multiaddrs
 		nd.EthAddress = nodeData.EthAddress
@@ -583,6 +590,9 @@ func (net *NodeEventTracker) cleanupStalePeers(hostId string) {
 	}
 }
 
+// UpdateNodeDataTwitter adds the given Twitter metrics to the node data of the peer and gossips it.
+//
+// Deprecated: use RecordWorkOutcome, which tracks the reliability of every category.
 func (net *NodeEventTracker) UpdateNodeDataTwitter(peerID string, updates NodeData) error {
 	nodeData, exists := net.nodeData.Get(peerID)
 	if !exists {
@@ -599,3 +609,19 @@ func (net *NodeEventTracker) UpdateNodeDataTwitter(peerID string, updates NodeDa
 	}
 	return nil
 }
+
+// UpdateNodeDataQuorum records whether the node with the given peer ID agreed with the quorum on a result.
+func (net *NodeEventTracker) UpdateNodeDataQuorum(peerID string, agreed bool) error {
+	nodeData, exists := net.nodeData.Get(peerID)
+	if !exists {
+		return fmt.Errorf("node data not found for peer ID: %s", peerID)
+	}
+
+	nodeData.RecordQuorumResult(agreed)
+
+	err := net.AddOrUpdateNodeData(nodeData, true)
+	if err != nil {
+		return fmt.Errorf("error updating node data: %v", err)
+	}
+	return nil
+}
@@ -367,3 +367,44 @@ func (c *testConn) IsClosed() bool {
 func (c *testConn) RemotePeer() peer.ID {
 	return c.peerId
 }
+
+func TestGetEligibleWorkerNodesTelegram(t *testing.T) {
+	tracker := NewNodeEventTracker("1.0.0", "test", "host1")
+	testPeerID1, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKC")
+	testPeerID2, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKD")
+
+	tracker.nodeData.Set(testPeerID1.String(), &NodeData{PeerId: testPeerID1, IsStaked: true, IsTelegramScraper: true})
+	tracker.nodeData.Set(testPeerID2.String(), &NodeData{PeerId: testPeerID2, IsStaked: true, IsTwitterScraper: true})
+
+	eligible := tracker.GetEligibleWorkerNodes(WorkRequirement{WorkerType: "telegram-channel-messages", Category: CategoryTelegram})
+	assert.Len(t, eligible, 1)
+	assert.Equal(t, testPeerID1, eligible[0].PeerId)
+}
+
+func TestGetEligibleWorkerNodesCapabilities(t *testing.T) {
+	tracker := NewNodeEventTracker("1.0.0", "test", "host1")
+	legacyPeer, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKC")
+	oldHandlerPeer, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKD")
+	newHandlerPeer, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKE")
+
+	tracker.nodeData.Set(legacyPeer.String(), &NodeData{PeerId: legacyPeer, IsStaked: true, IsTelegramScraper: true})
+	oldHandler := &NodeData{PeerId: oldHandlerPeer, IsStaked: true}
+	oldHandler.SetCapabilities([]Capability{{WorkerType: "telegram-channel-messages", Category: CategoryTelegram, Options: []string{"username", "limit"}}})
+	tracker.nodeData.Set(oldHandlerPeer.String(), oldHandler)
+	newHandler := &NodeData{PeerId: newHandlerPeer, IsStaked: true}
+	newHandler.SetCapabilities([]Capability{{WorkerType: "telegram-channel-messages", Category: CategoryTelegram, Options: []string{"username", "limit", "since"}}})
+	tracker.nodeData.Set(newHandlerPeer.String(), newHandler)
+
+	eligible := tracker.GetEligibleWorkerNodes(WorkRequirement{WorkerType: "telegram-channel-messages", Category: CategoryTelegram, Options: []string{"username"}})
+	assert.Len(t, eligible, 3)
+
+	eligible = tracker.GetEligibleWorkerNodes(WorkRequirement{WorkerType: "telegram-channel-messages", Category: CategoryTelegram, Options: []string{"username", "since"}})
+	peers := make([]peer.ID, 0, len(eligible))
+	for _, nd := range eligible {
+		peers = append(peers, nd.PeerId)
+	}
+	assert.ElementsMatch(t, []peer.ID{legacyPeer, newHandlerPeer}, peers, "legacy peers are matched by category only")
+
+	eligible = tracker.GetEligibleWorkerNodes(WorkRequirement{WorkerType: "discord-profile", Category: CategoryDiscord})
+	assert.Empty(t, eligible)
+}
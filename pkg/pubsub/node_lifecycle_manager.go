@@ -7,6 +7,8 @@ import (
 
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/metrics"
 )
 
 // NodeLifecycleEvent represents a join or leave event
@@ -45,6 +47,7 @@ func BroadcastEvent(ctx context.Context, ps *pubsub.PubSub, topicName string, ev
 		}).Error("[-] Failed to publish node lifecycle event")
 		return err
 	}
+	metrics.PubSubMessages.WithLabelValues(topicName, metrics.DirectionPublished).Inc()
 
 	logrus.WithFields(logrus.Fields{
 		"eventType": event.EventType,
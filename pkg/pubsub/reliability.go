@@ -0,0 +1,152 @@
+package pubsub
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// reliabilityHalfLife is the time after which an outcome counts half as much towards a node's score.
+	reliabilityHalfLife = 30 * time.Minute
+	// latencySamples is the number of most recent response times the latency percentiles are taken from.
+	latencySamples = 50
+	// latencyReference is the 90th percentile response time that halves a node's score.
+	latencyReference = 10 * time.Second
+)
+
+// WorkOutcome is the outcome of sending a work request to a remote node.
+type WorkOutcome int
+
+const (
+	OutcomeSuccess  WorkOutcome = iota // the node answered with data
+	OutcomeFailure                     // the node answered with an error it is at fault for
+	OutcomeTimeout                     // the node did not answer in time
+	OutcomeNotFound                    // the node could not be found in the DHT
+)
+
+// Reliability is a node's track record for a category of work, as observed by this node. The counts decay
+// exponentially with a half-life of reliabilityHalfLife, so recent outcomes weigh more than old ones.
+type Reliability struct {
+	Successes    float64   `json:"successes"`
+	Failures     float64   `json:"failures"`
+	Timeouts     float64   `json:"timeouts"`
+	NotFound     float64   `json:"notFound"`
+	LatencyP50Ms int64     `json:"latencyP50Ms"`
+	LatencyP90Ms int64     `json:"latencyP90Ms"`
+	LatencyP99Ms int64     `json:"latencyP99Ms"`
+	Score        float64   `json:"score"` // as of UpdatedAt, see ScoreAt
+	UpdatedAt    time.Time `json:"updatedAt"`
+
+	latencies []time.Duration
+}
+
+// decayed returns the record with its counts decayed to the given time.
+func (r Reliability) decayed(now time.Time) Reliability {
+	if r.UpdatedAt.IsZero() || !now.After(r.UpdatedAt) {
+		return r
+	}
+	factor := math.Exp2(-float64(now.Sub(r.UpdatedAt)) / float64(reliabilityHalfLife))
+	r.Successes *= factor
+	r.Failures *= factor
+	r.Timeouts *= factor
+	r.NotFound *= factor
+	r.UpdatedAt = now
+	return r
+}
+
+// record returns the record updated with an outcome. latency is only used for successful outcomes.
+func (r Reliability) record(outcome WorkOutcome, latency time.Duration, now time.Time) Reliability {
+	r = r.decayed(now)
+	r.UpdatedAt = now
+	switch outcome {
+	case OutcomeSuccess:
+		r.Successes++
+		// Copy the samples, the record may be shared with copies of the node data
+		samples := append(make([]time.Duration, 0, latencySamples), r.latencies...)
+		if len(samples) >= latencySamples {
+			samples = samples[len(samples)-latencySamples+1:]
+		}
+		r.latencies = append(samples, latency)
+		r.LatencyP50Ms = percentile(r.latencies, 0.50).Milliseconds()
+		r.LatencyP90Ms = percentile(r.latencies, 0.90).Milliseconds()
+		r.LatencyP99Ms = percentile(r.latencies, 0.99).Milliseconds()
+	case OutcomeFailure:
+		r.Failures++
+	case OutcomeTimeout:
+		r.Timeouts++
+	case OutcomeNotFound:
+		r.NotFound++
+	}
+	r.Score = r.score()
+	return r
+}
+
+// ScoreAt returns the score of the record at the given time, between 0 and 1. It is the smoothed success
+// rate, so a node without a track record scores 0.5, scaled down by the 90th percentile latency.
+func (r Reliability) ScoreAt(now time.Time) float64 {
+	return r.decayed(now).score()
+}
+
+func (r Reliability) score() float64 {
+	total := r.Successes + r.Failures + r.Timeouts + r.NotFound
+	successRate := (r.Successes + 1) / (total + 2)
+	latencyFactor := 1 / (1 + float64(r.LatencyP90Ms)/float64(latencyReference.Milliseconds()))
+	return successRate * latencyFactor
+}
+
+// percentile returns the p-th percentile of the samples using the nearest-rank method.
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// ReliabilityScore returns the node's current reliability score for the category, see Reliability.ScoreAt.
+func (n *NodeData) ReliabilityScore(category WorkerCategory, now time.Time) float64 {
+	return n.Reliability[category.String()].ScoreAt(now)
+}
+
+// RecordWorkOutcome updates the reliability of the node with the given peer ID for the category of work
+// with the outcome of a request sent to it. The record is local to this node: NodeData.Reliability is
+// left out of the JSON encoding that is gossiped and synced, and only served by the node data API.
+func (net *NodeEventTracker) RecordWorkOutcome(peerID string, category WorkerCategory, outcome WorkOutcome, latency time.Duration) error {
+	updated := net.nodeData.Update(peerID, func(nodeData *NodeData) {
+		// Replace rather than modify the map, copies of the node data handed out earlier share it
+		reliability := make(map[string]Reliability, len(nodeData.Reliability)+1)
+		for name, record := range nodeData.Reliability {
+			reliability[name] = record
+		}
+		reliability[category.String()] = reliability[category.String()].record(outcome, latency, time.Now())
+		nodeData.Reliability = reliability
+	})
+	if !updated {
+		return fmt.Errorf("node data not found for peer ID: %s", peerID)
+	}
+	return nil
+}
+
+// SortNodesByReliability sorts the given nodes from the highest to the lowest reliability score for the
+// category, by PeerId among nodes with the same score. The function modifies the input slice in-place.
+func SortNodesByReliability(nodes []NodeData, category WorkerCategory) {
+	now := time.Now()
+	scores := make(map[string]float64, len(nodes))
+	for _, nd := range nodes {
+		scores[nd.PeerId.String()] = nd.ReliabilityScore(category, now)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		iScore, jScore := scores[nodes[i].PeerId.String()], scores[nodes[j].PeerId.String()]
+		if iScore != jScore {
+			return iScore > jScore
+		}
+		return nodes[i].PeerId.String() < nodes[j].PeerId.String()
+	})
+}
@@ -0,0 +1,113 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReliability(t *testing.T) {
+	now := time.Now()
+
+	t.Run("no track record", func(t *testing.T) {
+		assert.Equal(t, 0.5, Reliability{}.ScoreAt(now))
+	})
+
+	t.Run("outcomes", func(t *testing.T) {
+		r := Reliability{}
+		r = r.record(OutcomeSuccess, 0, now)
+		r = r.record(OutcomeFailure, 0, now)
+		r = r.record(OutcomeTimeout, 0, now)
+		r = r.record(OutcomeNotFound, 0, now)
+		assert.Equal(t, 1.0, r.Successes)
+		assert.Equal(t, 1.0, r.Failures)
+		assert.Equal(t, 1.0, r.Timeouts)
+		assert.Equal(t, 1.0, r.NotFound)
+		assert.InDelta(t, 2.0/6.0, r.Score, 1e-9)
+	})
+
+	t.Run("decay", func(t *testing.T) {
+		r := Reliability{}
+		for i := 0; i < 10; i++ {
+			r = r.record(OutcomeTimeout, 0, now)
+		}
+		score := r.ScoreAt(now)
+		later := r.ScoreAt(now.Add(reliabilityHalfLife))
+		assert.Greater(t, later, score)
+		assert.InDelta(t, 1.0/7.0, later, 1e-9)
+		assert.InDelta(t, 0.5, r.ScoreAt(now.Add(100*reliabilityHalfLife)), 1e-6)
+	})
+
+	t.Run("latency percentiles", func(t *testing.T) {
+		r := Reliability{}
+		for i := 1; i <= 100; i++ {
+			r = r.record(OutcomeSuccess, time.Duration(i)*time.Second, now)
+		}
+		// Only the most recent samples, 51s to 100s, count
+		assert.Equal(t, int64(75000), r.LatencyP50Ms)
+		assert.Equal(t, int64(95000), r.LatencyP90Ms)
+		assert.Equal(t, int64(100000), r.LatencyP99Ms)
+
+		fast := Reliability{}.record(OutcomeSuccess, 100*time.Millisecond, now)
+		slow := Reliability{}.record(OutcomeSuccess, 10*time.Second, now)
+		assert.Greater(t, fast.Score, slow.Score)
+	})
+}
+
+func TestRecordWorkOutcome(t *testing.T) {
+	tracker := NewNodeEventTracker("1.0.0", "test", "host1")
+	testPeerID, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKC")
+
+	err := tracker.RecordWorkOutcome(testPeerID.String(), CategoryWeb, OutcomeSuccess, time.Second)
+	assert.Error(t, err)
+
+	tracker.nodeData.Set(testPeerID.String(), &NodeData{PeerId: testPeerID})
+	stored, _ := tracker.nodeData.Get(testPeerID.String())
+	before := *stored
+
+	require.NoError(t, tracker.RecordWorkOutcome(testPeerID.String(), CategoryWeb, OutcomeSuccess, time.Second))
+	require.NoError(t, tracker.RecordWorkOutcome(testPeerID.String(), CategoryTwitter, OutcomeTimeout, 0))
+
+	nodeData, _ := tracker.nodeData.Get(testPeerID.String())
+	assert.Equal(t, 1.0, nodeData.Reliability[CategoryWeb.String()].Successes)
+	assert.Equal(t, int64(1000), nodeData.Reliability[CategoryWeb.String()].LatencyP50Ms)
+	assert.Equal(t, 1.0, nodeData.Reliability[CategoryTwitter.String()].Timeouts)
+	assert.Greater(t, nodeData.ReliabilityScore(CategoryWeb, time.Now()), nodeData.ReliabilityScore(CategoryTwitter, time.Now()))
+	assert.Empty(t, before.Reliability)
+
+	// The record is not gossiped, and peers cannot claim one for themselves
+	encoded, err := json.Marshal(nodeData)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "successes")
+	var forged NodeData
+	require.NoError(t, json.Unmarshal([]byte(`{"reliability": {"web": {"successes": 1e9, "latencyP90Ms": 0}}}`), &forged))
+	assert.Empty(t, forged.Reliability)
+}
+
+func TestSortNodesByReliability(t *testing.T) {
+	testPeerID1, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKC")
+	testPeerID2, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKD")
+	testPeerID3, _ := peer.Decode("QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKE")
+	now := time.Now()
+
+	nodes := []NodeData{
+		{PeerId: testPeerID1, Reliability: map[string]Reliability{
+			CategoryWeb.String(): Reliability{}.record(OutcomeFailure, 0, now),
+		}},
+		{PeerId: testPeerID2},
+		{PeerId: testPeerID3, Reliability: map[string]Reliability{
+			CategoryWeb.String():     Reliability{}.record(OutcomeSuccess, time.Second, now),
+			CategoryTwitter.String(): Reliability{}.record(OutcomeFailure, 0, now),
+		}},
+	}
+
+	SortNodesByReliability(nodes, CategoryWeb)
+	assert.Equal(t, []peer.ID{testPeerID3, testPeerID2, testPeerID1}, []peer.ID{nodes[0].PeerId, nodes[1].PeerId, nodes[2].PeerId})
+
+	SortNodesByReliability(nodes, CategoryTwitter)
+	assert.Equal(t, nodes[2].PeerId, testPeerID3)
+}
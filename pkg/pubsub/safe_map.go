@@ -40,6 +40,18 @@ func (sm *SafeMap) Get(key string) (*NodeData, bool) {
 	return value, ok
 }
 
+// Update calls update with the value associated with the specified key while holding the write lock,
+// so that the change does not race with readers of the SafeMap. It returns false if the key does not exist.
+func (sm *SafeMap) Update(key string, update func(value *NodeData)) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	value, ok := sm.items[key]
+	if ok {
+		update(value)
+	}
+	return ok
+}
+
 // Delete removes the item with the specified key from the SafeMap.
 // It acquires a write lock to ensure thread-safety while deleting the item.
 func (sm *SafeMap) Delete(key string) {
@@ -71,6 +83,8 @@ func (sm *SafeMap) GetStakedNodesSlice() []NodeData {
 		nd.CurrentUptimeStr = PrettyDuration(nd.CurrentUptime)
 		nd.AccumulatedUptimeStr = PrettyDuration(nd.AccumulatedUptime)
 		nd.IsTwitterScraper = nodeData.IsTwitterScraper
+		nd.IsDiscordScraper = nodeData.IsDiscordScraper
+		nd.IsTelegramScraper = nodeData.IsTelegramScraper
 		nd.IsWebScraper = nodeData.IsWebScraper
 		nd.IsValidator = nodeData.IsValidator
 		result = append(result, nd)
@@ -0,0 +1,184 @@
+package tee
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Attestation proves that a result was produced by a genuine enclave. The report of the attestation
+// provider binds the enclave's measurement to its public key, and the signature of that key binds the
+// result to the request it answers, see AttestationBinding.
+type Attestation struct {
+	Provider    string `json:"provider"`    // format of Report, selects the ReportVerifier
+	Measurement string `json:"measurement"` // hex measurement of the enclave, e.g. its MRENCLAVE
+	Report      []byte `json:"report"`      // evidence of the provider, whose report data is the hash of PublicKey
+	PublicKey   []byte `json:"publicKey"`   // ed25519 key of the enclave
+	Signature   []byte `json:"signature"`   // signature of the binding by PublicKey
+	Timestamp   int64  `json:"timestamp"`   // when the result was attested in Unix milliseconds, part of the binding
+}
+
+// AttestationStatus is the outcome of verifying the attestation of a result.
+type AttestationStatus string
+
+const (
+	AttestationVerified  AttestationStatus = "verified"  // the result comes from a trusted enclave
+	AttestationMissing   AttestationStatus = "missing"   // the result has no attestation
+	AttestationInvalid   AttestationStatus = "invalid"   // the report or signature is not valid, or does not cover the result
+	AttestationUntrusted AttestationStatus = "untrusted" // the enclave's measurement is not allowed by the policy
+	AttestationStale     AttestationStatus = "stale"     // the attestation is older than the policy allows
+)
+
+// AttestationProvider produces attestations inside an enclave.
+type AttestationProvider interface {
+	// Attest signs the binding with the enclave key and returns it with the report of the enclave.
+	// The Timestamp is set by the caller.
+	Attest(binding []byte) (*Attestation, error)
+}
+
+// ReportVerifier checks the reports of an attestation provider.
+type ReportVerifier interface {
+	// VerifyReport checks the report and returns the measurement of the enclave and the report data it
+	// binds to it.
+	VerifyReport(report []byte) (measurement string, reportData []byte, err error)
+}
+
+// AttestationPolicy decides which attestations are trusted.
+type AttestationPolicy struct {
+	// Verifiers are the providers whose reports are accepted, by name. Attestations of other providers
+	// are invalid.
+	Verifiers map[string]ReportVerifier
+	// Measurements are the hex measurements of the trusted enclaves. If empty, no enclave is trusted.
+	Measurements []string
+	// MaxAge is how old an attestation may be. If zero, attestations do not expire.
+	MaxAge time.Duration
+	// MaxClockSkew is how far in the future an attestation may be dated.
+	MaxClockSkew time.Duration
+}
+
+// RequestHash returns the hash of a work request that attestations bind results to.
+func RequestHash(workType string, data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(workType))
+	h.Write([]byte{0})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// ResultHash returns the hash of a result that attestations bind requests to. Sealed results are strings
+// and are hashed as is, any other result is hashed in its JSON encoding.
+func ResultHash(data interface{}) ([]byte, error) {
+	var encoded []byte
+	switch v := data.(type) {
+	case string:
+		encoded = []byte(v)
+	case []byte:
+		encoded = v
+	default:
+		var err error
+		if encoded, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	sum := sha256.Sum256(encoded)
+	return sum[:], nil
+}
+
+// AttestationBinding returns the message signed by the enclave for a result, which ties it to the request
+// and the time it was attested at.
+func AttestationBinding(requestHash, resultHash []byte, timestamp int64) []byte {
+	h := sha256.New()
+	h.Write([]byte("masa-attestation-v1"))
+	h.Write(requestHash)
+	h.Write(resultHash)
+	_ = binary.Write(h, binary.BigEndian, timestamp)
+	return h.Sum(nil)
+}
+
+// Attest returns the attestation of the provider for the result of a request at the given time.
+func Attest(provider AttestationProvider, requestHash, resultHash []byte, now time.Time) (*Attestation, error) {
+	timestamp := now.UnixMilli()
+	attestation, err := provider.Attest(AttestationBinding(requestHash, resultHash, timestamp))
+	if err != nil {
+		return nil, err
+	}
+	attestation.Timestamp = timestamp
+	return attestation, nil
+}
+
+// errAttestation annotates an error with the status it leads to.
+type errAttestation struct {
+	status AttestationStatus
+	err    error
+}
+
+func (e *errAttestation) Error() string {
+	return e.err.Error()
+}
+
+func attestationError(status AttestationStatus, format string, args ...interface{}) error {
+	return &errAttestation{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// Verify checks the attestation of the result of a request against the policy at the given time, and
+// returns its status with an error describing why it is not verified.
+func (p AttestationPolicy) Verify(attestation *Attestation, requestHash, resultHash []byte, now time.Time) (AttestationStatus, error) {
+	err := p.verify(attestation, requestHash, resultHash, now)
+	if err == nil {
+		return AttestationVerified, nil
+	}
+	var attErr *errAttestation
+	if errors.As(err, &attErr) {
+		return attErr.status, err
+	}
+	return AttestationInvalid, err
+}
+
+func (p AttestationPolicy) verify(attestation *Attestation, requestHash, resultHash []byte, now time.Time) error {
+	if attestation == nil {
+		return attestationError(AttestationMissing, "the result has no attestation")
+	}
+	verifier, ok := p.Verifiers[attestation.Provider]
+	if !ok {
+		return attestationError(AttestationInvalid, "unsupported attestation provider %q", attestation.Provider)
+	}
+	measurement, reportData, err := verifier.VerifyReport(attestation.Report)
+	if err != nil {
+		return attestationError(AttestationInvalid, "invalid %s report: %v", attestation.Provider, err)
+	}
+	if !strings.EqualFold(measurement, attestation.Measurement) {
+		return attestationError(AttestationInvalid, "the report is for measurement %s, not %s", measurement, attestation.Measurement)
+	}
+	keyHash := sha256.Sum256(attestation.PublicKey)
+	if !bytes.Equal(reportData, keyHash[:]) {
+		return attestationError(AttestationInvalid, "the report does not bind the signing key")
+	}
+	if len(attestation.PublicKey) != ed25519.PublicKeySize ||
+		!ed25519.Verify(attestation.PublicKey, AttestationBinding(requestHash, resultHash, attestation.Timestamp), attestation.Signature) {
+		return attestationError(AttestationInvalid, "the signature does not match the request and result")
+	}
+	if !p.trusts(measurement) {
+		return attestationError(AttestationUntrusted, "measurement %s is not allowed", measurement)
+	}
+	attestedAt := time.UnixMilli(attestation.Timestamp)
+	age := now.Sub(attestedAt)
+	if age < -p.MaxClockSkew || (p.MaxAge > 0 && age > p.MaxAge) {
+		return attestationError(AttestationStale, "the attestation is dated %s", attestedAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (p AttestationPolicy) trusts(measurement string) bool {
+	for _, allowed := range p.Measurements {
+		if strings.EqualFold(strings.TrimSpace(allowed), measurement) {
+			return true
+		}
+	}
+	return false
+}
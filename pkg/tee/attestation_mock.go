@@ -0,0 +1,90 @@
+package tee
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+)
+
+// MockAttestationProvider is the name of the mock attestation provider.
+const MockAttestationProvider = "mock"
+
+// MockAttestor is an AttestationProvider without an enclave, for tests. Its reports are signed by a
+// platform key that stands in for the hardware vendor's, and checked by the verifier returned by Verifier.
+type MockAttestor struct {
+	measurement string
+	platform    ed25519.PrivateKey
+	key         ed25519.PrivateKey
+}
+
+// mockReport is the report of the mock provider.
+type mockReport struct {
+	Measurement string `json:"measurement"`
+	ReportData  []byte `json:"reportData"`
+	Signature   []byte `json:"signature,omitempty"`
+}
+
+// NewMockAttestor returns a mock provider attesting an enclave with the given measurement.
+func NewMockAttestor(measurement string) (*MockAttestor, error) {
+	_, platform, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &MockAttestor{measurement: measurement, platform: platform, key: key}, nil
+}
+
+// Attest implements AttestationProvider.
+func (m *MockAttestor) Attest(binding []byte) (*Attestation, error) {
+	publicKey := m.key.Public().(ed25519.PublicKey)
+	keyHash := sha256.Sum256(publicKey)
+	report := mockReport{Measurement: m.measurement, ReportData: keyHash[:]}
+	unsigned, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	report.Signature = ed25519.Sign(m.platform, unsigned)
+	signed, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	return &Attestation{
+		Provider:    MockAttestationProvider,
+		Measurement: m.measurement,
+		Report:      signed,
+		PublicKey:   publicKey,
+		Signature:   ed25519.Sign(m.key, binding),
+	}, nil
+}
+
+// Verifier returns the verifier of the reports of this provider.
+func (m *MockAttestor) Verifier() ReportVerifier {
+	return mockVerifier{platform: m.platform.Public().(ed25519.PublicKey)}
+}
+
+type mockVerifier struct {
+	platform ed25519.PublicKey
+}
+
+// VerifyReport implements ReportVerifier.
+func (v mockVerifier) VerifyReport(report []byte) (string, []byte, error) {
+	var parsed mockReport
+	if err := json.Unmarshal(report, &parsed); err != nil {
+		return "", nil, err
+	}
+	signature := parsed.Signature
+	parsed.Signature = nil
+	unsigned, err := json.Marshal(parsed)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ed25519.Verify(v.platform, unsigned, signature) {
+		return "", nil, errors.New("the report is not signed by the platform")
+	}
+	return parsed.Measurement, parsed.ReportData, nil
+}
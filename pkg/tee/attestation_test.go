@@ -0,0 +1,81 @@
+package tee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttestationPolicy(t *testing.T) {
+	attestor, err := NewMockAttestor("ab12")
+	require.NoError(t, err)
+	policy := AttestationPolicy{
+		Verifiers:    map[string]ReportVerifier{MockAttestationProvider: attestor.Verifier()},
+		Measurements: []string{"AB12"},
+		MaxAge:       time.Minute,
+		MaxClockSkew: time.Second,
+	}
+
+	now := time.Now()
+	requestHash := RequestHash("web", []byte(`{"url":"https://example.com"}`))
+	resultHash, err := ResultHash("sealed-result")
+	require.NoError(t, err)
+	attestation, err := Attest(attestor, requestHash, resultHash, now)
+	require.NoError(t, err)
+
+	status, err := policy.Verify(attestation, requestHash, resultHash, now)
+	require.NoError(t, err)
+	assert.Equal(t, AttestationVerified, status)
+
+	status, _ = policy.Verify(nil, requestHash, resultHash, now)
+	assert.Equal(t, AttestationMissing, status)
+
+	// The signature covers both the request and the result
+	otherResult, _ := ResultHash("other-result")
+	status, _ = policy.Verify(attestation, requestHash, otherResult, now)
+	assert.Equal(t, AttestationInvalid, status)
+	status, _ = policy.Verify(attestation, RequestHash("twitter", []byte(`{"url":"https://example.com"}`)), resultHash, now)
+	assert.Equal(t, AttestationInvalid, status)
+
+	// So does the timestamp
+	backdated := *attestation
+	backdated.Timestamp = now.Add(time.Second).UnixMilli()
+	status, _ = policy.Verify(&backdated, requestHash, resultHash, now)
+	assert.Equal(t, AttestationInvalid, status)
+
+	status, _ = policy.Verify(attestation, requestHash, resultHash, now.Add(2*time.Minute))
+	assert.Equal(t, AttestationStale, status)
+
+	untrusted := policy
+	untrusted.Measurements = []string{"cd34"}
+	status, _ = untrusted.Verify(attestation, requestHash, resultHash, now)
+	assert.Equal(t, AttestationUntrusted, status)
+
+	// Claiming another measurement than the report's does not help
+	forged := *attestation
+	forged.Measurement = "cd34"
+	status, _ = untrusted.Verify(&forged, requestHash, resultHash, now)
+	assert.Equal(t, AttestationInvalid, status)
+
+	// Reports of another platform are rejected
+	other, err := NewMockAttestor("ab12")
+	require.NoError(t, err)
+	foreign, err := Attest(other, requestHash, resultHash, now)
+	require.NoError(t, err)
+	status, _ = policy.Verify(foreign, requestHash, resultHash, now)
+	assert.Equal(t, AttestationInvalid, status)
+
+	// Providers without a verifier in the policy are not trusted
+	status, _ = AttestationPolicy{Measurements: []string{"ab12"}}.Verify(attestation, requestHash, resultHash, now)
+	assert.Equal(t, AttestationInvalid, status)
+}
+
+func TestResultHash(t *testing.T) {
+	fromString, err := ResultHash(`{"a":1}`)
+	require.NoError(t, err)
+	fromValue, err := ResultHash(map[string]int{"a": 1})
+	require.NoError(t, err)
+	assert.Equal(t, fromString, fromValue)
+}
@@ -1,13 +1,61 @@
 package tee
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"os"
+	"time"
 
 	worker "github.com/masa-finance/tee-worker/pkg/client"
 )
 
-var teeWorkerURL = os.Getenv("TEE_WORKER_URL")
+// teeWorkerURL returns the address of the TEE worker. It is read on every call so it can be changed at runtime.
+func teeWorkerURL() string {
+	return os.Getenv("TEE_WORKER_URL")
+}
+
+// Polling settings used while waiting for a job result, matching the tee-worker client defaults.
+var (
+	resultMaxRetries = 60
+	resultDelay      = time.Second
+)
 
 func NewClient() *worker.Client {
-	return worker.NewClient(teeWorkerURL)
+	return worker.NewClient(teeWorkerURL())
+}
+
+// NewClientWithContext returns a client whose requests are aborted once ctx is done.
+func NewClientWithContext(ctx context.Context) *worker.Client {
+	client := NewClient()
+	client.HTTPClient = &http.Client{Transport: contextTransport{ctx: ctx, next: http.DefaultTransport}}
+	return client
+}
+
+// contextTransport binds every request to a context.
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+// WaitForResult polls the client until the result of the job is ready, the retries are exhausted or ctx is done.
+// Unlike JobResult.Get it stops polling as soon as ctx is cancelled, freeing the worker for other requests.
+func WaitForResult(ctx context.Context, client *worker.Client, job *worker.JobResult) (result string, err error) {
+	for retries := 0; retries < resultMaxRetries; retries++ {
+		var available bool
+		result, available, err = client.GetResult(job.UUID)
+		if err == nil || available {
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("job %s abandoned: %w", job.UUID, ctx.Err())
+		case <-time.After(resultDelay):
+		}
+	}
+	return "", fmt.Errorf("max retries reached: %w", err)
 }
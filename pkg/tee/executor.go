@@ -0,0 +1,75 @@
+package tee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	types "github.com/masa-finance/tee-worker/api/types"
+)
+
+// Names of the job executors, as used to select one per work type.
+const (
+	ExecutorRemote  = "remote"  // the TEE worker at TEE_WORKER_URL
+	ExecutorFixture = "fixture" // recorded results served in-process, for tests and development
+	ExecutorLocal   = "local"   // jobs run in-process without a TEE, for trusted environments
+)
+
+// JobExecutor runs the scraper jobs of the work handlers.
+type JobExecutor interface {
+	// ExecuteJob runs the job and returns its result. It should stop and return as soon as ctx is done.
+	ExecuteJob(ctx context.Context, job types.Job) (string, error)
+	// Sealed reports whether the results are sealed by a TEE, and have to be decrypted by the TEE worker
+	// before use, or plain JSON.
+	Sealed() bool
+	// Supports reports whether the executor can run jobs of the type, so that nodes only take on the work
+	// their executors can do.
+	Supports(jobType string) bool
+}
+
+// ErrUnsupportedJob is returned by executors that cannot run jobs of a type.
+var ErrUnsupportedJob = errors.New("job type not supported by the executor")
+
+// NewExecutor returns the job executor with the given name. fixturesDir is the directory the fixture
+// executor loads its recorded results from.
+func NewExecutor(name string, fixturesDir string) (JobExecutor, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case ExecutorRemote:
+		return RemoteExecutor{}, nil
+	case ExecutorFixture:
+		return NewFixtureExecutor(fixturesDir)
+	case ExecutorLocal:
+		return NewLocalExecutor(), nil
+	default:
+		return nil, fmt.Errorf("unknown job executor %q, expected %s, %s or %s", name, ExecutorRemote, ExecutorFixture, ExecutorLocal)
+	}
+}
+
+// remoteJobTypes are the job types of the TEE worker version the node is built against. It has no Discord
+// or Telegram jobs yet.
+var remoteJobTypes = []string{"twitter-scraper", "web-scraper"}
+
+// RemoteExecutor runs jobs on the TEE worker at TEE_WORKER_URL, which seals their results.
+type RemoteExecutor struct{}
+
+// ExecuteJob submits the job to the TEE worker and waits for its result.
+func (RemoteExecutor) ExecuteJob(ctx context.Context, job types.Job) (string, error) {
+	client := NewClientWithContext(ctx)
+	res, err := client.SubmitJob(job)
+	if err != nil {
+		return "", err
+	}
+	return WaitForResult(ctx, client, res)
+}
+
+// Sealed implements JobExecutor.
+func (RemoteExecutor) Sealed() bool {
+	return true
+}
+
+// Supports implements JobExecutor.
+func (RemoteExecutor) Supports(jobType string) bool {
+	return slices.Contains(remoteJobTypes, jobType)
+}
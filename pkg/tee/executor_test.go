@@ -0,0 +1,110 @@
+package tee
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	types "github.com/masa-finance/tee-worker/api/types"
+)
+
+func TestFixtureExecutor(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "twitter.json"), []byte(`[
+		{"type": "twitter-scraper", "arguments": {"type": "searchbyquery"}, "result": [{"text": "any"}]},
+		{"type": "twitter-scraper", "arguments": {"type": "searchbyquery", "query": "masa", "count": 2}, "result": [{"text": "gm"}]},
+		{"type": "twitter-scraper", "arguments": {"type": "searchbyprofile"}, "error": "rate limit exceeded"}
+	]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "web.json"), []byte(`{"type": "web-scraper", "result": {"pages": []}}`), 0644))
+
+	executor, err := NewFixtureExecutor(dir)
+	require.NoError(t, err)
+	assert.False(t, executor.Sealed())
+	ctx := context.Background()
+
+	result, err := executor.ExecuteJob(ctx, types.Job{Type: "twitter-scraper", Arguments: types.JobArguments{"type": "searchbyquery", "query": "masa", "count": 2}})
+	require.NoError(t, err)
+	assert.Equal(t, `[{"text": "gm"}]`, result)
+
+	result, err = executor.ExecuteJob(ctx, types.Job{Type: "twitter-scraper", Arguments: types.JobArguments{"type": "searchbyquery", "query": "other", "count": 2}})
+	require.NoError(t, err)
+	assert.Equal(t, `[{"text": "any"}]`, result)
+
+	_, err = executor.ExecuteJob(ctx, types.Job{Type: "twitter-scraper", Arguments: types.JobArguments{"type": "searchbyprofile", "query": "masa"}})
+	assert.EqualError(t, err, "rate limit exceeded")
+
+	result, err = executor.ExecuteJob(ctx, types.Job{Type: "web-scraper", Arguments: types.JobArguments{"url": "https://example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"pages": []}`, result)
+
+	_, err = executor.ExecuteJob(ctx, types.Job{Type: "discord-scraper"})
+	assert.ErrorIs(t, err, ErrUnsupportedJob)
+
+	_, err = NewFixtureExecutor(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestLocalExecutor(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body>
+			<h1>Maize</h1><p>Maize is a <b>cereal</b> grain.</p><img src="/corn.png">
+			<script>ignored()</script>
+			<h2>History</h2><ul><li>Domesticated in Mexico</li></ul>
+			<a href="/history">more</a><a href="https://elsewhere.example/">elsewhere</a><a href="/missing">missing</a>
+		</body></html>`))
+	})
+	mux.HandleFunc("/history", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<p>About 9000 years ago.</p><a href="/">home</a>`))
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	executor := NewLocalExecutor()
+	assert.False(t, executor.Sealed())
+	ctx := context.Background()
+
+	result, err := executor.ExecuteJob(ctx, types.Job{Type: "web-scraper", Arguments: types.JobArguments{"url": server.URL + "/", "depth": 2}})
+	require.NoError(t, err)
+	var scraped webResult
+	require.NoError(t, json.Unmarshal([]byte(result), &scraped))
+	assert.Equal(t, []string{server.URL + "/", server.URL + "/history"}, scraped.Pages)
+	assert.Equal(t, []webSection{
+		{Title: "Maize", Paragraphs: []string{"Maize is a cereal grain."}, Images: []string{server.URL + "/corn.png"}},
+		{Title: "History", Paragraphs: []string{"Domesticated in Mexico"}},
+		{Paragraphs: []string{"About 9000 years ago."}},
+	}, scraped.Sections)
+
+	result, err = executor.ExecuteJob(ctx, types.Job{Type: "web-scraper", Arguments: types.JobArguments{"url": server.URL + "/"}})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result), &scraped))
+	assert.Equal(t, []string{server.URL + "/"}, scraped.Pages)
+
+	_, err = executor.ExecuteJob(ctx, types.Job{Type: "web-scraper", Arguments: types.JobArguments{"url": server.URL + "/missing"}})
+	assert.ErrorContains(t, err, "not found")
+	_, err = executor.ExecuteJob(ctx, types.Job{Type: "web-scraper", Arguments: types.JobArguments{"url": "file:///etc/passwd"}})
+	assert.Error(t, err)
+	_, err = executor.ExecuteJob(ctx, types.Job{Type: "twitter-scraper"})
+	assert.ErrorIs(t, err, ErrUnsupportedJob)
+}
+
+func TestExecutorsSupportedJobs(t *testing.T) {
+	assert.True(t, RemoteExecutor{}.Supports("twitter-scraper"))
+	assert.True(t, RemoteExecutor{}.Supports("web-scraper"))
+	assert.False(t, RemoteExecutor{}.Supports("discord-scraper"))
+
+	assert.True(t, NewLocalExecutor().Supports("web-scraper"))
+	assert.False(t, NewLocalExecutor().Supports("twitter-scraper"))
+
+	fixtures := NewFixtureExecutorWith(Fixture{Type: "discord-scraper", Result: json.RawMessage(`{}`)})
+	assert.True(t, fixtures.Supports("discord-scraper"))
+	assert.False(t, fixtures.Supports("web-scraper"))
+}
@@ -0,0 +1,134 @@
+package tee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	types "github.com/masa-finance/tee-worker/api/types"
+)
+
+// Fixture is a recorded job result. It answers jobs of its type whose arguments include all of its own,
+// with Result, or with Error if that is set.
+type Fixture struct {
+	Type      string                 `json:"type"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Result    json.RawMessage        `json:"result,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// FixtureExecutor answers jobs with recorded results instead of running them, so that handlers can be
+// exercised without a TEE or access to the data sources.
+type FixtureExecutor struct {
+	fixtures []Fixture
+}
+
+// NewFixtureExecutor returns an executor serving the fixtures of every .json file in dir. A file holds
+// either a single fixture or an array of them.
+func NewFixtureExecutor(dir string) (*FixtureExecutor, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var fixtures []Fixture
+	for _, path := range paths {
+		loaded, err := loadFixtures(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fixtures from %s: %w", path, err)
+		}
+		fixtures = append(fixtures, loaded...)
+	}
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+	return NewFixtureExecutorWith(fixtures...), nil
+}
+
+// NewFixtureExecutorWith returns an executor serving the given fixtures.
+func NewFixtureExecutorWith(fixtures ...Fixture) *FixtureExecutor {
+	return &FixtureExecutor{fixtures: fixtures}
+}
+
+func loadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err == nil {
+		return fixtures, nil
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, err
+	}
+	return []Fixture{fixture}, nil
+}
+
+// ExecuteJob answers the job with the most specific matching fixture, the one with the most arguments.
+func (fe *FixtureExecutor) ExecuteJob(ctx context.Context, job types.Job) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	arguments, err := normalizeArguments(job.Arguments)
+	if err != nil {
+		return "", err
+	}
+	var match *Fixture
+	for i, fixture := range fe.fixtures {
+		if fixture.Type != job.Type || !fixture.matches(arguments) {
+			continue
+		}
+		if match == nil || len(fixture.Arguments) > len(match.Arguments) {
+			match = &fe.fixtures[i]
+		}
+	}
+	if match == nil {
+		return "", fmt.Errorf("%w: no fixture for %s job with arguments %v", ErrUnsupportedJob, job.Type, job.Arguments)
+	}
+	if match.Error != "" {
+		return "", errors.New(match.Error)
+	}
+	return string(match.Result), nil
+}
+
+// matches reports whether every argument of the fixture has the same value in the job's arguments.
+func (f Fixture) matches(arguments map[string]interface{}) bool {
+	for name, value := range f.Arguments {
+		if !reflect.DeepEqual(value, arguments[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeArguments round trips job arguments through JSON, so that they compare equal to the
+// arguments of fixtures decoded from JSON.
+func normalizeArguments(arguments types.JobArguments) (map[string]interface{}, error) {
+	var normalized map[string]interface{}
+	if err := arguments.Unmarshal(&normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// Sealed implements JobExecutor, fixtures are recorded as plain JSON.
+func (fe *FixtureExecutor) Sealed() bool {
+	return false
+}
+
+// Supports implements JobExecutor, jobs are supported if there is a fixture of their type.
+func (fe *FixtureExecutor) Supports(jobType string) bool {
+	for _, fixture := range fe.fixtures {
+		if fixture.Type == jobType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+package tee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	types "github.com/masa-finance/tee-worker/api/types"
+	"golang.org/x/net/html"
+)
+
+// webScraperJob is the TEE worker job type for the web scraper.
+const webScraperJob = "web-scraper"
+
+// Limits of the local web scraper, which has none of the TEE worker's rate limiting.
+var (
+	localMaxPages    = 25
+	localMaxPageSize = int64(5 << 20)
+	localTimeout     = 30 * time.Second
+)
+
+// LocalExecutor runs web scraper jobs in-process over plain HTTP. It offers none of the guarantees of a TEE
+// and its results are not sealed, so it is only meant for trusted environments. Other jobs need the
+// credentials and scrapers of the TEE worker and are not supported.
+type LocalExecutor struct {
+	client *http.Client
+}
+
+// NewLocalExecutor returns a local executor.
+func NewLocalExecutor() *LocalExecutor {
+	return &LocalExecutor{client: &http.Client{Timeout: localTimeout}}
+}
+
+// webSection and webResult mirror the result of the TEE worker's web scraper.
+type webSection struct {
+	Title      string   `json:"title"`
+	Paragraphs []string `json:"paragraphs"`
+	Images     []string `json:"images"`
+}
+
+type webResult struct {
+	Sections []webSection `json:"sections"`
+	Pages    []string     `json:"pages"`
+}
+
+// ExecuteJob scrapes the page at the job's "url" argument, and the pages on the same host it links to up
+// to "depth" levels deep.
+func (le *LocalExecutor) ExecuteJob(ctx context.Context, job types.Job) (string, error) {
+	if job.Type != webScraperJob {
+		return "", fmt.Errorf("%w: the local executor cannot run %s jobs", ErrUnsupportedJob, job.Type)
+	}
+	var args struct {
+		URL   string `json:"url"`
+		Depth int    `json:"depth"`
+	}
+	if err := job.Arguments.Unmarshal(&args); err != nil {
+		return "", err
+	}
+	start, err := url.Parse(args.URL)
+	if err != nil || (start.Scheme != "http" && start.Scheme != "https") {
+		return "", fmt.Errorf("invalid url %q", args.URL)
+	}
+	depth := args.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+
+	result := webResult{Sections: []webSection{}, Pages: []string{}}
+	visited := map[string]bool{start.String(): true}
+	level := []*url.URL{start}
+	for d := 0; d < depth && len(level) > 0; d++ {
+		var next []*url.URL
+		for _, page := range level {
+			if len(result.Pages) >= localMaxPages {
+				break
+			}
+			sections, links, err := le.scrape(ctx, page)
+			if err != nil {
+				if ctx.Err() != nil || page == start {
+					return "", err
+				}
+				// A broken link does not fail the whole job
+				continue
+			}
+			result.Pages = append(result.Pages, page.String())
+			result.Sections = append(result.Sections, sections...)
+			for _, link := range links {
+				if link.Host == start.Host && !visited[link.String()] {
+					visited[link.String()] = true
+					next = append(next, link)
+				}
+			}
+		}
+		level = next
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// scrape fetches a page and returns its sections and the absolute URLs of its links.
+func (le *LocalExecutor) scrape(ctx context.Context, page *url.URL) ([]webSection, []*url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := le.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil, fmt.Errorf("page %s not found", page)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, nil, fmt.Errorf("rate limited by %s", page.Host)
+	case resp.StatusCode >= 300:
+		return nil, nil, fmt.Errorf("error fetching %s: received status code %d", page, resp.StatusCode)
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, localMaxPageSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	var sections []webSection
+	var links []*url.URL
+	current := webSection{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "noscript":
+				return
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				if current.Title != "" || len(current.Paragraphs) > 0 || len(current.Images) > 0 {
+					sections = append(sections, current)
+				}
+				current = webSection{Title: nodeText(n)}
+				return
+			case "p", "li":
+				if text := nodeText(n); text != "" {
+					current.Paragraphs = append(current.Paragraphs, text)
+				}
+			case "img":
+				if src := resolve(page, attribute(n, "src")); src != nil {
+					current.Images = append(current.Images, src.String())
+				}
+			case "a":
+				if link := resolve(page, attribute(n, "href")); link != nil {
+					link.Fragment = ""
+					links = append(links, link)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+	if current.Title != "" || len(current.Paragraphs) > 0 || len(current.Images) > 0 {
+		sections = append(sections, current)
+	}
+	return sections, links, nil
+}
+
+// nodeText returns the text of a node and its descendants with whitespace collapsed.
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func attribute(n *html.Node, name string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// resolve returns the http or https URL a reference on the page points to, or nil.
+func resolve(page *url.URL, ref string) *url.URL {
+	if ref == "" {
+		return nil
+	}
+	u, err := page.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	return u
+}
+
+// Sealed implements JobExecutor, local results are plain JSON.
+func (le *LocalExecutor) Sealed() bool {
+	return false
+}
+
+// Supports implements JobExecutor, the local executor only runs web scraper jobs.
+func (le *LocalExecutor) Supports(jobType string) bool {
+	return jobType == webScraperJob
+}
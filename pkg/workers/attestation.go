@@ -0,0 +1,105 @@
+package workers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// ParseRequiredAttestation parses a comma-separated list of work types, e.g. "twitter,web", into the set
+// of work types whose results must have a verified attestation.
+func ParseRequiredAttestation(spec string) (map[data_types.WorkerType]bool, error) {
+	result := make(map[data_types.WorkerType]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		wType := data_types.WorkerType(strings.TrimSpace(entry))
+		if wType == "" {
+			continue
+		}
+		if data_types.WorkerTypeToCategory(wType) < 0 {
+			return nil, fmt.Errorf("unknown work type %q", wType)
+		}
+		result[wType] = true
+	}
+	return result, nil
+}
+
+// ErrNoAttestationVerifier is returned when results must have a verified attestation but no verifier is
+// registered, so that every result of the work types would be rejected.
+var ErrNoAttestationVerifier = errors.New("attestation is required but no attestation verifier is registered")
+
+// CheckAttestation reports whether the attestations the node requires can be verified. Nodes refuse to
+// start when they cannot, rather than rejecting every result of the work types.
+func (whm *WorkHandlerManager) CheckAttestation() error {
+	if len(whm.requireAttestation) > 0 && len(whm.attestationPolicy.Verifiers) == 0 {
+		return ErrNoAttestationVerifier
+	}
+	return nil
+}
+
+// newAttestationPolicy returns the policy the attestations of results are verified against.
+func newAttestationPolicy(options *WorkerOption) tee.AttestationPolicy {
+	var measurements []string
+	for _, measurement := range strings.Split(options.attestationMeasurements, ",") {
+		if measurement = strings.TrimSpace(measurement); measurement != "" {
+			measurements = append(measurements, measurement)
+		}
+	}
+	return tee.AttestationPolicy{
+		Verifiers:    options.attestationVerifiers,
+		Measurements: measurements,
+		MaxAge:       workerConfig.AttestationMaxAge,
+		MaxClockSkew: workerConfig.AttestationClockSkew,
+	}
+}
+
+// attest attaches the attestation of the provider to a successful sealed response. Results of job
+// executors without a TEE are never attested. The provider has to produce the report inside the enclave
+// that ran the job, an attestation signed by the node process proves nothing about where the job ran. A response that cannot be attested is sent without, the
+// requester decides whether to accept it.
+func (whm *WorkHandlerManager) attest(workRequest data_types.WorkRequest, response *data_types.WorkResponse) {
+	if whm.attestor == nil || response.Error != "" || response.Unsealed {
+		return
+	}
+	resultHash, err := tee.ResultHash(response.Data)
+	if err == nil {
+		response.Attestation, err = tee.Attest(whm.attestor, tee.RequestHash(string(workRequest.WorkType), workRequest.Data), resultHash, time.Now())
+	}
+	if err != nil {
+		logrus.Errorf("[-] Failed to attest %s result: %v", workRequest.WorkType, err)
+	}
+}
+
+// verifyAttestation sets the verification status of a successful response. If the work type requires a
+// verified attestation and the response has none, it is replaced by an error that counts against the worker.
+func (whm *WorkHandlerManager) verifyAttestation(workRequest data_types.WorkRequest, response data_types.WorkResponse) data_types.WorkResponse {
+	if response.Error != "" {
+		// Only successful results are verified, a status claimed by the worker is not kept
+		response.Verification = ""
+		return response
+	}
+	var status tee.AttestationStatus
+	resultHash, err := tee.ResultHash(response.Data)
+	if err == nil {
+		status, err = whm.attestationPolicy.Verify(response.Attestation, tee.RequestHash(string(workRequest.WorkType), workRequest.Data), resultHash, time.Now())
+	} else {
+		status = tee.AttestationInvalid
+	}
+	response.Verification = status
+	if status == tee.AttestationVerified {
+		return response
+	}
+	if !whm.requireAttestation[workRequest.WorkType] {
+		logrus.Debugf("Accepting %s result of %s with %s attestation: %v", workRequest.WorkType, response.WorkerPeerId, status, err)
+		return response
+	}
+	failed := data_types.NewErrorResponse(data_types.ErrorAttestationFailed, "%s attestation: %v", status, err)
+	failed.WorkerPeerId = response.WorkerPeerId
+	failed.Verification = status
+	return failed
+}
@@ -0,0 +1,98 @@
+package workers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+	"github.com/masa-finance/masa-oracle/pkg/workers/wire"
+)
+
+func TestParseRequiredAttestation(t *testing.T) {
+	required, err := ParseRequiredAttestation("twitter, web,")
+	require.NoError(t, err)
+	assert.Equal(t, map[data_types.WorkerType]bool{data_types.Twitter: true, data_types.Web: true}, required)
+
+	_, err = ParseRequiredAttestation("mastodon")
+	assert.Error(t, err)
+}
+
+func TestCheckAttestation(t *testing.T) {
+	assert.NoError(t, NewWorkHandlerManager().CheckAttestation())
+	assert.ErrorIs(t, NewWorkHandlerManager(WithRequiredAttestation("twitter")).CheckAttestation(), ErrNoAttestationVerifier)
+
+	attestor, err := tee.NewMockAttestor("ab12")
+	require.NoError(t, err)
+	whm := NewWorkHandlerManager(WithRequiredAttestation("twitter"), WithAttestationVerifier(tee.MockAttestationProvider, attestor.Verifier()))
+	assert.NoError(t, whm.CheckAttestation())
+}
+
+func TestAttestation(t *testing.T) {
+	attestor, err := tee.NewMockAttestor("ab12")
+	require.NoError(t, err)
+	request := data_types.WorkRequest{WorkType: data_types.Web, Data: []byte(`{"url":"https://example.com"}`)}
+
+	worker := NewWorkHandlerManager(WithAttestationProvider(attestor))
+	worker.addWorkHandler(data_types.Web, stubHandler{})
+	response := worker.ExecuteWork(context.Background(), request)
+	require.Empty(t, response.Error)
+	require.NotNil(t, response.Attestation)
+
+	requester := NewWorkHandlerManager(
+		WithAttestationVerifier(tee.MockAttestationProvider, attestor.Verifier()),
+		WithAttestationMeasurements("cd34, AB12"),
+		WithRequiredAttestation("web"),
+	)
+	verified := requester.verifyAttestation(request, response)
+	assert.Empty(t, verified.Error)
+	assert.Equal(t, tee.AttestationVerified, verified.Verification)
+
+	// The attestation survives the trip over the wire
+	encoded, err := wire.Marshal(wire.CodecCBOR, response)
+	require.NoError(t, err)
+	var received data_types.WorkResponse
+	require.NoError(t, wire.Unmarshal(wire.CodecCBOR, encoded, &received))
+	assert.Equal(t, tee.AttestationVerified, requester.verifyAttestation(request, received).Verification)
+
+	// The result has to match the attestation
+	tampered := response
+	tampered.Data = "forged"
+	rejected := requester.verifyAttestation(request, tampered)
+	assert.Equal(t, data_types.ErrorAttestationFailed, rejected.Code())
+	assert.True(t, rejected.Code().WorkerFault())
+	assert.Equal(t, tee.AttestationInvalid, rejected.Verification)
+
+	// Work types that do not require attestations accept results without, but report it
+	unattested := response
+	unattested.Attestation = nil
+	missing := requester.verifyAttestation(data_types.WorkRequest{WorkType: data_types.Twitter, Data: request.Data}, unattested)
+	assert.Empty(t, missing.Error)
+	assert.Equal(t, tee.AttestationMissing, missing.Verification)
+	rejected = requester.verifyAttestation(request, unattested)
+	assert.Equal(t, data_types.ErrorAttestationFailed, rejected.Code())
+
+	// Untrusted enclaves are rejected
+	strict := NewWorkHandlerManager(
+		WithAttestationVerifier(tee.MockAttestationProvider, attestor.Verifier()),
+		WithAttestationMeasurements("cd34"),
+		WithRequiredAttestation("web"),
+	)
+	rejected = strict.verifyAttestation(request, response)
+	assert.Equal(t, tee.AttestationUntrusted, rejected.Verification)
+
+	// Failed responses carry no verification status
+	failed := data_types.NewErrorResponse(data_types.ErrorTimeout, "timed out")
+	failed.Verification = tee.AttestationVerified
+	assert.Empty(t, requester.verifyAttestation(request, failed).Verification)
+
+	// Results of executors without a TEE are not attested
+	unsealed := worker.ExecuteWork(context.Background(), request)
+	unsealed.Attestation = nil
+	unsealed.Unsealed = true
+	worker.attest(request, &unsealed)
+	assert.Nil(t, unsealed.Attestation)
+}
@@ -0,0 +1,128 @@
+package workers
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// defaultHandlerVersion is the advertised version of work handlers that do not implement VersionedWorkHandler.
+const defaultHandlerVersion = "1.0.0"
+
+// VersionedWorkHandler is implemented by work handlers that advertise a version of their own, which
+// should change whenever the handler's behaviour or results change in a way requesters may care about.
+type VersionedWorkHandler interface {
+	WorkHandler
+	Version() string
+}
+
+// Capabilities returns the capability of every enabled work handler, to be advertised to other nodes.
+// The options of a capability are the fields of the request schema of its work type.
+func (whm *WorkHandlerManager) Capabilities() []pubsub.Capability {
+	whm.mu.RLock()
+	defer whm.mu.RUnlock()
+	capabilities := make([]pubsub.Capability, 0, len(whm.handlers))
+	for wType, info := range whm.handlers {
+		if info.Disabled {
+			continue
+		}
+		version := defaultHandlerVersion
+		if versioned, ok := info.Handler.(VersionedWorkHandler); ok {
+			version = versioned.Version()
+		}
+		concurrency, configured := whm.concurrency[wType]
+		if !configured {
+			concurrency = workerConfig.WorkerConcurrency
+		}
+		capabilities = append(capabilities, pubsub.Capability{
+			WorkerType:     string(wType),
+			Category:       data_types.WorkerTypeToCategory(wType),
+			HandlerVersion: version,
+			MaxConcurrency: concurrency,
+			Options:        requestSchemaOptions(wType),
+			RateLimits:     workerConfig.RateLimits[wType],
+		})
+	}
+	sort.Slice(capabilities, func(i, j int) bool { return capabilities[i].WorkerType < capabilities[j].WorkerType })
+	return capabilities
+}
+
+// requestSchemaOptions returns the fields of the request schema of the work type, sorted.
+func requestSchemaOptions(wType data_types.WorkerType) []string {
+	schema, ok := data_types.RequestJSONSchema(wType)
+	if !ok {
+		return nil
+	}
+	options := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		options = append(options, name)
+	}
+	sort.Strings(options)
+	return options
+}
+
+// workRequirement returns what a worker must be capable of to be sent the work request. The options in use
+// are the fields of the request data that are set to something other than null, false, zero or an empty
+// value, so that requests only relying on defaults can still go to workers with older handlers.
+func workRequirement(workRequest data_types.WorkRequest) pubsub.WorkRequirement {
+	requirement := pubsub.WorkRequirement{
+		WorkerType: string(workRequest.WorkType),
+		Category:   data_types.WorkerTypeToCategory(workRequest.WorkType),
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(workRequest.Data, &fields); err != nil {
+		return requirement
+	}
+	for name, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case bool:
+			if !v {
+				continue
+			}
+		case float64:
+			if v == 0 {
+				continue
+			}
+		case string:
+			if v == "" {
+				continue
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		case map[string]interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		}
+		requirement.Options = append(requirement.Options, name)
+	}
+	sort.Strings(requirement.Options)
+	return requirement
+}
+
+// offersWorkType reports whether any of the nodes, whether available right now or not, advertises the work
+// type. Work types no node offers, such as those the job executors of the network cannot run, are answered
+// as unsupported rather than tried.
+func offersWorkType(nodes []pubsub.NodeData, wType data_types.WorkerType) bool {
+	requirement := pubsub.WorkRequirement{
+		WorkerType: string(wType),
+		Category:   data_types.WorkerTypeToCategory(wType),
+	}
+	for _, nd := range nodes {
+		if nd.CanDo(requirement) {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupportedResponse returns the failed response to work of a type no node offers.
+func unsupportedResponse(wType data_types.WorkerType) data_types.WorkResponse {
+	return data_types.NewErrorResponse(data_types.ErrorUnsupported, "unsupported work type %s: no worker in the network offers it", wType)
+}
@@ -0,0 +1,72 @@
+package workers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+type versionedHandler struct{ stubHandler }
+
+func (versionedHandler) Version() string { return "2.1.0" }
+
+type stubHandler struct{}
+
+func (stubHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
+	return data_types.WorkResponse{Data: "ok"}
+}
+
+func TestCapabilities(t *testing.T) {
+	whm := &WorkHandlerManager{
+		handlers:    make(map[data_types.WorkerType]*WorkHandlerInfo),
+		concurrency: map[data_types.WorkerType]int{data_types.Web: 2},
+	}
+	whm.addWorkHandler(data_types.Web, versionedHandler{})
+	whm.addWorkHandler(data_types.TelegramChannelMessages, stubHandler{})
+	whm.addWorkHandler(data_types.Twitter, stubHandler{})
+	whm.handlers[data_types.Twitter].Disabled = true
+
+	capabilities := whm.Capabilities()
+	require.Len(t, capabilities, 2)
+	assert.Equal(t, pubsub.Capability{
+		WorkerType:     string(data_types.TelegramChannelMessages),
+		Category:       pubsub.CategoryTelegram,
+		HandlerVersion: defaultHandlerVersion,
+		MaxConcurrency: workerConfig.WorkerConcurrency,
+		Options:        []string{"limit", "offsetID", "since", "until", "username"},
+	}, capabilities[0])
+	assert.Equal(t, string(data_types.Web), capabilities[1].WorkerType)
+	assert.Equal(t, "2.1.0", capabilities[1].HandlerVersion)
+	assert.Equal(t, 2, capabilities[1].MaxConcurrency)
+}
+
+func TestWorkRequirement(t *testing.T) {
+	requirement := workRequirement(data_types.WorkRequest{
+		WorkType: data_types.TelegramChannelMessages,
+		Data:     []byte(`{"username":"masafinance","limit":50,"offsetID":0,"since":"2024-01-01T00:00:00Z","until":null}`),
+	})
+	assert.Equal(t, string(data_types.TelegramChannelMessages), requirement.WorkerType)
+	assert.Equal(t, pubsub.CategoryTelegram, requirement.Category)
+	assert.Equal(t, []string{"limit", "since", "username"}, requirement.Options)
+}
+
+func TestOffersWorkType(t *testing.T) {
+	nodes := []pubsub.NodeData{
+		{IsStaked: true, Capabilities: []pubsub.Capability{{WorkerType: string(data_types.Twitter), Category: pubsub.CategoryTwitter}}},
+		{IsStaked: false, Capabilities: []pubsub.Capability{{WorkerType: string(data_types.DiscordProfile), Category: pubsub.CategoryDiscord}}},
+		{IsStaked: true, IsWebScraper: true},
+	}
+	assert.True(t, offersWorkType(nodes, data_types.Twitter))
+	assert.True(t, offersWorkType(nodes, data_types.Web), "nodes without capabilities advertise whole categories")
+	assert.False(t, offersWorkType(nodes, data_types.DiscordProfile), "unstaked nodes do not work")
+	assert.False(t, offersWorkType(nodes, data_types.TelegramChannelMessages))
+
+	response := unsupportedResponse(data_types.TelegramChannelMessages)
+	assert.Equal(t, data_types.ErrorUnsupported, response.Code())
+	assert.False(t, response.Retryable)
+}
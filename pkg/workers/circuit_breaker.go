@@ -0,0 +1,171 @@
+package workers
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BreakerState is the state of the circuit breaker of a remote peer.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // requests are sent to the peer
+	BreakerOpen     BreakerState = "open"      // the peer failed repeatedly and is skipped
+	BreakerHalfOpen BreakerState = "half-open" // a single trial request decides whether the peer is used again
+)
+
+// BreakerStatus describes the circuit breaker of a remote peer.
+type BreakerStatus struct {
+	PeerId              string       `json:"peerId"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenedAt            time.Time    `json:"openedAt,omitempty"`
+	RetryAt             time.Time    `json:"retryAt,omitempty"`
+}
+
+type peerBreaker struct {
+	state               BreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	trialInFlight       bool
+}
+
+// peerBreakers keeps a circuit breaker per remote peer. A peer whose requests fail threshold times in a row
+// is skipped for openTimeout. After that a single trial request is let through: if it succeeds the peer is
+// used again, otherwise it is skipped for another openTimeout. Only failures the worker is at fault for count.
+type peerBreakers struct {
+	mu          sync.Mutex
+	threshold   int
+	openTimeout time.Duration
+	breakers    map[string]*peerBreaker
+	now         func() time.Time
+}
+
+func newPeerBreakers(threshold int, openTimeout time.Duration) *peerBreakers {
+	return &peerBreakers{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		breakers:    make(map[string]*peerBreaker),
+		now:         time.Now,
+	}
+}
+
+// state returns the current state of the peer's breaker, moving an open breaker to half-open once its
+// timeout has passed. The caller must hold b.mu.
+func (b *peerBreakers) state(peerId string) (*peerBreaker, BreakerState) {
+	breaker, ok := b.breakers[peerId]
+	if !ok {
+		return nil, BreakerClosed
+	}
+	if breaker.state == BreakerOpen && b.now().Sub(breaker.openedAt) >= b.openTimeout {
+		breaker.state = BreakerHalfOpen
+		breaker.trialInFlight = false
+	}
+	return breaker, breaker.state
+}
+
+// available reports whether the peer may be selected for work, that is its breaker is not open.
+// A half-open peer is available until its trial request is in flight.
+func (b *peerBreakers) available(peerId string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breaker, state := b.state(peerId)
+	switch state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		return !breaker.trialInFlight
+	default:
+		return true
+	}
+}
+
+// allow reports whether a request may be sent to the peer now. For a half-open breaker it lets the
+// trial request through and holds back any other until the trial's outcome is recorded.
+func (b *peerBreakers) allow(peerId string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breaker, state := b.state(peerId)
+	switch state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if breaker.trialInFlight {
+			return false
+		}
+		breaker.trialInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the peer's breaker with the outcome of a request. failed is whether the worker was at
+// fault; a request that did not tell anything about the worker should be recorded with release instead.
+func (b *peerBreakers) record(peerId string, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breaker, state := b.state(peerId)
+	if !failed {
+		if state == BreakerHalfOpen {
+			logrus.Infof("[+] Closing the circuit breaker of peer %s after a successful trial request", peerId)
+		}
+		delete(b.breakers, peerId)
+		return
+	}
+
+	if breaker == nil {
+		breaker = &peerBreaker{state: BreakerClosed}
+		b.breakers[peerId] = breaker
+	}
+	breaker.consecutiveFailures++
+	breaker.trialInFlight = false
+	if state == BreakerHalfOpen || (b.threshold > 0 && breaker.consecutiveFailures >= b.threshold) {
+		if state != BreakerOpen {
+			logrus.Warnf("[-] Opening the circuit breaker of peer %s after %d consecutive failures", peerId, breaker.consecutiveFailures)
+		}
+		breaker.state = BreakerOpen
+		breaker.openedAt = b.now()
+	}
+}
+
+// release ends a trial request that did not tell whether the peer works, such as a cancelled one, so
+// that another trial request can be made.
+func (b *peerBreakers) release(peerId string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if breaker, ok := b.breakers[peerId]; ok {
+		breaker.trialInFlight = false
+	}
+}
+
+// statuses returns the breakers of the peers that have failed since their last success, by peer ID.
+func (b *peerBreakers) statuses() []BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	statuses := make([]BreakerStatus, 0, len(b.breakers))
+	for peerId := range b.breakers {
+		breaker, state := b.state(peerId)
+		status := BreakerStatus{
+			PeerId:              peerId,
+			State:               state,
+			ConsecutiveFailures: breaker.consecutiveFailures,
+		}
+		if state != BreakerClosed {
+			status.OpenedAt = breaker.openedAt
+			status.RetryAt = breaker.openedAt.Add(b.openTimeout)
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].PeerId < statuses[j].PeerId })
+	return statuses
+}
+
+// CircuitBreakers returns the circuit breaker of every remote peer that has failed since its last success.
+// Peers that are not listed have a closed breaker.
+func (whm *WorkHandlerManager) CircuitBreakers() []BreakerStatus {
+	return whm.breakers.statuses()
+}
@@ -0,0 +1,53 @@
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerBreakers(t *testing.T) {
+	now := time.Now()
+	breakers := newPeerBreakers(2, time.Minute)
+	breakers.now = func() time.Time { return now }
+
+	// Failures below the threshold keep the breaker closed, a success resets it
+	breakers.record("a", true)
+	assert.True(t, breakers.available("a"))
+	breakers.record("a", false)
+	assert.Empty(t, breakers.statuses())
+
+	breakers.record("a", true)
+	breakers.record("a", true)
+	assert.False(t, breakers.available("a"))
+	assert.False(t, breakers.allow("a"))
+	statuses := breakers.statuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, BreakerStatus{PeerId: "a", State: BreakerOpen, ConsecutiveFailures: 2, OpenedAt: now, RetryAt: now.Add(time.Minute)}, statuses[0])
+
+	// Once the timeout passes a single trial request is let through
+	now = now.Add(time.Minute)
+	assert.True(t, breakers.available("a"))
+	assert.True(t, breakers.allow("a"))
+	assert.False(t, breakers.allow("a"))
+	assert.False(t, breakers.available("a"))
+	assert.Equal(t, BreakerHalfOpen, breakers.statuses()[0].State)
+
+	// A trial that tells nothing about the peer lets another one through
+	breakers.release("a")
+	assert.True(t, breakers.allow("a"))
+
+	// A failed trial opens the breaker again
+	breakers.record("a", true)
+	assert.Equal(t, BreakerOpen, breakers.statuses()[0].State)
+	assert.False(t, breakers.allow("a"))
+
+	// A successful trial closes it
+	now = now.Add(time.Minute)
+	assert.True(t, breakers.allow("a"))
+	breakers.record("a", false)
+	assert.True(t, breakers.allow("a"))
+	assert.Empty(t, breakers.statuses())
+}
@@ -0,0 +1,221 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// ErrWorkerBusy is reported by a worker that has no capacity left for a work request.
+var ErrWorkerBusy = errors.New("worker busy")
+
+// minRetryAfter is the smallest retry-after hint a busy worker gives.
+const minRetryAfter = time.Second
+
+// ParseWorkerConcurrency parses a concurrency configuration of the form "twitter=2,web=8" into
+// the maximum number of requests executed at once per work type.
+func ParseWorkerConcurrency(spec string) (map[data_types.WorkerType]int, error) {
+	result := make(map[data_types.WorkerType]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		workType, limitStr, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid worker concurrency entry %q, expected type=limit", entry)
+		}
+		wType := data_types.WorkerType(strings.TrimSpace(workType))
+		if data_types.WorkerTypeToCategory(wType) < 0 {
+			return nil, fmt.Errorf("unknown work type %q", wType)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid concurrency limit %q for %s", limitStr, wType)
+		}
+		result[wType] = limit
+	}
+	return result, nil
+}
+
+// workLimiter bounds the number of requests of one work type that a worker executes at once.
+// Requests beyond the limit wait in a bounded queue; once the queue is full, or a request has
+// waited for longer than the queue timeout, the worker reports itself as busy.
+type workLimiter struct {
+	slots        chan struct{}
+	mu           sync.Mutex
+	queued       int
+	maxQueued    int
+	queueTimeout time.Duration
+}
+
+func newWorkLimiter(limit, maxQueued int, queueTimeout time.Duration) *workLimiter {
+	return &workLimiter{
+		slots:        make(chan struct{}, limit),
+		maxQueued:    maxQueued,
+		queueTimeout: queueTimeout,
+	}
+}
+
+// acquire waits for an execution slot and returns a function that releases it, or false if the worker is busy
+// or ctx is done before a slot frees up.
+func (l *workLimiter) acquire(ctx context.Context) (release func(), ok bool) {
+	release = func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, true
+	default:
+	}
+
+	l.mu.Lock()
+	if l.queued >= l.maxQueued {
+		l.mu.Unlock()
+		return nil, false
+	}
+	l.queued++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.queued--
+		l.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return release, true
+	case <-timer.C:
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// load returns the number of requests executing and waiting.
+func (l *workLimiter) load() (running, queued int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.slots), l.queued
+}
+
+// getWorkLimiter returns the limiter for a work type, creating it on first use.
+func (whm *WorkHandlerManager) getWorkLimiter(wType data_types.WorkerType) *workLimiter {
+	whm.mu.Lock()
+	defer whm.mu.Unlock()
+	limiter, ok := whm.limiters[wType]
+	if !ok {
+		limit, configured := whm.concurrency[wType]
+		if !configured {
+			limit = workerConfig.WorkerConcurrency
+		}
+		limiter = newWorkLimiter(limit, workerConfig.WorkerQueueSize, workerConfig.WorkerQueueTimeout)
+		whm.limiters[wType] = limiter
+	}
+	return limiter
+}
+
+// executeWorkLimited runs ExecuteWork within the concurrency limit of the request's work type.
+// If there is no capacity left it answers at once with a busy response carrying a retry-after hint.
+func (whm *WorkHandlerManager) executeWorkLimited(ctx context.Context, workRequest data_types.WorkRequest) data_types.WorkResponse {
+	limiter := whm.getWorkLimiter(workRequest.WorkType)
+	release, ok := limiter.acquire(ctx)
+	if !ok {
+		if ctx.Err() != nil {
+			return cancelledResponse()
+		}
+		response := data_types.NewErrorResponse(data_types.ErrorWorkerBusy, "%v", ErrWorkerBusy)
+		response.Busy = true
+		response.RetryAfterMs = whm.retryAfter(workRequest.WorkType, limiter).Milliseconds()
+		return response
+	}
+	defer release()
+	return whm.ExecuteWork(ctx, workRequest)
+}
+
+// retryAfter estimates when the limiter is likely to have capacity again, based on the average
+// runtime of the work type's handler.
+func (whm *WorkHandlerManager) retryAfter(wType data_types.WorkerType, limiter *workLimiter) time.Duration {
+	whm.mu.RLock()
+	var average time.Duration
+	if info, ok := whm.handlers[wType]; ok && info.CallCount > 0 {
+		average = info.TotalRuntime / time.Duration(info.CallCount)
+	}
+	whm.mu.RUnlock()
+
+	running, queued := limiter.load()
+	wait := average * time.Duration(queued+1) / time.Duration(max(running, 1))
+	if wait < minRetryAfter {
+		return minRetryAfter
+	}
+	return wait.Round(time.Second)
+}
+
+// busyPeers remembers the remote workers that reported themselves busy, until their retry-after hint passes.
+type busyPeers struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+func newBusyPeers() *busyPeers {
+	return &busyPeers{until: make(map[string]time.Time)}
+}
+
+// mark records that the peer is busy for the given duration.
+func (b *busyPeers) mark(peerId string, retryAfter time.Duration) {
+	if retryAfter < minRetryAfter {
+		retryAfter = minRetryAfter
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.until[peerId] = time.Now().Add(retryAfter)
+}
+
+// isBusy reports whether the peer asked not to be sent work right now.
+func (b *busyPeers) isBusy(peerId string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	until, ok := b.until[peerId]
+	if ok && time.Now().After(until) {
+		delete(b.until, peerId)
+		return false
+	}
+	return ok
+}
+
+// nextFree returns how long it takes until the first busy peer accepts work again.
+func (b *busyPeers) nextFree() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var next time.Duration
+	for _, until := range b.until {
+		if wait := time.Until(until); wait > 0 && (next == 0 || wait < next) {
+			next = wait
+		}
+	}
+	if next < minRetryAfter {
+		return minRetryAfter
+	}
+	return next.Round(time.Second)
+}
+
+// deprioritize moves the workers that are currently busy to the end of the list, keeping the
+// order of the others, so they are only tried once every other worker has failed.
+func (b *busyPeers) deprioritize(workers []data_types.Worker) []data_types.Worker {
+	ordered := make([]data_types.Worker, 0, len(workers))
+	var busy []data_types.Worker
+	for _, worker := range workers {
+		if b.isBusy(worker.NodeData.PeerId.String()) {
+			busy = append(busy, worker)
+			continue
+		}
+		ordered = append(ordered, worker)
+	}
+	return append(ordered, busy...)
+}
@@ -0,0 +1,166 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
+	h.started <- struct{}{}
+	select {
+	case <-h.release:
+		return data_types.WorkResponse{Data: "done"}
+	case <-ctx.Done():
+		return data_types.WorkResponse{Error: ctx.Err().Error()}
+	}
+}
+
+func TestParseWorkerConcurrency(t *testing.T) {
+	limits, err := ParseWorkerConcurrency("twitter=2, web=8")
+	require.NoError(t, err)
+	assert.Equal(t, map[data_types.WorkerType]int{data_types.Twitter: 2, data_types.Web: 8}, limits)
+
+	_, err = ParseWorkerConcurrency("web=0")
+	assert.Error(t, err)
+	_, err = ParseWorkerConcurrency("unknown=2")
+	assert.Error(t, err)
+}
+
+func TestWorkerConcurrencyLimit(t *testing.T) {
+	queueSize, queueTimeout := workerConfig.WorkerQueueSize, workerConfig.WorkerQueueTimeout
+	workerConfig.WorkerQueueSize, workerConfig.WorkerQueueTimeout = 1, time.Second
+	defer func() { workerConfig.WorkerQueueSize, workerConfig.WorkerQueueTimeout = queueSize, queueTimeout }()
+
+	handler := &blockingHandler{started: make(chan struct{}, 3), release: make(chan struct{})}
+	whm := NewWorkHandlerManager(WithWorkerConcurrency("web=1"))
+	whm.addWorkHandler(data_types.Web, handler)
+	request := data_types.WorkRequest{WorkType: data_types.Web}
+
+	var wg sync.WaitGroup
+	responses := make(chan data_types.WorkResponse, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses <- whm.executeWorkLimited(context.Background(), request)
+		}()
+	}
+
+	// One request runs, the other waits in the queue, so a third one is rejected at once
+	<-handler.started
+	limiter := whm.getWorkLimiter(data_types.Web)
+	require.Eventually(t, func() bool {
+		_, queued := limiter.load()
+		return queued == 1
+	}, time.Second, time.Millisecond)
+
+	busy := whm.executeWorkLimited(context.Background(), request)
+	assert.True(t, busy.Busy)
+	assert.Equal(t, ErrWorkerBusy.Error(), busy.Error)
+	assert.Equal(t, data_types.ErrorWorkerBusy, busy.ErrorCode)
+	assert.GreaterOrEqual(t, busy.RetryAfterMs, minRetryAfter.Milliseconds())
+
+	close(handler.release)
+	wg.Wait()
+	close(responses)
+	for response := range responses {
+		assert.Equal(t, "done", response.Data)
+	}
+}
+
+func TestExecuteWorkCancelled(t *testing.T) {
+	handler := &blockingHandler{started: make(chan struct{}, 1), release: make(chan struct{})}
+	whm := NewWorkHandlerManager()
+	whm.addWorkHandler(data_types.Web, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	responses := make(chan data_types.WorkResponse, 1)
+	go func() {
+		responses <- whm.executeWorkLimited(ctx, data_types.WorkRequest{WorkType: data_types.Web})
+	}()
+	<-handler.started
+	cancel()
+
+	response := <-responses
+	assert.Equal(t, errWorkCancelled.Error(), response.Error)
+	assert.False(t, response.Busy)
+}
+
+func TestBusyPeers(t *testing.T) {
+	busy := newBusyPeers()
+	workers := []data_types.Worker{
+		{NodeData: pubsub.NodeData{PeerId: peer.ID("a")}},
+		{NodeData: pubsub.NodeData{PeerId: peer.ID("b")}},
+		{NodeData: pubsub.NodeData{PeerId: peer.ID("c")}},
+	}
+	busy.mark(peer.ID("a").String(), 2*time.Second)
+
+	ordered := busy.deprioritize(workers)
+	assert.Equal(t, []peer.ID{"b", "c", "a"}, []peer.ID{ordered[0].NodeData.PeerId, ordered[1].NodeData.PeerId, ordered[2].NodeData.PeerId})
+	assert.Equal(t, 2*time.Second, busy.nextFree())
+}
+
+func TestWorkFailuresResponse(t *testing.T) {
+	nextFree := func() time.Duration { return 3 * time.Second }
+	busy := data_types.NewErrorResponse(data_types.ErrorWorkerBusy, "worker busy")
+	busy.Busy = true
+	rateLimited := data_types.NewErrorResponse(data_types.ErrorRateLimited, "rate limited")
+	rateLimited.RetryAfterMs = 5000
+
+	t.Run("no attempts", func(t *testing.T) {
+		response := workFailures(nil).response(nextFree)
+		assert.Equal(t, "no eligible workers found", response.Error)
+		assert.Equal(t, data_types.ErrorUpstreamUnavailable, response.ErrorCode)
+	})
+
+	t.Run("every worker busy", func(t *testing.T) {
+		var failures workFailures
+		failures.add("Worker a", busy)
+		failures.add("Local worker", busy)
+		response := failures.response(nextFree)
+		assert.Equal(t, data_types.ErrorWorkerBusy, response.ErrorCode)
+		assert.True(t, response.Busy)
+		assert.Equal(t, int64(3000), response.RetryAfterMs)
+		assert.Equal(t, "All workers failed. Errors: Worker a: worker busy; Local worker: worker busy", response.Error)
+	})
+
+	t.Run("every worker rate limited", func(t *testing.T) {
+		var failures workFailures
+		failures.add("Worker a", data_types.NewErrorResponse(data_types.ErrorRateLimited, "rate limited"))
+		failures.add("Worker b", rateLimited)
+		response := failures.response(nextFree)
+		assert.Equal(t, data_types.ErrorRateLimited, response.ErrorCode)
+		assert.Equal(t, int64(5000), response.RetryAfterMs)
+	})
+
+	t.Run("mixed failures", func(t *testing.T) {
+		var failures workFailures
+		failures.add("Worker a", busy)
+		failures.add("Worker b", data_types.NewErrorResponse(data_types.ErrorTimeout, "timed out"))
+		response := failures.response(nextFree)
+		assert.Equal(t, data_types.ErrorUpstreamUnavailable, response.ErrorCode)
+		assert.True(t, response.Retryable)
+		assert.False(t, response.Busy)
+	})
+
+	t.Run("errors of older peers", func(t *testing.T) {
+		var failures workFailures
+		failures.add("Worker a", data_types.WorkResponse{Error: "something broke"})
+		response := failures.response(nextFree)
+		assert.Equal(t, data_types.ErrorInternal, response.ErrorCode)
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
 )
 
 type WorkerConfig struct {
@@ -12,9 +15,49 @@ type WorkerConfig struct {
 	ConnectionTimeout     time.Duration
 	FindPeerTimeout       time.Duration
 	MaxRetries            int
-	MaxSpawnAttempts      int
+	RetryBaseDelay        time.Duration
+	RetryMaxDelay         time.Duration
+	BreakerThreshold      int
+	BreakerOpenTimeout    time.Duration
 	WorkerBufferSize      int
 	MaxRemoteWorkers      int
+	DispatchFanOut        int
+	DispatchHedgeDelay    time.Duration
+	MaxDispatchFanOut     int
+	JobQueueSize          int
+	JobConcurrency        int
+	JobRetention          time.Duration
+	JobVisibilityTimeout  time.Duration
+	JobMaxAttempts        int
+	ResultCacheTTL        map[data_types.WorkerType]time.Duration
+	ResultCacheSize       int
+	MaxQuorumWorkers      int
+	QuorumIgnoredFields   []string
+	WorkerConcurrency     int
+	WorkerQueueSize       int
+	WorkerQueueTimeout    time.Duration
+	WireCodec             string
+	WireCompressThreshold int
+	WireReadTimeout       time.Duration
+	WireWriteTimeout      time.Duration
+	MaxRequestSize        int
+	MaxResponseSize       int
+	CrashLogSize          int
+	HandlerCrashLimit     int
+	HandlerCrashWindow    time.Duration
+	SchedulerInterval     time.Duration
+	ScheduleResultLimit   int
+	WebhookTimeout        time.Duration
+	RetryPolicies         map[data_types.WorkerType]RetryPolicy
+	RateLimits            map[data_types.WorkerType][]pubsub.RateLimit // advertised to other nodes with each capability
+	AcceptUnsealedResults bool                                         // accept results of remote workers that run their jobs without a TEE
+	AttestationMaxAge     time.Duration                                // how old the attestation of a result may be
+	AttestationClockSkew  time.Duration                                // how far in the future the attestation of a result may be dated
+	ResultStoreMaxAge     time.Duration                                // how long the result store keeps results
+	ResultStoreMaxSize    int64                                        // the size in bytes the result store is pruned down to
+	ResultStorePruneEvery time.Duration
+	ExportSpoolMaxAge     time.Duration // how long the export spool keeps results
+	ExportSpoolMaxSize    int64         // the size in bytes the export spool is pruned down to
 }
 
 var DefaultConfig = WorkerConfig{
@@ -23,9 +66,73 @@ var DefaultConfig = WorkerConfig{
 	ConnectionTimeout:     75 * time.Millisecond,
 	FindPeerTimeout:       50 * time.Millisecond,
 	MaxRetries:            1,
-	MaxSpawnAttempts:      1,
+	RetryBaseDelay:        500 * time.Millisecond,
+	RetryMaxDelay:         5 * time.Second,
+	BreakerThreshold:      3,
+	BreakerOpenTimeout:    time.Minute,
 	WorkerBufferSize:      100,
 	MaxRemoteWorkers:      10,
+	DispatchFanOut:        1,
+	DispatchHedgeDelay:    0,
+	MaxDispatchFanOut:     5,
+	JobQueueSize:          1000,
+	JobConcurrency:        10,
+	JobRetention:          24 * time.Hour,
+	JobVisibilityTimeout:  5 * time.Minute,
+	JobMaxAttempts:        3,
+	ResultCacheTTL: map[data_types.WorkerType]time.Duration{
+		data_types.Twitter:                 30 * time.Second,
+		data_types.TwitterFollowers:        5 * time.Minute,
+		data_types.TwitterProfile:          5 * time.Minute,
+		data_types.Web:                     5 * time.Minute,
+		data_types.DiscordProfile:          5 * time.Minute,
+		data_types.DiscordChannelMessages:  30 * time.Second,
+		data_types.DiscordGuildChannels:    5 * time.Minute,
+		data_types.DiscordUserGuilds:       5 * time.Minute,
+		data_types.TelegramChannelMessages: 30 * time.Second,
+	},
+	ResultCacheSize:       1000,
+	MaxQuorumWorkers:      7,
+	QuorumIgnoredFields:   nil,
+	WorkerConcurrency:     4,
+	WorkerQueueSize:       16,
+	WorkerQueueTimeout:    10 * time.Second,
+	WireCodec:             "cbor",
+	WireCompressThreshold: 16 * 1024,
+	WireReadTimeout:       10 * time.Second,
+	WireWriteTimeout:      10 * time.Second,
+	MaxRequestSize:        1 << 20,
+	MaxResponseSize:       64 << 20,
+	CrashLogSize:          100,
+	HandlerCrashLimit:     5,
+	HandlerCrashWindow:    10 * time.Minute,
+	SchedulerInterval:     time.Second,
+	ScheduleResultLimit:   100,
+	WebhookTimeout:        10 * time.Second,
+	RetryPolicies: map[data_types.WorkerType]RetryPolicy{
+		// Twitter workers share rate limits, so a round that was rate limited everywhere is not repeated
+		data_types.Twitter: {
+			MaxAttempts: 2,
+			BaseDelay:   time.Second,
+			MaxDelay:    5 * time.Second,
+			RetryOn:     []data_types.ErrorCode{data_types.ErrorTimeout, data_types.ErrorUpstreamUnavailable, data_types.ErrorWorkerBusy},
+		},
+		data_types.Web: {
+			MaxAttempts: 3,
+			BaseDelay:   500 * time.Millisecond,
+			MaxDelay:    5 * time.Second,
+			RetryOn:     []data_types.ErrorCode{data_types.ErrorTimeout, data_types.ErrorUpstreamUnavailable, data_types.ErrorWorkerBusy, data_types.ErrorRateLimited},
+		},
+	},
+	RateLimits:            nil,
+	AcceptUnsealedResults: false,
+	AttestationMaxAge:     5 * time.Minute,
+	AttestationClockSkew:  30 * time.Second,
+	ResultStoreMaxAge:     7 * 24 * time.Hour,
+	ResultStoreMaxSize:    1 << 30,
+	ResultStorePruneEvery: time.Minute,
+	ExportSpoolMaxAge:     30 * 24 * time.Hour,
+	ExportSpoolMaxSize:    1 << 30,
 }
 
 var workerConfig *WorkerConfig
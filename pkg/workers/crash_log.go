@@ -0,0 +1,200 @@
+package workers
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// ErrHandlerDisabled is returned for work whose handler was disabled after crashing repeatedly.
+var ErrHandlerDisabled = errors.New("work handler disabled after repeated crashes")
+
+// Crash is a recovered panic of a work handler.
+type Crash struct {
+	ID       string                `json:"id"`
+	WorkType data_types.WorkerType `json:"workType"`
+	Time     time.Time             `json:"time"`
+	Panic    string                `json:"panic"`
+	Stack    string                `json:"stack"`
+}
+
+// HandlerStatus describes a registered work handler, for operators.
+type HandlerStatus struct {
+	WorkType  data_types.WorkerType `json:"workType"`
+	Enabled   bool                  `json:"enabled"`
+	CallCount int64                 `json:"callCount"`
+	Crashes   int64                 `json:"crashes"`
+}
+
+// crashLog keeps the most recent handler crashes, and the times of the crashes of each work type
+// that are recent enough to count towards disabling its handler.
+type crashLog struct {
+	mu      sync.Mutex
+	size    int
+	crashes []Crash
+	recent  map[data_types.WorkerType][]time.Time
+}
+
+func newCrashLog(size int) *crashLog {
+	return &crashLog{
+		size:   size,
+		recent: make(map[data_types.WorkerType][]time.Time),
+	}
+}
+
+// record adds a crash to the log, dropping the oldest one once the log is full. It returns the crash
+// and the number of crashes of the work type within the window.
+func (l *crashLog) record(wType data_types.WorkerType, recovered interface{}, stack []byte, window time.Duration) (Crash, int) {
+	crash := Crash{
+		ID:       uuid.New().String(),
+		WorkType: wType,
+		Time:     time.Now(),
+		Panic:    fmt.Sprint(recovered),
+		Stack:    string(stack),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size > 0 {
+		if len(l.crashes) >= l.size {
+			l.crashes = append(l.crashes[:0], l.crashes[len(l.crashes)-l.size+1:]...)
+		}
+		l.crashes = append(l.crashes, crash)
+	}
+
+	recent := l.recent[wType][:0]
+	for _, t := range l.recent[wType] {
+		if crash.Time.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	l.recent[wType] = append(recent, crash.Time)
+	return crash, len(l.recent[wType])
+}
+
+// reset forgets the recent crashes of the work type, so it has to crash repeatedly again to be disabled.
+func (l *crashLog) reset(wType data_types.WorkerType) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.recent, wType)
+}
+
+// list returns the logged crashes, newest first.
+func (l *crashLog) list() []Crash {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	crashes := make([]Crash, len(l.crashes))
+	for i, crash := range l.crashes {
+		crashes[len(l.crashes)-1-i] = crash
+	}
+	return crashes
+}
+
+// handleWork runs the handler and turns a panic into an internal error response carrying the ID of the
+// crash. A handler that crashes too often within the configured window is disabled.
+func (whm *WorkHandlerManager) handleWork(handlerInfo *WorkHandlerInfo, workRequest data_types.WorkRequest, run func() data_types.WorkResponse) (response data_types.WorkResponse) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		crash, count := whm.crashes.record(workRequest.WorkType, recovered, debug.Stack(), workerConfig.HandlerCrashWindow)
+		logrus.Errorf("[-] Work handler for %s crashed (crash ID %s): %v\n%s", workRequest.WorkType, crash.ID, recovered, crash.Stack)
+
+		whm.mu.Lock()
+		handlerInfo.CrashCount++
+		whm.mu.Unlock()
+		if workerConfig.HandlerCrashLimit > 0 && count >= workerConfig.HandlerCrashLimit {
+			logrus.Errorf("[-] Disabling the %s work handler after %d crashes within %s", workRequest.WorkType, count, workerConfig.HandlerCrashWindow)
+			whm.setWorkHandlerEnabled(workRequest.WorkType, false)
+		}
+
+		response = data_types.NewErrorResponse(data_types.ErrorInternal, "work handler crashed, crash ID %s", crash.ID)
+		response.CrashId = crash.ID
+	}()
+	return run()
+}
+
+// SetCapabilityListener registers a function that is called whenever a handler is disabled or re-enabled,
+// with its category and whether the category still has an enabled handler. It is meant to advertise the
+// changed capabilities to other nodes, and to withdraw the category once none of its handlers is enabled.
+func (whm *WorkHandlerManager) SetCapabilityListener(listener func(category pubsub.WorkerCategory, available bool)) {
+	whm.mu.Lock()
+	defer whm.mu.Unlock()
+	whm.capabilityListener = listener
+}
+
+// EnableWorkHandler re-enables a work handler that was disabled after crashing repeatedly.
+func (whm *WorkHandlerManager) EnableWorkHandler(wType data_types.WorkerType) error {
+	whm.mu.RLock()
+	_, exists := whm.handlers[wType]
+	whm.mu.RUnlock()
+	if !exists {
+		return ErrHandlerNotFound
+	}
+	whm.crashes.reset(wType)
+	whm.setWorkHandlerEnabled(wType, true)
+	return nil
+}
+
+// setWorkHandlerEnabled disables or re-enables a handler, and tells the capability listener.
+func (whm *WorkHandlerManager) setWorkHandlerEnabled(wType data_types.WorkerType, enabled bool) {
+	category := data_types.WorkerTypeToCategory(wType)
+
+	whm.mu.Lock()
+	info, exists := whm.handlers[wType]
+	if !exists || info.Disabled == !enabled {
+		whm.mu.Unlock()
+		return
+	}
+	info.Disabled = !enabled
+	available := whm.categoryAvailable(category)
+	listener := whm.capabilityListener
+	whm.mu.Unlock()
+
+	if listener != nil {
+		// The listener gossips node data, which must not hold up the work
+		go listener(category, available)
+	}
+}
+
+// categoryAvailable reports whether at least one of the handlers of the category is enabled. The caller must hold whm.mu.
+func (whm *WorkHandlerManager) categoryAvailable(category pubsub.WorkerCategory) bool {
+	for wType, info := range whm.handlers {
+		if !info.Disabled && data_types.WorkerTypeToCategory(wType) == category {
+			return true
+		}
+	}
+	return false
+}
+
+// HandlerStatuses returns the status of every registered work handler.
+func (whm *WorkHandlerManager) HandlerStatuses() []HandlerStatus {
+	whm.mu.RLock()
+	defer whm.mu.RUnlock()
+	statuses := make([]HandlerStatus, 0, len(whm.handlers))
+	for wType, info := range whm.handlers {
+		statuses = append(statuses, HandlerStatus{
+			WorkType:  wType,
+			Enabled:   !info.Disabled,
+			CallCount: info.CallCount,
+			Crashes:   info.CrashCount,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].WorkType < statuses[j].WorkType })
+	return statuses
+}
+
+// Crashes returns the most recent work handler crashes, newest first.
+func (whm *WorkHandlerManager) Crashes() []Crash {
+	return whm.crashes.list()
+}
@@ -0,0 +1,102 @@
+package workers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+type panickingHandler struct{}
+
+func (panickingHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
+	var counts map[string]int
+	counts["count"]++
+	return data_types.WorkResponse{}
+}
+
+func TestHandlerCrash(t *testing.T) {
+	limit := workerConfig.HandlerCrashLimit
+	workerConfig.HandlerCrashLimit = 2
+	defer func() { workerConfig.HandlerCrashLimit = limit }()
+
+	whm := NewWorkHandlerManager()
+	whm.addWorkHandler(data_types.Web, panickingHandler{})
+	changes := make(chan bool, 2)
+	whm.SetCapabilityListener(func(category pubsub.WorkerCategory, enabled bool) {
+		assert.Equal(t, pubsub.CategoryWeb, category)
+		changes <- enabled
+	})
+	request := data_types.WorkRequest{WorkType: data_types.Web}
+
+	response := whm.ExecuteWork(context.Background(), request)
+	assert.Equal(t, data_types.ErrorInternal, response.Code())
+	require.NotEmpty(t, response.CrashId)
+	assert.Contains(t, response.Error, response.CrashId)
+
+	crashes := whm.Crashes()
+	require.Len(t, crashes, 1)
+	assert.Equal(t, response.CrashId, crashes[0].ID)
+	assert.Equal(t, data_types.Web, crashes[0].WorkType)
+	assert.Contains(t, crashes[0].Panic, "nil map")
+	assert.Contains(t, crashes[0].Stack, "panickingHandler")
+
+	// The second crash within the window disables the handler and withdraws the capability
+	whm.ExecuteWork(context.Background(), request)
+	assert.False(t, <-changes)
+	assert.Equal(t, []HandlerStatus{{WorkType: data_types.Web, Enabled: false, CallCount: 2, Crashes: 2}}, whm.HandlerStatuses())
+	response = whm.ExecuteWork(context.Background(), request)
+	assert.Equal(t, ErrHandlerDisabled.Error(), response.Error)
+
+	require.NoError(t, whm.EnableWorkHandler(data_types.Web))
+	assert.True(t, <-changes)
+	assert.True(t, whm.HandlerStatuses()[0].Enabled)
+	assert.ErrorIs(t, whm.EnableWorkHandler(data_types.Twitter), ErrHandlerNotFound)
+}
+
+func TestCapabilityListener(t *testing.T) {
+	whm := NewWorkHandlerManager()
+	whm.addWorkHandler(data_types.Twitter, panickingHandler{})
+	whm.addWorkHandler(data_types.TwitterProfile, panickingHandler{})
+	changes := make(chan bool, 4)
+	whm.SetCapabilityListener(func(category pubsub.WorkerCategory, available bool) {
+		assert.Equal(t, pubsub.CategoryTwitter, category)
+		changes <- available
+	})
+
+	// Disabling one handler only withdraws its own capability, the category is still available
+	whm.setWorkHandlerEnabled(data_types.TwitterProfile, false)
+	assert.True(t, <-changes)
+	capabilities := whm.Capabilities()
+	require.Len(t, capabilities, 1)
+	assert.Equal(t, string(data_types.Twitter), capabilities[0].WorkerType)
+
+	whm.setWorkHandlerEnabled(data_types.Twitter, false)
+	assert.False(t, <-changes, "the category is unavailable once every handler is disabled")
+	assert.Empty(t, whm.Capabilities())
+
+	whm.setWorkHandlerEnabled(data_types.Twitter, true)
+	assert.True(t, <-changes)
+}
+
+func TestCrashLogIsBounded(t *testing.T) {
+	log := newCrashLog(2)
+	var ids []string
+	for i := 0; i < 3; i++ {
+		crash, count := log.record(data_types.Web, "boom", nil, time.Minute)
+		assert.Equal(t, i+1, count)
+		ids = append(ids, crash.ID)
+	}
+	crashes := log.list()
+	require.Len(t, crashes, 2)
+	assert.Equal(t, []string{ids[2], ids[1]}, []string{crashes[0].ID, crashes[1].ID})
+
+	// Crashes outside the window do not count towards disabling the handler
+	_, count := log.record(data_types.Web, "boom", nil, 0)
+	assert.Equal(t, 1, count)
+}
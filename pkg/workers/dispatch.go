@@ -0,0 +1,235 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/node"
+	"github.com/masa-finance/masa-oracle/pkg/metrics"
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// errWorkCancelled is reported by work that was abandoned, because another worker answered first or the requester
+// is no longer waiting for the result.
+var errWorkCancelled = errors.New("work cancelled")
+
+// cancelledResponse returns the response of abandoned work.
+func cancelledResponse() data_types.WorkResponse {
+	return data_types.NewErrorResponse(data_types.ErrorCancelled, "%v", errWorkCancelled)
+}
+
+// dispatchSettings returns the fan-out width and hedge delay for a work request, falling back to the
+// worker configuration for any value the request does not set.
+func dispatchSettings(workRequest data_types.WorkRequest) (fanOut int, hedgeDelay time.Duration) {
+	fanOut = workerConfig.DispatchFanOut
+	hedgeDelay = workerConfig.DispatchHedgeDelay
+	if opts := workRequest.Dispatch; opts != nil {
+		if opts.FanOut > 0 {
+			fanOut = opts.FanOut
+		}
+		if opts.HedgeDelayMs > 0 {
+			hedgeDelay = time.Duration(opts.HedgeDelayMs) * time.Millisecond
+		}
+	}
+	if fanOut > workerConfig.MaxDispatchFanOut {
+		fanOut = workerConfig.MaxDispatchFanOut
+	}
+	return fanOut, hedgeDelay
+}
+
+// tryRemoteWorker looks up and connects to a remote worker, then sends it the work request.
+// Lookup and connection failures are reported through the returned response's Error field.
+func (whm *WorkHandlerManager) tryRemoteWorker(ctx context.Context, node *node.OracleNode, worker data_types.Worker, workRequest data_types.WorkRequest, onRunning func(peerId string)) (response data_types.WorkResponse) {
+	category := data_types.WorkerTypeToCategory(workRequest.WorkType)
+	peerId := worker.NodeData.PeerId.String()
+	if !whm.breakers.allow(peerId) {
+		return data_types.NewErrorResponse(data_types.ErrorUpstreamUnavailable, "circuit breaker of peer %s is open", peerId)
+	}
+	started := time.Now()
+	defer func() {
+		// Only failures the worker is at fault for count towards opening its circuit breaker
+		if code := response.Code(); code == "" || code.WorkerFault() {
+			whm.breakers.record(peerId, code != "")
+			if code != "" {
+				metrics.DispatchFailures.WithLabelValues(peerId, string(code)).Inc()
+			}
+		} else {
+			whm.breakers.release(peerId)
+		}
+		observeExecution(workRequest, metrics.ExecutionRemote, response, started)
+	}()
+
+	// Attempt to connect to the worker
+	findCtx, cancel := context.WithTimeout(ctx, workerConfig.FindPeerTimeout)
+	peerInfo, err := node.DHT.FindPeer(findCtx, worker.NodeData.PeerId)
+	cancel()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return cancelledResponse()
+		}
+		if err == context.DeadlineExceeded {
+			logrus.Warnf("Timeout while finding peer %s in DHT", worker.NodeData.PeerId.String())
+		} else {
+			logrus.Warnf("Failed to find peer %s in DHT: %v", worker.NodeData.PeerId.String(), err)
+		}
+		if err := node.NodeTracker.RecordWorkOutcome(worker.NodeData.PeerId.String(), category, pubsub.OutcomeNotFound, 0); err != nil {
+			logrus.Warnf("Failed to update node data for peer %s: %v", worker.NodeData.PeerId.String(), err)
+		}
+		return data_types.NewErrorResponse(data_types.ErrorUpstreamUnavailable, "failed to find peer in DHT: %v", err)
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, workerConfig.ConnectionTimeout)
+	err = node.Host.Connect(connectCtx, peerInfo)
+	cancel()
+	if err != nil {
+		logrus.Warnf("Failed to connect to peer %s: %v", worker.NodeData.PeerId.String(), err)
+		return data_types.NewErrorResponse(data_types.ErrorUpstreamUnavailable, "failed to connect to peer: %v", err)
+	}
+
+	worker.AddrInfo = &peerInfo
+	done := whm.peerLoad.start(worker.NodeData.PeerId.String())
+	defer done()
+	response = whm.sendWorkToWorker(ctx, node, worker, workRequest, onRunning)
+	code := response.Code()
+	if code == data_types.ErrorWorkerBusy {
+		// A busy worker is skipped without counting against its reputation
+		logrus.Infof("Worker %s is busy, retry after %dms", worker.NodeData.PeerId, response.RetryAfterMs)
+		whm.busyPeers.mark(worker.NodeData.PeerId.String(), time.Duration(response.RetryAfterMs)*time.Millisecond)
+		return response
+	}
+	if code != "" && code.WorkerFault() {
+		whm.eventTracker.TrackWorkerFailure(workRequest.WorkType, response.Error, worker.AddrInfo.ID.String())
+		logrus.Errorf("error sending work to worker: %s: %s", worker.NodeData.PeerId, response.Error)
+	}
+	return response
+}
+
+// dispatchConcurrently sends the work request to up to fanOut remote workers at once. Whenever an attempt
+// fails the next worker in line takes its place, and if hedgeDelay is set an additional worker is started
+// each time the delay passes without an answer. The first successful response, or the first failure that is
+// not worth retrying, is returned and every other attempt still in flight is cancelled. done is false if every
+// worker failed with an error that may not happen on another worker.
+func (whm *WorkHandlerManager) dispatchConcurrently(ctx context.Context, node *node.OracleNode, remoteWorkers []data_types.Worker, workRequest data_types.WorkRequest, fanOut int, hedgeDelay time.Duration, onRunning func(peerId string)) (response data_types.WorkResponse, failures workFailures, done bool) {
+	if len(remoteWorkers) == 0 {
+		return response, nil, false
+	}
+	if fanOut < 1 {
+		fanOut = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type attempt struct {
+		worker   data_types.Worker
+		response data_types.WorkResponse
+	}
+	results := make(chan attempt, len(remoteWorkers))
+	next, inFlight := 0, 0
+	launch := func() {
+		worker := remoteWorkers[next]
+		next++
+		inFlight++
+		logrus.Infof("Attempting remote worker %s (attempt %d/%d, %d in flight)", worker.NodeData.PeerId, next, len(remoteWorkers), inFlight)
+		go func() {
+			results <- attempt{worker: worker, response: whm.tryRemoteWorker(ctx, node, worker, workRequest, onRunning)}
+		}()
+	}
+
+	for next < len(remoteWorkers) && inFlight < fanOut {
+		launch()
+	}
+
+	var hedge <-chan time.Time
+	if hedgeDelay > 0 {
+		ticker := time.NewTicker(hedgeDelay)
+		defer ticker.Stop()
+		hedge = ticker.C
+	}
+
+	for inFlight > 0 {
+		select {
+		case result := <-results:
+			inFlight--
+			if result.response.Error == "" {
+				logrus.Infof("Remote worker %s answered first, cancelling %d other attempts", result.worker.NodeData.PeerId, inFlight)
+				return result.response, failures, true
+			}
+			if ctx.Err() != nil {
+				// The requester gave up, there is no point in trying other workers
+				return result.response, failures, false
+			}
+			if !result.response.Code().Retryable() {
+				logrus.Infof("Remote worker %s failed with %s, not trying other workers", result.worker.NodeData.PeerId, result.response.Code())
+				return result.response, failures, true
+			}
+			failures.add(fmt.Sprintf("Worker %s", result.worker.NodeData.PeerId), result.response)
+			logrus.Infof("Remote worker %s failed, moving to next worker", result.worker.NodeData.PeerId)
+			if next < len(remoteWorkers) {
+				launch()
+			}
+		case <-hedge:
+			if next < len(remoteWorkers) {
+				logrus.Infof("No answer after %s, sending hedged request", hedgeDelay)
+				launch()
+			}
+		}
+	}
+	return response, failures, false
+}
+
+// workFailure is a failed attempt at a work request.
+type workFailure struct {
+	source   string
+	response data_types.WorkResponse
+}
+
+// workFailures collects the failed attempts at a work request.
+type workFailures []workFailure
+
+func (f *workFailures) add(source string, response data_types.WorkResponse) {
+	*f = append(*f, workFailure{source: source, response: response})
+}
+
+// response combines the failures into the response of the work request. It carries the error code
+// shared by every attempt, or upstream_unavailable if the attempts failed for different reasons that
+// are all worth retrying. nextFree is used as the retry-after hint when every worker was busy.
+func (f workFailures) response(nextFree func() time.Duration) data_types.WorkResponse {
+	if len(f) == 0 {
+		return data_types.NewErrorResponse(data_types.ErrorUpstreamUnavailable, "no eligible workers found")
+	}
+
+	code := f[0].response.Code()
+	retryable := true
+	var retryAfterMs int64
+	errorList := make([]string, len(f))
+	for i, failure := range f {
+		errorList[i] = fmt.Sprintf("%s: %s", failure.source, failure.response.Error)
+		if failure.response.Code() != code {
+			code = data_types.ErrorUpstreamUnavailable
+		}
+		retryable = retryable && failure.response.Code().Retryable()
+		if failure.response.RetryAfterMs > retryAfterMs {
+			retryAfterMs = failure.response.RetryAfterMs
+		}
+	}
+	if code == data_types.ErrorUpstreamUnavailable && !retryable {
+		code = data_types.ErrorInternal
+	}
+
+	response := data_types.NewErrorResponse(code, "All workers failed. Errors: %s", strings.Join(errorList, "; "))
+	switch code {
+	case data_types.ErrorWorkerBusy:
+		response.Busy = true
+		response.RetryAfterMs = nextFree().Milliseconds()
+	case data_types.ErrorRateLimited:
+		response.RetryAfterMs = retryAfterMs
+	}
+	return response
+}
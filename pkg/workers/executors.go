@@ -0,0 +1,65 @@
+package workers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// ParseJobExecutors parses a job executor configuration of the form "web=local,twitter=fixture" into the
+// name of the executor per work type, see tee.NewExecutor.
+func ParseJobExecutors(spec string) (map[data_types.WorkerType]string, error) {
+	result := make(map[data_types.WorkerType]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		workType, name, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid job executor entry %q, expected type=executor", entry)
+		}
+		wType := data_types.WorkerType(strings.TrimSpace(workType))
+		if data_types.WorkerTypeToCategory(wType) < 0 {
+			return nil, fmt.Errorf("unknown work type %q", wType)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case tee.ExecutorRemote, tee.ExecutorFixture, tee.ExecutorLocal:
+		default:
+			return nil, fmt.Errorf("unknown job executor %q for %s", name, wType)
+		}
+		result[wType] = name
+	}
+	return result, nil
+}
+
+// newJobExecutors returns the configured job executor per work type. Work types that are not configured,
+// or whose executor cannot be created, are left out and run their jobs on the remote TEE worker.
+func newJobExecutors(spec string, fixturesDir string) map[data_types.WorkerType]tee.JobExecutor {
+	names, err := ParseJobExecutors(spec)
+	if err != nil {
+		logrus.Errorf("[-] Invalid job executors %q, using the remote TEE worker: %v", spec, err)
+		return nil
+	}
+	executors := make(map[data_types.WorkerType]tee.JobExecutor, len(names))
+	byName := make(map[string]tee.JobExecutor)
+	for wType, name := range names {
+		executor, ok := byName[name]
+		if !ok {
+			executor, err = tee.NewExecutor(name, fixturesDir)
+			if err != nil {
+				logrus.Errorf("[-] Failed to create the %s job executor for %s, using the remote TEE worker: %v", name, wType, err)
+				continue
+			}
+			byName[name] = executor
+		}
+		executors[wType] = executor
+		logrus.Infof("[+] Using the %s job executor for %s work", name, wType)
+	}
+	return executors
+}
@@ -0,0 +1,88 @@
+package workers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func TestParseJobExecutors(t *testing.T) {
+	executors, err := ParseJobExecutors("web=Local, twitter=fixture,discord-profile=remote")
+	require.NoError(t, err)
+	assert.Equal(t, map[data_types.WorkerType]string{
+		data_types.Web:            tee.ExecutorLocal,
+		data_types.Twitter:        tee.ExecutorFixture,
+		data_types.DiscordProfile: tee.ExecutorRemote,
+	}, executors)
+
+	executors, err = ParseJobExecutors("")
+	require.NoError(t, err)
+	assert.Empty(t, executors)
+
+	_, err = ParseJobExecutors("web")
+	assert.Error(t, err)
+	_, err = ParseJobExecutors("mastodon=local")
+	assert.Error(t, err)
+	_, err = ParseJobExecutors("web=sgx")
+	assert.Error(t, err)
+}
+
+func TestNewJobExecutors(t *testing.T) {
+	// The fixture executor cannot be created without fixtures, so twitter falls back to the remote TEE worker
+	executors := newJobExecutors("web=local,twitter=fixture", t.TempDir())
+	require.Len(t, executors, 1)
+	assert.IsType(t, &tee.LocalExecutor{}, executors[data_types.Web])
+}
+
+// handledWorkTypes returns the work types the manager advertises.
+func handledWorkTypes(whm *WorkHandlerManager) []data_types.WorkerType {
+	var workTypes []data_types.WorkerType
+	for _, capability := range whm.Capabilities() {
+		workTypes = append(workTypes, data_types.WorkerType(capability.WorkerType))
+	}
+	return workTypes
+}
+
+func TestDiscordHandlersNeedAnExecutor(t *testing.T) {
+	// The TEE worker has no Discord jobs, so the handlers are left out rather than failing every request
+	whm := NewWorkHandlerManager(EnableDiscordScraperWorker, EnableWebScraperWorker)
+	assert.Equal(t, []data_types.WorkerType{data_types.Web}, handledWorkTypes(whm))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "discord.json"), []byte(
+		`{"type": "discord-scraper", "arguments": {"type": "getprofile"}, "result": {"id": "42", "username": "masa"}}`), 0644))
+	whm = NewWorkHandlerManager(EnableDiscordScraperWorker, WithJobExecutors("discord-profile=fixture"), WithJobFixturesDir(dir))
+	assert.Equal(t, []data_types.WorkerType{data_types.DiscordProfile}, handledWorkTypes(whm))
+
+	response := whm.ExecuteWork(context.Background(), data_types.WorkRequest{WorkType: data_types.DiscordProfile, RequestId: "req-1", Data: []byte(`{"userID":"42"}`)})
+	require.Empty(t, response.Error)
+	assert.Equal(t, `{"id": "42", "username": "masa"}`, response.Data)
+}
+
+func TestTelegramHandlerNeedsAnExecutor(t *testing.T) {
+	// Without an executor that runs Telegram jobs the node does not advertise a Telegram capability
+	whm := NewWorkHandlerManager(EnableTelegramScraperWorker)
+	assert.Empty(t, handledWorkTypes(whm))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "telegram.json"), []byte(
+		`{"type": "telegram-scraper", "arguments": {"type": "getchannelmessages", "username": "masafinance"}, "result": [{"id": 120, "text": "gm"}]}`), 0644))
+	whm = NewWorkHandlerManager(EnableTelegramScraperWorker, WithJobExecutors("telegram-channel-messages=fixture"), WithJobFixturesDir(dir))
+	assert.Equal(t, []data_types.WorkerType{data_types.TelegramChannelMessages}, handledWorkTypes(whm))
+
+	response := whm.ExecuteWork(context.Background(), data_types.WorkRequest{
+		WorkType:  data_types.TelegramChannelMessages,
+		RequestId: "req-1",
+		Data:      []byte(`{"username":"masafinance","limit":20}`),
+	})
+	require.Empty(t, response.Error)
+	assert.Equal(t, `[{"id": 120, "text": "gm"}]`, response.Data)
+	assert.True(t, response.Unsealed)
+}
@@ -0,0 +1,486 @@
+package workers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/parquet-go/parquet-go"
+	"github.com/sirupsen/logrus"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// exportSettleTime is how long before an export starts results must have been stored to be exported, so that
+// results being saved while the export starts are not skipped by the next export.
+const exportSettleTime = 5 * time.Second
+
+// ErrInvalidCursor is returned for an export cursor that was not returned by a previous export.
+var ErrInvalidCursor = errors.New("invalid export cursor")
+
+// errSealedData is returned for the rows of results whose data was not unsealed for the export.
+var errSealedData = errors.New("the result data is sealed")
+
+// ExportFormat is the file format of an export.
+type ExportFormat string
+
+const (
+	ExportJSONL   ExportFormat = "jsonl"   // one stored result per line
+	ExportCSV     ExportFormat = "csv"     // one row per tweet, page section or result, with a header
+	ExportParquet ExportFormat = "parquet" // the rows of the CSV format
+)
+
+// ParseExportFormat returns the export format of the given name.
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch format := ExportFormat(strings.ToLower(name)); format {
+	case ExportJSONL, ExportCSV, ExportParquet:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q, expected jsonl, csv or parquet", name)
+	}
+}
+
+// ContentType returns the MIME type of the format.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportCSV:
+		return "text/csv"
+	case ExportParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// ExportQuery selects the stored results to export. Zero fields match any result.
+type ExportQuery struct {
+	WorkType data_types.WorkerType
+	From     time.Time // stored at or after
+	To       time.Time // stored before
+	Cursor   string    // the cursor of the previous export, to only export the results stored since
+	Client   string    // the API client exporting the results, whose results are unsealed, see StoredResult.UnsealableBy
+}
+
+// ResultExport is the range of stored results selected by an export query. Cursor resumes the next export
+// where this one ends, so that running the same query with it only exports the results stored since.
+type ResultExport struct {
+	Cursor   string
+	WorkType data_types.WorkerType
+	client   string
+	store    *ResultStore
+	from, to time.Time
+}
+
+// Export returns the export of the stored results selected by the query. The export ends a few seconds
+// before now at the latest, so it can be resumed without missing results that were being stored.
+func (s *ResultStore) Export(query ExportQuery, now time.Time) (*ResultExport, error) {
+	from, to := query.From, now.Add(-exportSettleTime)
+	if !query.To.IsZero() && query.To.Before(to) {
+		to = query.To
+	}
+	if query.Cursor != "" {
+		nanos, err := strconv.ParseInt(query.Cursor, 10, 64)
+		if err != nil || nanos <= 0 {
+			return nil, ErrInvalidCursor
+		}
+		if cursor := time.Unix(0, nanos); cursor.After(from) {
+			from = cursor
+		}
+	}
+	// The cursor never moves back, even if the export ends before the previous one
+	next := to
+	if from.After(next) {
+		next = from
+	}
+	return &ResultExport{
+		Cursor:   strconv.FormatInt(next.UnixNano(), 10),
+		WorkType: query.WorkType,
+		client:   query.Client,
+		store:    s,
+		from:     from,
+		to:       to,
+	}, nil
+}
+
+// Each calls fn with the exported results, oldest first, and stops at the first error it returns.
+func (e *ResultExport) Each(fn func(StoredResult) error) error {
+	if !e.from.Before(e.to) {
+		return nil
+	}
+	return e.store.db.View(func(txn *badger.Txn) error {
+		iterator := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iterator.Close()
+
+		prefix := []byte(e.store.keyPrefix)
+		seek := prefix
+		if !e.from.IsZero() {
+			seek = []byte(fmt.Sprintf("%s%020d", e.store.keyPrefix, e.from.UnixNano()))
+		}
+		end := []byte(fmt.Sprintf("%s%020d", e.store.keyPrefix, e.to.UnixNano()))
+		for iterator.Seek(seek); iterator.ValidForPrefix(prefix); iterator.Next() {
+			item := iterator.Item()
+			if bytes.Compare(item.Key(), end) >= 0 {
+				break
+			}
+			var result StoredResult
+			if err := item.Value(func(value []byte) error {
+				return json.Unmarshal(value, &result)
+			}); err != nil {
+				return err
+			}
+			if e.WorkType != "" && result.WorkType != e.WorkType {
+				continue
+			}
+			if err := fn(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WriteExport writes the exported results to w in the given format, and returns how many results it wrote.
+// The sealed results the exporting client requested are unsealed through the TEE, and the export fails if
+// one cannot be, so that the cursor of an incomplete export is not used. Other sealed results are written
+// with their sealed data. In CSV and Parquet, Twitter search results are written as one row per tweet and
+// web results as one row per page section, leaving out sealed results; other results, and exports of every
+// work type, are written as one row per result with the data as JSON.
+func WriteExport(w io.Writer, export *ResultExport, format ExportFormat) (int, error) {
+	if format == ExportJSONL {
+		return writeExportJSONL(w, export)
+	}
+	switch export.WorkType {
+	case data_types.Twitter:
+		return writeExportRows(w, export, format, tweetRows)
+	case data_types.Web:
+		return writeExportRows(w, export, format, pageRows)
+	default:
+		return writeExportRows(w, export, format, resultRows)
+	}
+}
+
+// exportedResult returns the stored result as exported to the client, with its data unsealed if the client
+// may unseal it. The requester is left out, as it identifies the client.
+func exportedResult(result StoredResult, client string) (StoredResult, error) {
+	unsealable := result.UnsealableBy(client)
+	result.Requester = ""
+	if !unsealable && !result.Unsealed {
+		return result, nil
+	}
+	response := result.Response()
+	if err := response.UnsealDataIfNeeded(data_types.WorkRequest{WorkType: result.WorkType, RequestId: result.ID}, nil); err != nil {
+		return result, fmt.Errorf("failed to unseal the result of request %s: %w", result.ID, err)
+	}
+	result.Data = response.Data
+	result.Unsealed = true
+	return result, nil
+}
+
+func writeExportJSONL(w io.Writer, export *ResultExport) (int, error) {
+	encoder := json.NewEncoder(w)
+	written := 0
+	err := export.Each(func(result StoredResult) error {
+		result, err := exportedResult(result, export.client)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+		written++
+		return nil
+	})
+	return written, err
+}
+
+// writeExportRows writes the rows of each exported result, as returned by rowsOf, as CSV or Parquet. The
+// columns are the fields of the row type, named after their parquet tags.
+func writeExportRows[T any](w io.Writer, export *ResultExport, format ExportFormat, rowsOf func(StoredResult) ([]T, error)) (int, error) {
+	var write func([]T) error
+	var flush func() error
+	switch format {
+	case ExportCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader(reflect.TypeOf((*T)(nil)).Elem())); err != nil {
+			return 0, err
+		}
+		write = func(rows []T) error {
+			for _, row := range rows {
+				if err := writer.Write(csvRecord(reflect.ValueOf(row))); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case ExportParquet:
+		writer := parquet.NewGenericWriter[T](w)
+		write = func(rows []T) error {
+			_, err := writer.Write(rows)
+			return err
+		}
+		flush = writer.Close
+	default:
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+
+	written := 0
+	err := export.Each(func(result StoredResult) error {
+		result, err := exportedResult(result, export.client)
+		if err != nil {
+			return err
+		}
+		rows, err := rowsOf(result)
+		if err != nil {
+			// A result the schema does not fit would fail every later export, so it is left out
+			logrus.Warnf("[-] Leaving the result of request %s out of the export: %v", result.ID, err)
+			return nil
+		}
+		if err := write(rows); err != nil {
+			return err
+		}
+		written++
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+	return written, flush()
+}
+
+// csvHeader returns the column names of a row type.
+func csvHeader(rowType reflect.Type) []string {
+	header := make([]string, rowType.NumField())
+	for i := range header {
+		field := rowType.Field(i)
+		header[i], _, _ = strings.Cut(field.Tag.Get("parquet"), ",")
+		if header[i] == "" {
+			header[i] = field.Name
+		}
+	}
+	return header
+}
+
+// csvRecord returns the columns of a row. Lists are joined with spaces.
+func csvRecord(row reflect.Value) []string {
+	record := make([]string, row.NumField())
+	for i := range record {
+		switch value := row.Field(i).Interface().(type) {
+		case string:
+			record[i] = value
+		case int64:
+			record[i] = strconv.FormatInt(value, 10)
+		case bool:
+			record[i] = strconv.FormatBool(value)
+		case time.Time:
+			if !value.IsZero() {
+				record[i] = value.UTC().Format(time.RFC3339Nano)
+			}
+		case []string:
+			record[i] = strings.Join(value, " ")
+		default:
+			record[i] = fmt.Sprint(value)
+		}
+	}
+	return record
+}
+
+// resultRow is the row of a result of any work type.
+type resultRow struct {
+	RequestID    string    `parquet:"request_id"`
+	WorkType     string    `parquet:"work_type"`
+	WorkerPeerID string    `parquet:"worker_peer_id"`
+	StoredAt     time.Time `parquet:"stored_at"`
+	ContentHash  string    `parquet:"content_hash"`
+	Verification string    `parquet:"verification"`
+	Request      string    `parquet:"request"` // the request data as JSON
+	Data         string    `parquet:"data"`    // the result data as JSON
+	Sealed       bool      `parquet:"sealed"`  // Data is the sealed result, as a JSON string
+}
+
+func resultRows(result StoredResult) ([]resultRow, error) {
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, err
+	}
+	return []resultRow{{
+		RequestID:    result.ID,
+		WorkType:     string(result.WorkType),
+		WorkerPeerID: result.WorkerPeerId,
+		StoredAt:     result.StoredAt,
+		ContentHash:  result.ContentHash,
+		Verification: string(result.Verification),
+		Request:      string(result.Request),
+		Data:         string(data),
+		Sealed:       !result.Unsealed,
+	}}, nil
+}
+
+// exportTweet mirrors the exported fields of the tweets of the TEE worker's Twitter search results.
+type exportTweet struct {
+	ID                string
+	ConversationID    string
+	UserID            string
+	Username          string
+	Name              string
+	Text              string
+	TimeParsed        time.Time
+	Likes             int64
+	Retweets          int64
+	Replies           int64
+	Views             int64
+	Hashtags          []string
+	URLs              []string
+	PermanentURL      string
+	IsReply           bool
+	IsRetweet         bool
+	IsQuoted          bool
+	InReplyToStatusID string
+	QuotedStatusID    string
+	RetweetedStatusID string
+}
+
+// tweetRow is the row of a tweet of a Twitter search result.
+type tweetRow struct {
+	RequestID         string    `parquet:"request_id"`
+	WorkerPeerID      string    `parquet:"worker_peer_id"`
+	StoredAt          time.Time `parquet:"stored_at"`
+	Query             string    `parquet:"query"`
+	TweetID           string    `parquet:"tweet_id"`
+	ConversationID    string    `parquet:"conversation_id"`
+	UserID            string    `parquet:"user_id"`
+	Username          string    `parquet:"username"`
+	Name              string    `parquet:"name"`
+	Text              string    `parquet:"text"`
+	CreatedAt         time.Time `parquet:"created_at"`
+	Likes             int64     `parquet:"likes"`
+	Retweets          int64     `parquet:"retweets"`
+	Replies           int64     `parquet:"replies"`
+	Views             int64     `parquet:"views"`
+	Hashtags          []string  `parquet:"hashtags,list"`
+	URLs              []string  `parquet:"urls,list"`
+	PermanentURL      string    `parquet:"permanent_url"`
+	IsReply           bool      `parquet:"is_reply"`
+	IsRetweet         bool      `parquet:"is_retweet"`
+	IsQuoted          bool      `parquet:"is_quoted"`
+	InReplyToStatusID string    `parquet:"in_reply_to_status_id"`
+	QuotedStatusID    string    `parquet:"quoted_status_id"`
+	RetweetedStatusID string    `parquet:"retweeted_status_id"`
+}
+
+func tweetRows(result StoredResult) ([]tweetRow, error) {
+	var request struct {
+		Query string `json:"query"`
+	}
+	_ = json.Unmarshal(result.Request, &request)
+	var tweets []struct {
+		Tweet *exportTweet
+	}
+	if !result.Unsealed {
+		return nil, errSealedData
+	}
+	if err := decodeExportData(result.Data, &tweets); err != nil {
+		return nil, err
+	}
+	rows := make([]tweetRow, 0, len(tweets))
+	for _, item := range tweets {
+		// Tweets the scraper failed to get only have an error
+		if item.Tweet == nil {
+			continue
+		}
+		tweet := item.Tweet
+		rows = append(rows, tweetRow{
+			RequestID:         result.ID,
+			WorkerPeerID:      result.WorkerPeerId,
+			StoredAt:          result.StoredAt,
+			Query:             request.Query,
+			TweetID:           tweet.ID,
+			ConversationID:    tweet.ConversationID,
+			UserID:            tweet.UserID,
+			Username:          tweet.Username,
+			Name:              tweet.Name,
+			Text:              tweet.Text,
+			CreatedAt:         tweet.TimeParsed,
+			Likes:             tweet.Likes,
+			Retweets:          tweet.Retweets,
+			Replies:           tweet.Replies,
+			Views:             tweet.Views,
+			Hashtags:          tweet.Hashtags,
+			URLs:              tweet.URLs,
+			PermanentURL:      tweet.PermanentURL,
+			IsReply:           tweet.IsReply,
+			IsRetweet:         tweet.IsRetweet,
+			IsQuoted:          tweet.IsQuoted,
+			InReplyToStatusID: tweet.InReplyToStatusID,
+			QuotedStatusID:    tweet.QuotedStatusID,
+			RetweetedStatusID: tweet.RetweetedStatusID,
+		})
+	}
+	return rows, nil
+}
+
+// pageRow is the row of a section of a page of a web result.
+type pageRow struct {
+	RequestID    string    `parquet:"request_id"`
+	WorkerPeerID string    `parquet:"worker_peer_id"`
+	StoredAt     time.Time `parquet:"stored_at"`
+	URL          string    `parquet:"url"`     // the requested URL
+	Section      int64     `parquet:"section"` // the index of the section in the result
+	Title        string    `parquet:"title"`
+	Text         string    `parquet:"text"` // the paragraphs, separated by blank lines
+	Images       []string  `parquet:"images,list"`
+}
+
+func pageRows(result StoredResult) ([]pageRow, error) {
+	var request struct {
+		URL string `json:"url"`
+	}
+	_ = json.Unmarshal(result.Request, &request)
+	var page struct {
+		Sections []struct {
+			Title      string   `json:"title"`
+			Paragraphs []string `json:"paragraphs"`
+			Images     []string `json:"images"`
+		} `json:"sections"`
+	}
+	if !result.Unsealed {
+		return nil, errSealedData
+	}
+	if err := decodeExportData(result.Data, &page); err != nil {
+		return nil, err
+	}
+	rows := make([]pageRow, len(page.Sections))
+	for i, section := range page.Sections {
+		rows[i] = pageRow{
+			RequestID:    result.ID,
+			WorkerPeerID: result.WorkerPeerId,
+			StoredAt:     result.StoredAt,
+			URL:          request.URL,
+			Section:      int64(i),
+			Title:        section.Title,
+			Text:         strings.Join(section.Paragraphs, "\n\n"),
+			Images:       section.Images,
+		}
+	}
+	return rows, nil
+}
+
+// decodeExportData decodes unsealed result data, as decoded from JSON, into v.
+func decodeExportData(data interface{}, v interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, v)
+}
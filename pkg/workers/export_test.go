@@ -0,0 +1,288 @@
+package workers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+const exportTweets = `[
+	{"Tweet": {"ID": "1", "Username": "masa", "Text": "Hello $MASA", "TimeParsed": "2024-09-01T10:00:00Z", "Likes": 5, "Hashtags": ["masa", "ai"], "IsReply": true}},
+	{"Error": "rate limited"},
+	{"Tweet": {"ID": "2", "Username": "oracle", "Text": "gm", "Views": 100}}
+]`
+
+const exportPage = `{"sections": [
+	{"title": "Maize", "paragraphs": ["Maize is a cereal grain.", "It is also called corn."], "images": ["https://masa.ai/corn.png"]},
+	{"title": "History", "paragraphs": ["Domesticated in Mexico"]}
+], "pages": ["https://masa.ai/"]}`
+
+func TestResultExport(t *testing.T) {
+	start := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	save := func(t *testing.T, store *ResultStore, id string, workType data_types.WorkerType, request, data string, at time.Time) {
+		response := data_types.WorkResponse{Data: data, WorkerPeerId: "worker-1", Unsealed: true}
+		require.NoError(t, store.Save(data_types.WorkRequest{WorkType: workType, RequestId: id, Data: []byte(request)}, response, at))
+	}
+	exported := func(t *testing.T, export *ResultExport) []string {
+		ids := []string{}
+		require.NoError(t, export.Each(func(result StoredResult) error {
+			ids = append(ids, result.ID)
+			return nil
+		}))
+		return ids
+	}
+
+	t.Run("exports resume from the cursor of the previous one", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		save(t, store, "req-1", data_types.Twitter, `{"query":"a"}`, exportTweets, start)
+		save(t, store, "req-2", data_types.Web, `{"url":"https://masa.ai"}`, exportPage, start.Add(time.Minute))
+		// Stored too recently to be exported yet
+		save(t, store, "req-3", data_types.Twitter, `{"query":"b"}`, exportTweets, start.Add(2*time.Minute))
+
+		first, err := store.Export(ExportQuery{}, start.Add(2*time.Minute+time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-1", "req-2"}, exported(t, first))
+
+		save(t, store, "req-4", data_types.Twitter, `{"query":"c"}`, exportTweets, start.Add(3*time.Minute))
+		next, err := store.Export(ExportQuery{Cursor: first.Cursor}, start.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-3", "req-4"}, exported(t, next))
+
+		// Nothing is exported twice
+		last, err := store.Export(ExportQuery{Cursor: next.Cursor}, start.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, exported(t, last))
+	})
+
+	t.Run("exports are filtered by work type and time range", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		save(t, store, "req-1", data_types.Twitter, `{"query":"a"}`, exportTweets, start)
+		save(t, store, "req-2", data_types.Web, `{"url":"https://masa.ai"}`, exportPage, start.Add(time.Minute))
+		save(t, store, "req-3", data_types.Twitter, `{"query":"b"}`, exportTweets, start.Add(2*time.Minute))
+		now := start.Add(time.Hour)
+
+		export, err := store.Export(ExportQuery{WorkType: data_types.Twitter}, now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-1", "req-3"}, exported(t, export))
+
+		export, err = store.Export(ExportQuery{From: start.Add(time.Minute), To: start.Add(2 * time.Minute)}, now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-2"}, exported(t, export))
+
+		// An earlier end does not move the cursor back
+		later, err := store.Export(ExportQuery{To: start.Add(-time.Hour), Cursor: export.Cursor}, now)
+		require.NoError(t, err)
+		assert.Empty(t, exported(t, later))
+		assert.Equal(t, export.Cursor, later.Cursor)
+
+		_, err = store.Export(ExportQuery{Cursor: "yesterday"}, now)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("JSON lines hold the stored results with their data", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		save(t, store, "req-1", data_types.Web, `{"url":"https://masa.ai"}`, exportPage, start)
+		export, err := store.Export(ExportQuery{}, start.Add(time.Hour))
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		written, err := WriteExport(&out, export, ExportJSONL)
+		require.NoError(t, err)
+		assert.Equal(t, 1, written)
+		var line struct {
+			ID   string
+			Data struct {
+				Pages []string `json:"pages"`
+			}
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &line))
+		assert.Equal(t, "req-1", line.ID)
+		assert.Equal(t, []string{"https://masa.ai/"}, line.Data.Pages)
+	})
+
+	t.Run("sealed results are only unsealed for the client that requested them", func(t *testing.T) {
+		// The TEE worker decrypts results by stripping their "sealed:" prefix
+		tee := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				EncryptedResult string `json:"encrypted_result"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_, _ = w.Write([]byte(strings.TrimPrefix(req.EncryptedResult, "sealed:")))
+		}))
+		defer tee.Close()
+		t.Setenv("TEE_WORKER_URL", tee.URL)
+
+		store := openTestResultStore(t, 0, 0)
+		request := data_types.WorkRequest{WorkType: data_types.Web, RequestId: "req-1", Data: []byte(`{"url":"https://masa.ai"}`), Requester: "client-1"}
+		require.NoError(t, store.Save(request, data_types.WorkResponse{Data: "sealed:" + exportPage, WorkerPeerId: "worker-1"}, start))
+
+		lines := func(client string) []StoredResult {
+			export, err := store.Export(ExportQuery{Client: client}, start.Add(time.Hour))
+			require.NoError(t, err)
+			var out bytes.Buffer
+			_, err = WriteExport(&out, export, ExportJSONL)
+			require.NoError(t, err)
+			var results []StoredResult
+			decoder := json.NewDecoder(&out)
+			for decoder.More() {
+				var result StoredResult
+				require.NoError(t, decoder.Decode(&result))
+				results = append(results, result)
+			}
+			return results
+		}
+
+		own := lines("client-1")
+		require.Len(t, own, 1)
+		assert.True(t, own[0].Unsealed)
+		assert.Contains(t, own[0].Data, "sections")
+		assert.Empty(t, own[0].Requester, "the requester is not exported")
+
+		for _, client := range []string{"client-2", ""} {
+			other := lines(client)
+			require.Len(t, other, 1)
+			assert.False(t, other[0].Unsealed, client)
+			assert.Equal(t, "sealed:"+exportPage, other[0].Data, client)
+			assert.Empty(t, other[0].Requester, client)
+		}
+
+		// Sealed results cannot be flattened, and are left out
+		export, err := store.Export(ExportQuery{WorkType: data_types.Web, Client: "client-2"}, start.Add(time.Hour))
+		require.NoError(t, err)
+		written, err := WriteExport(&bytes.Buffer{}, export, ExportCSV)
+		require.NoError(t, err)
+		assert.Zero(t, written)
+	})
+
+	t.Run("CSV exports flatten tweets and pages", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		save(t, store, "req-1", data_types.Twitter, `{"query":"$MASA"}`, exportTweets, start)
+		save(t, store, "req-2", data_types.Twitter, `{"query":"broken"}`, `{"not":"tweets"}`, start.Add(time.Second))
+		save(t, store, "req-3", data_types.Web, `{"url":"https://masa.ai"}`, exportPage, start.Add(time.Minute))
+
+		records := func(workType data_types.WorkerType) []map[string]string {
+			export, err := store.Export(ExportQuery{WorkType: workType}, start.Add(time.Hour))
+			require.NoError(t, err)
+			var out bytes.Buffer
+			_, err = WriteExport(&out, export, ExportCSV)
+			require.NoError(t, err)
+			lines, err := csv.NewReader(&out).ReadAll()
+			require.NoError(t, err)
+			var records []map[string]string
+			for _, line := range lines[1:] {
+				record := map[string]string{}
+				for i, column := range lines[0] {
+					record[column] = line[i]
+				}
+				records = append(records, record)
+			}
+			return records
+		}
+
+		tweets := records(data_types.Twitter)
+		require.Len(t, tweets, 2)
+		assert.Equal(t, "req-1", tweets[0]["request_id"])
+		assert.Equal(t, "$MASA", tweets[0]["query"])
+		assert.Equal(t, "1", tweets[0]["tweet_id"])
+		assert.Equal(t, "Hello $MASA", tweets[0]["text"])
+		assert.Equal(t, "2024-09-01T10:00:00Z", tweets[0]["created_at"])
+		assert.Equal(t, "5", tweets[0]["likes"])
+		assert.Equal(t, "masa ai", tweets[0]["hashtags"])
+		assert.Equal(t, "true", tweets[0]["is_reply"])
+		assert.Equal(t, "2", tweets[1]["tweet_id"])
+		assert.Equal(t, "100", tweets[1]["views"])
+
+		pages := records(data_types.Web)
+		require.Len(t, pages, 2)
+		assert.Equal(t, "https://masa.ai", pages[0]["url"])
+		assert.Equal(t, "Maize", pages[0]["title"])
+		assert.Equal(t, "Maize is a cereal grain.\n\nIt is also called corn.", pages[0]["text"])
+		assert.Equal(t, "https://masa.ai/corn.png", pages[0]["images"])
+		assert.Equal(t, "1", pages[1]["section"])
+
+		all := records("")
+		require.Len(t, all, 3)
+		assert.Equal(t, "twitter", all[0]["work_type"])
+		assert.JSONEq(t, `{"query":"$MASA"}`, all[0]["request"])
+		assert.True(t, strings.Contains(all[2]["data"], "Maize"))
+	})
+
+	t.Run("Parquet exports hold the rows of the CSV exports", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		save(t, store, "req-1", data_types.Twitter, `{"query":"$MASA"}`, exportTweets, start)
+		export, err := store.Export(ExportQuery{WorkType: data_types.Twitter}, start.Add(time.Hour))
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		written, err := WriteExport(&out, export, ExportParquet)
+		require.NoError(t, err)
+		assert.Equal(t, 1, written)
+		rows, err := parquet.Read[tweetRow](bytes.NewReader(out.Bytes()), int64(out.Len()))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "masa", rows[0].Username)
+		assert.Equal(t, []string{"masa", "ai"}, rows[0].Hashtags)
+		assert.True(t, rows[0].StoredAt.Equal(start))
+		assert.Equal(t, int64(100), rows[1].Views)
+	})
+}
+
+func TestExportSpool(t *testing.T) {
+	now := time.Now()
+	request := data_types.WorkRequest{WorkType: data_types.Web, RequestId: "req-1", Data: []byte(`{"url":"https://masa.ai"}`)}
+
+	t.Run("results passing through the work manager are spooled without the result store", func(t *testing.T) {
+		whm := NewWorkHandlerManager(WithMasaDir(t.TempDir()), EnableExportSpool)
+		defer whm.Close()
+		require.Nil(t, whm.Results())
+		require.NotNil(t, whm.ExportSpool())
+
+		whm.storeResult(request, data_types.WorkResponse{Data: exportPage, Unsealed: true})
+		whm.storeResult(data_types.WorkRequest{RequestId: "req-2"}, data_types.WorkResponse{Error: "rate limited"})
+
+		export, err := whm.ExportSpool().Export(ExportQuery{}, now.Add(time.Hour))
+		require.NoError(t, err)
+		ids := []string{}
+		require.NoError(t, export.Each(func(result StoredResult) error {
+			ids = append(ids, result.ID)
+			return nil
+		}))
+		assert.Equal(t, []string{"req-1"}, ids)
+	})
+
+	t.Run("the spool keeps its results apart from the result store", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		spool := NewExportSpool(store.db, time.Hour, 0)
+		require.NoError(t, spool.Save(request, data_types.WorkResponse{Data: exportPage, Unsealed: true}, now.Add(-2*time.Hour)))
+
+		results, err := store.Query(ResultQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		// The spool is pruned by its own retention
+		dropped, err := spool.Prune(now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, dropped)
+	})
+}
+
+func TestParseExportFormat(t *testing.T) {
+	format, err := ParseExportFormat("CSV")
+	require.NoError(t, err)
+	assert.Equal(t, ExportCSV, format)
+	assert.Equal(t, "text/csv", format.ContentType())
+
+	_, err = ParseExportFormat("xlsx")
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// discordScraperJob is the TEE worker job type for Discord.
+const discordScraperJob = "discord-scraper"
+
+type DiscordProfileHandler struct{ Executor tee.JobExecutor }
+type DiscordChannelMessagesHandler struct{ Executor tee.JobExecutor }
+type DiscordGuildChannelsHandler struct{ Executor tee.JobExecutor }
+type DiscordUserGuildsHandler struct{ Executor tee.JobExecutor }
+
+func (h *DiscordProfileHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
+	logrus.Infof("[+] DiscordProfileHandler %s", data)
+	var request data_types.DiscordProfileRequest
+	if err := data_types.DecodeRequestData(data_types.DiscordProfile, data, &request); err != nil {
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse discord profile data: %v", err)
+	}
+
+	result, err := runJob(ctx, h.Executor, discordScraperJob, map[string]interface{}{
+		"type":   "getprofile",
+		"userID": request.UserID,
+	})
+	if err != nil {
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to get discord profile: %v", err)
+	}
+
+	logrus.Infof("[+] DiscordProfileHandler Work response for %s: %v", data_types.DiscordProfile, result)
+	return jobResponse(h.Executor, result)
+}
+
+func (h *DiscordChannelMessagesHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
+	logrus.Infof("[+] DiscordChannelMessagesHandler %s", data)
+	var request data_types.DiscordChannelMessagesRequest
+	if err := data_types.DecodeRequestData(data_types.DiscordChannelMessages, data, &request); err != nil {
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse discord channel messages data: %v", err)
+	}
+
+	arguments := map[string]interface{}{
+		"type":      "getchannelmessages",
+		"channelID": request.ChannelID,
+	}
+	if request.Limit > 0 {
+		arguments["limit"] = request.Limit
+	}
+	if request.Before != "" {
+		arguments["before"] = request.Before
+	}
+	result, err := runJob(ctx, h.Executor, discordScraperJob, arguments)
+	if err != nil {
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to get discord channel messages: %v", err)
+	}
+
+	logrus.Infof("[+] DiscordChannelMessagesHandler Work response for %s: %v", data_types.DiscordChannelMessages, result)
+	return jobResponse(h.Executor, result)
+}
+
+func (h *DiscordGuildChannelsHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
+	logrus.Infof("[+] DiscordGuildChannelsHandler %s", data)
+	var request data_types.DiscordGuildChannelsRequest
+	if err := data_types.DecodeRequestData(data_types.DiscordGuildChannels, data, &request); err != nil {
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse discord guild channels data: %v", err)
+	}
+
+	result, err := runJob(ctx, h.Executor, discordScraperJob, map[string]interface{}{
+		"type":    "getguildchannels",
+		"guildID": request.GuildID,
+	})
+	if err != nil {
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to get discord guild channels: %v", err)
+	}
+
+	logrus.Infof("[+] DiscordGuildChannelsHandler Work response for %s: %v", data_types.DiscordGuildChannels, result)
+	return jobResponse(h.Executor, result)
+}
+
+func (h *DiscordUserGuildsHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
+	logrus.Infof("[+] DiscordUserGuildsHandler %s", data)
+	var request data_types.DiscordUserGuildsRequest
+	if err := data_types.DecodeRequestData(data_types.DiscordUserGuilds, data, &request); err != nil {
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse discord user guilds data: %v", err)
+	}
+
+	result, err := runJob(ctx, h.Executor, discordScraperJob, map[string]interface{}{
+		"type": "getuserguilds",
+	})
+	if err != nil {
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to get discord user guilds: %v", err)
+	}
+
+	logrus.Infof("[+] DiscordUserGuildsHandler Work response for %s: %v", data_types.DiscordUserGuilds, result)
+	return jobResponse(h.Executor, result)
+}
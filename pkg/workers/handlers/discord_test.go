@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	types "github.com/masa-finance/tee-worker/api/types"
+)
+
+// stubTeeWorker serves the job endpoints of the TEE worker, recording submitted jobs and answering with result.
+func stubTeeWorker(t *testing.T, result string) *[]types.Job {
+	var mu sync.Mutex
+	jobs := &[]types.Job{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /job", func(w http.ResponseWriter, r *http.Request) {
+		var job types.Job
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&job))
+		mu.Lock()
+		*jobs = append(*jobs, job)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(types.JobResponse{UID: "job-1"})
+	})
+	mux.HandleFunc("GET /job/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(result))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	t.Setenv("TEE_WORKER_URL", server.URL)
+	return jobs
+}
+
+func TestDiscordHandlers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("profile", func(t *testing.T) {
+		jobs := stubTeeWorker(t, `{"id":"42","username":"masa"}`)
+		response := (&DiscordProfileHandler{}).HandleWork(ctx, []byte(`{"userID":"42"}`))
+
+		require.Empty(t, response.Error)
+		assert.Equal(t, `{"id":"42","username":"masa"}`, response.Data)
+		require.Len(t, *jobs, 1)
+		assert.Equal(t, discordScraperJob, (*jobs)[0].Type)
+		assert.Equal(t, types.JobArguments{"type": "getprofile", "userID": "42"}, (*jobs)[0].Arguments)
+	})
+
+	t.Run("channel messages forward paging arguments", func(t *testing.T) {
+		jobs := stubTeeWorker(t, `[]`)
+		response := (&DiscordChannelMessagesHandler{}).HandleWork(ctx, []byte(`{"channelID":"7","limit":50,"before":"99"}`))
+
+		require.Empty(t, response.Error)
+		require.Len(t, *jobs, 1)
+		assert.Equal(t, types.JobArguments{"type": "getchannelmessages", "channelID": "7", "limit": float64(50), "before": "99"}, (*jobs)[0].Arguments)
+	})
+
+	t.Run("guild channels", func(t *testing.T) {
+		jobs := stubTeeWorker(t, `[]`)
+		response := (&DiscordGuildChannelsHandler{}).HandleWork(ctx, []byte(`{"guildID":"3"}`))
+
+		require.Empty(t, response.Error)
+		require.Len(t, *jobs, 1)
+		assert.Equal(t, types.JobArguments{"type": "getguildchannels", "guildID": "3"}, (*jobs)[0].Arguments)
+	})
+
+	t.Run("user guilds", func(t *testing.T) {
+		jobs := stubTeeWorker(t, `[]`)
+		response := (&DiscordUserGuildsHandler{}).HandleWork(ctx, []byte(`{}`))
+
+		require.Empty(t, response.Error)
+		require.Len(t, *jobs, 1)
+		assert.Equal(t, types.JobArguments{"type": "getuserguilds"}, (*jobs)[0].Arguments)
+	})
+
+	t.Run("missing identifiers are rejected without a job", func(t *testing.T) {
+		jobs := stubTeeWorker(t, `[]`)
+
+		assert.Contains(t, (&DiscordProfileHandler{}).HandleWork(ctx, []byte(`{}`)).Error, "userID is required")
+		assert.Contains(t, (&DiscordChannelMessagesHandler{}).HandleWork(ctx, []byte(`{}`)).Error, "channelID is required")
+		assert.Contains(t, (&DiscordGuildChannelsHandler{}).HandleWork(ctx, []byte(`not json`)).Error, "is not valid JSON")
+		assert.Empty(t, *jobs)
+	})
+
+	t.Run("worker errors are reported", func(t *testing.T) {
+		stubTeeWorker(t, `{"error":"unknown guild"}`)
+		response := (&DiscordGuildChannelsHandler{}).HandleWork(ctx, []byte(`{"guildID":"3"}`))
+
+		assert.Contains(t, response.Error, "unknown guild")
+	})
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// teeErrorCode classifies an error returned while running a job on the TEE worker. The TEE worker reports
+// the errors of its scrapers as text only, so this is the one place where their messages are matched.
+func teeErrorCode(err error) data_types.ErrorCode {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return data_types.ErrorCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return data_types.ErrorTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return data_types.ErrorTimeout
+	case errors.As(err, &netErr):
+		return data_types.ErrorUpstreamUnavailable
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.HasPrefix(message, "max retries reached"):
+		return data_types.ErrorTimeout
+	case strings.Contains(message, "rate limit"), strings.Contains(message, "429"):
+		return data_types.ErrorRateLimited
+	case strings.Contains(message, "authenticat"), strings.Contains(message, "credentials"), strings.Contains(message, "unauthorized"):
+		return data_types.ErrorAuthFailed
+	case strings.Contains(message, "not found"), strings.Contains(message, "does not exist"):
+		return data_types.ErrorNotFound
+	case strings.Contains(message, "received status code 5"), strings.Contains(message, "error sending"):
+		return data_types.ErrorUpstreamUnavailable
+	default:
+		return data_types.ErrorInternal
+	}
+}
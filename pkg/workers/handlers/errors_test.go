@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func TestTeeErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code data_types.ErrorCode
+	}{
+		{fmt.Errorf("waiting for job: %w", context.Canceled), data_types.ErrorCancelled},
+		{fmt.Errorf("waiting for job: %w", context.DeadlineExceeded), data_types.ErrorTimeout},
+		{errors.New("max retries reached: 10"), data_types.ErrorTimeout},
+		{errors.New("Twitter API rate limit exceeded (429 error)"), data_types.ErrorRateLimited},
+		{errors.New("unable to get twitter profile: there was an error authenticating with your Twitter credentials"), data_types.ErrorAuthFailed},
+		{errors.New("user not found"), data_types.ErrorNotFound},
+		{errors.New("received status code 502"), data_types.ErrorUpstreamUnavailable},
+		{errors.New("unexpected end of JSON input"), data_types.ErrorInternal},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, teeErrorCode(test.err), test.err.Error())
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+	types "github.com/masa-finance/tee-worker/api/types"
+)
+
+// JobType returns the TEE worker job type the handler of a work type runs, or "" if it has no handler.
+func JobType(workType data_types.WorkerType) string {
+	switch workType {
+	case data_types.Twitter, data_types.TwitterFollowers, data_types.TwitterProfile:
+		return twitterScraperJob
+	case data_types.DiscordProfile, data_types.DiscordChannelMessages, data_types.DiscordGuildChannels, data_types.DiscordUserGuilds:
+		return discordScraperJob
+	case data_types.TelegramChannelMessages:
+		return telegramScraperJob
+	case data_types.Web:
+		return webScraperJob
+	default:
+		return ""
+	}
+}
+
+// SupportedBy reports whether the executor, or the remote TEE worker if it is nil, can run the jobs of the
+// handler of a work type.
+func SupportedBy(executor tee.JobExecutor, workType data_types.WorkerType) bool {
+	return executorOrRemote(executor).Supports(JobType(workType))
+}
+
+// executorOrRemote returns the executor of a handler, or the remote TEE worker for handlers without one.
+func executorOrRemote(executor tee.JobExecutor) tee.JobExecutor {
+	if executor == nil {
+		return tee.RemoteExecutor{}
+	}
+	return executor
+}
+
+// runJob runs a job with the executor of a handler and returns its result.
+func runJob(ctx context.Context, executor tee.JobExecutor, jobType string, arguments map[string]interface{}) (string, error) {
+	return executorOrRemote(executor).ExecuteJob(ctx, types.Job{
+		Type:      jobType,
+		Arguments: arguments,
+	})
+}
+
+// jobResponse returns the work response carrying a job result of the executor of a handler.
+func jobResponse(executor tee.JobExecutor, result string) data_types.WorkResponse {
+	return data_types.WorkResponse{Data: result, Unsealed: !executorOrRemote(executor).Sealed()}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func TestHandlersWithExecutor(t *testing.T) {
+	ctx := context.Background()
+	executor := tee.NewFixtureExecutorWith(
+		tee.Fixture{Type: webScraperJob, Arguments: map[string]interface{}{"url": "https://masa.ai"}, Result: json.RawMessage(`{"pages":["https://masa.ai"]}`)},
+		tee.Fixture{Type: twitterScraperJob, Arguments: map[string]interface{}{"type": "searchbyprofile"}, Error: "rate limit exceeded"},
+	)
+
+	t.Run("results are not sealed", func(t *testing.T) {
+		response := (&WebHandler{Executor: executor}).HandleWork(ctx, []byte(`{"url":"https://masa.ai"}`))
+
+		require.Empty(t, response.Error)
+		assert.Equal(t, `{"pages":["https://masa.ai"]}`, response.Data)
+		assert.True(t, response.Unsealed)
+	})
+
+	t.Run("errors are classified", func(t *testing.T) {
+		response := (&TwitterProfileHandler{Executor: executor}).HandleWork(ctx, []byte(`{"username":"masa"}`))
+
+		assert.Equal(t, data_types.ErrorRateLimited, response.Code())
+	})
+
+	t.Run("jobs without a fixture fail", func(t *testing.T) {
+		response := (&DiscordUserGuildsHandler{Executor: executor}).HandleWork(ctx, []byte(`{}`))
+
+		assert.Contains(t, response.Error, "no fixture for discord-scraper job")
+	})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// telegramScraperJob is the TEE worker job type for Telegram.
+const telegramScraperJob = "telegram-scraper"
+
+type TelegramChannelMessagesHandler struct{ Executor tee.JobExecutor }
+
+func (h *TelegramChannelMessagesHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
+	logrus.Infof("[+] TelegramChannelMessagesHandler %s", data)
+	var request data_types.TelegramChannelMessagesRequest
+	if err := data_types.DecodeRequestData(data_types.TelegramChannelMessages, data, &request); err != nil {
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse telegram channel messages data: %v", err)
+	}
+
+	arguments := map[string]interface{}{
+		"type":     "getchannelmessages",
+		"username": request.Username,
+	}
+	if request.Limit > 0 {
+		arguments["limit"] = request.Limit
+	}
+	if request.OffsetID > 0 {
+		arguments["offsetID"] = request.OffsetID
+	}
+	if request.Since != nil {
+		arguments["since"] = request.Since.UTC().Format(time.RFC3339)
+	}
+	if request.Until != nil {
+		arguments["until"] = request.Until.UTC().Format(time.RFC3339)
+	}
+	result, err := runJob(ctx, h.Executor, telegramScraperJob, arguments)
+	if err != nil {
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to get telegram channel messages: %v", err)
+	}
+
+	logrus.Infof("[+] TelegramChannelMessagesHandler Work response for %s: %v", data_types.TelegramChannelMessages, result)
+	return jobResponse(h.Executor, result)
+}
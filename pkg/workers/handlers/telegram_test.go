@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+	types "github.com/masa-finance/tee-worker/api/types"
+)
+
+func TestTelegramChannelMessagesHandler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("forwards paging and date range arguments", func(t *testing.T) {
+		jobs := stubTeeWorker(t, `[{"id":120,"text":"gm"}]`)
+		response := (&TelegramChannelMessagesHandler{}).HandleWork(ctx, []byte(
+			`{"username":"masafinance","limit":20,"offsetID":121,"since":"2024-01-01T00:00:00Z","until":"2024-01-31T02:00:00+02:00"}`))
+
+		require.Empty(t, response.Error)
+		assert.Equal(t, `[{"id":120,"text":"gm"}]`, response.Data)
+		require.Len(t, *jobs, 1)
+		assert.Equal(t, telegramScraperJob, (*jobs)[0].Type)
+		assert.Equal(t, types.JobArguments{
+			"type":     "getchannelmessages",
+			"username": "masafinance",
+			"limit":    float64(20),
+			"offsetID": float64(121),
+			"since":    "2024-01-01T00:00:00Z",
+			"until":    "2024-01-31T00:00:00Z",
+		}, (*jobs)[0].Arguments)
+	})
+
+	t.Run("optional arguments are omitted", func(t *testing.T) {
+		jobs := stubTeeWorker(t, `[]`)
+		response := (&TelegramChannelMessagesHandler{}).HandleWork(ctx, []byte(`{"username":"masafinance"}`))
+
+		require.Empty(t, response.Error)
+		require.Len(t, *jobs, 1)
+		assert.Equal(t, types.JobArguments{"type": "getchannelmessages", "username": "masafinance"}, (*jobs)[0].Arguments)
+	})
+
+	t.Run("runs on an executor with Telegram jobs", func(t *testing.T) {
+		executor := tee.NewFixtureExecutorWith(tee.Fixture{
+			Type:      telegramScraperJob,
+			Arguments: map[string]interface{}{"type": "getchannelmessages", "username": "masafinance", "offsetID": float64(121)},
+			Result:    json.RawMessage(`[{"id":120,"text":"gm"}]`),
+		})
+		require.True(t, SupportedBy(executor, data_types.TelegramChannelMessages))
+		response := (&TelegramChannelMessagesHandler{Executor: executor}).HandleWork(ctx, []byte(`{"username":"masafinance","offsetID":121}`))
+
+		require.Empty(t, response.Error)
+		assert.Equal(t, `[{"id":120,"text":"gm"}]`, response.Data)
+		assert.True(t, response.Unsealed)
+	})
+
+	t.Run("the remote TEE worker has no Telegram jobs", func(t *testing.T) {
+		assert.False(t, SupportedBy(nil, data_types.TelegramChannelMessages))
+	})
+
+	t.Run("invalid requests are rejected without a job", func(t *testing.T) {
+		jobs := stubTeeWorker(t, `[]`)
+		handler := &TelegramChannelMessagesHandler{}
+
+		assert.Contains(t, handler.HandleWork(ctx, []byte(`{}`)).Error, "username is required")
+		assert.Contains(t, handler.HandleWork(ctx, []byte(`{"username":"masafinance","since":"2024-02-01T00:00:00Z","until":"2024-01-01T00:00:00Z"}`)).Error, "until must not be before since")
+		assert.Empty(t, *jobs)
+	})
+}
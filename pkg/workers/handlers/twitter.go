@@ -1,110 +1,93 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/masa-finance/masa-oracle/pkg/tee"
-	"github.com/masa-finance/masa-oracle/pkg/utils"
 	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
-	types "github.com/masa-finance/tee-worker/api/types"
 )
 
-type TwitterQueryHandler struct{ MasaDir string }
-type TwitterFollowersHandler struct{ MasaDir string }
-type TwitterProfileHandler struct{ MasaDir string }
+// twitterScraperJob is the TEE worker job type for Twitter.
+const twitterScraperJob = "twitter-scraper"
 
-func (h *TwitterQueryHandler) HandleWork(data []byte) data_types.WorkResponse {
+type TwitterQueryHandler struct {
+	MasaDir  string
+	Executor tee.JobExecutor
+}
+type TwitterFollowersHandler struct {
+	MasaDir  string
+	Executor tee.JobExecutor
+}
+type TwitterProfileHandler struct {
+	MasaDir  string
+	Executor tee.JobExecutor
+}
+
+func (h *TwitterQueryHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
 	logrus.Infof("[+] TwitterQueryHandler input: %s", data)
-	dataMap, err := utils.BytesToMap(data)
-	if err != nil {
+	var request data_types.TwitterQueryRequest
+	if err := data_types.DecodeRequestData(data_types.Twitter, data, &request); err != nil {
 		logrus.Errorf("[+] TwitterQueryHandler error parsing data: %v", err)
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter query data: %v", err)}
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse twitter query data: %v", err)
 	}
-	count := int(dataMap["count"].(float64))
-	query := dataMap["query"].(string)
+	count := request.Count
+	query := request.Query
 
 	logrus.Infof("[+] Scraping tweets for query: %s, count: %d", query, count)
 
-	client := tee.NewClient()
-	res, err := client.SubmitJob(types.Job{
-		Type: "twitter-scraper",
-		Arguments: map[string]interface{}{
-			"type":  "searchbyquery",
-			"query": query,
-			"count": count,
-		},
+	result, err := runJob(ctx, h.Executor, twitterScraperJob, map[string]interface{}{
+		"type":  "searchbyquery",
+		"query": query,
+		"count": count,
 	})
 	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter query data: %v", err)}
-	}
-
-	result, err := res.Get()
-	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter query data: %v", err)}
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to parse twitter query data: %v", err)
 	}
 
 	logrus.Infof("[+] TwitterQueryHandler Work response for %s: %v", data_types.Twitter, result)
-	return data_types.WorkResponse{Data: result}
+	return jobResponse(h.Executor, result)
 }
 
-func (h *TwitterFollowersHandler) HandleWork(data []byte) data_types.WorkResponse {
+func (h *TwitterFollowersHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
 	logrus.Infof("[+] TwitterFollowersHandler %s", data)
-	dataMap, err := utils.BytesToMap(data)
-	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter followers data: %v", err)}
+	var request data_types.TwitterFollowersRequest
+	if err := data_types.DecodeRequestData(data_types.TwitterFollowers, data, &request); err != nil {
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse twitter followers data: %v", err)
 	}
-	username := dataMap["username"].(string)
-	count := int(dataMap["count"].(float64))
+	username := request.Username
+	count := request.Count
 
-	client := tee.NewClient()
-	res, err := client.SubmitJob(types.Job{
-		Type: "twitter-scraper",
-		Arguments: map[string]interface{}{
-			"type":  "searchfollowers",
-			"query": username,
-			"count": count,
-		},
+	result, err := runJob(ctx, h.Executor, twitterScraperJob, map[string]interface{}{
+		"type":  "searchfollowers",
+		"query": username,
+		"count": count,
 	})
 	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter followers data: %v", err)}
-	}
-
-	result, err := res.Get()
-	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter query data: %v", err)}
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to parse twitter followers data: %v", err)
 	}
 
 	logrus.Infof("[+] TwitterQueryHandler Work response for %s: %v", data_types.Twitter, result)
-	return data_types.WorkResponse{Data: result}
+	return jobResponse(h.Executor, result)
 }
 
-func (h *TwitterProfileHandler) HandleWork(data []byte) data_types.WorkResponse {
+func (h *TwitterProfileHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
 	logrus.Infof("[+] TwitterProfileHandler %s", data)
-	dataMap, err := utils.BytesToMap(data)
-	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter profile data: %v", err)}
+	var request data_types.TwitterProfileRequest
+	if err := data_types.DecodeRequestData(data_types.TwitterProfile, data, &request); err != nil {
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse twitter profile data: %v", err)
 	}
-	username := dataMap["username"].(string)
+	username := request.Username
 
-	client := tee.NewClient()
-	res, err := client.SubmitJob(types.Job{
-		Type: "twitter-scraper",
-		Arguments: map[string]interface{}{
-			"type":  "searchbyprofile",
-			"query": username,
-		},
+	result, err := runJob(ctx, h.Executor, twitterScraperJob, map[string]interface{}{
+		"type":  "searchbyprofile",
+		"query": username,
 	})
 	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter query data: %v", err)}
-	}
-
-	result, err := res.Get()
-	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter query data: %v", err)}
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to parse twitter query data: %v", err)
 	}
 
 	logrus.Infof("[+] TwitterQueryHandler Work response for %s: %v", data_types.Twitter, result)
-	return data_types.WorkResponse{Data: result}
+	return jobResponse(h.Executor, result)
 }
@@ -1,45 +1,36 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/masa-finance/masa-oracle/pkg/tee"
-	"github.com/masa-finance/masa-oracle/pkg/utils"
 	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
-	types "github.com/masa-finance/tee-worker/api/types"
 )
 
+// webScraperJob is the TEE worker job type for the web scraper.
+const webScraperJob = "web-scraper"
+
 // WebHandler - All the web handlers implement the WorkHandler interface.
-type WebHandler struct{}
+type WebHandler struct{ Executor tee.JobExecutor }
 
-func (h *WebHandler) HandleWork(data []byte) data_types.WorkResponse {
+func (h *WebHandler) HandleWork(ctx context.Context, data []byte) data_types.WorkResponse {
 	logrus.Infof("[+] WebHandler %s", data)
-	client := tee.NewClient()
 
-	dataMap, err := utils.BytesToMap(data)
-	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse web data: %v", err)}
-	}
-	depth := int(dataMap["depth"].(float64))
-
-	res, err := client.SubmitJob(types.Job{
-		Type: "web-scraper",
-		Arguments: map[string]interface{}{
-			"url":   dataMap["url"].(string),
-			"depth": depth,
-		},
-	})
-	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse web query data: %v", err)}
+	var request data_types.WebRequest
+	if err := data_types.DecodeRequestData(data_types.Web, data, &request); err != nil {
+		return data_types.NewErrorResponse(data_types.ErrorInvalidRequest, "unable to parse web data: %v", err)
 	}
 
-	result, err := res.Get()
+	result, err := runJob(ctx, h.Executor, webScraperJob, map[string]interface{}{
+		"url":   request.URL,
+		"depth": request.Depth,
+	})
 	if err != nil {
-		return data_types.WorkResponse{Error: fmt.Sprintf("unable to parse twitter query data: %v", err)}
+		return data_types.NewErrorResponse(teeErrorCode(err), "unable to scrape web data: %v", err)
 	}
 
 	logrus.Infof("[+] WebHandler Work response for %s: %v returned", data_types.Web, result)
-	return data_types.WorkResponse{Data: result}
+	return jobResponse(h.Executor, result)
 }
@@ -0,0 +1,81 @@
+package workers
+
+import (
+	"encoding/json"
+
+	"github.com/dgraph-io/badger"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// jobKeyPrefix prefixes the keys of jobs in the job store.
+const jobKeyPrefix = "job/"
+
+// jobStore persists jobs so that they survive a restart of the node.
+type jobStore interface {
+	Save(job *Job) error
+	Delete(id string) error
+	Load() ([]*Job, error)
+}
+
+// storedJob is the persisted form of a job, which unlike the API form includes its request and response.
+type storedJob struct {
+	Job
+	Request  data_types.WorkRequest   `json:"request"`
+	Response *data_types.WorkResponse `json:"response,omitempty"`
+}
+
+// badgerJobStore is a jobStore backed by a badger database.
+type badgerJobStore struct {
+	db *badger.DB
+}
+
+// newBadgerJobStore returns the job store kept in the given database.
+func newBadgerJobStore(db *badger.DB) *badgerJobStore {
+	return &badgerJobStore{db: db}
+}
+
+func (s *badgerJobStore) Save(job *Job) error {
+	data, err := json.Marshal(storedJob{Job: *job, Request: job.Request, Response: job.Response})
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(jobKeyPrefix+job.ID), data)
+	})
+}
+
+func (s *badgerJobStore) Delete(id string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(jobKeyPrefix + id))
+	})
+}
+
+// Load returns every stored job.
+func (s *badgerJobStore) Load() ([]*Job, error) {
+	var jobs []*Job
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterator := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iterator.Close()
+
+		prefix := []byte(jobKeyPrefix)
+		for iterator.Seek(prefix); iterator.ValidForPrefix(prefix); iterator.Next() {
+			err := iterator.Item().Value(func(value []byte) error {
+				var stored storedJob
+				if err := json.Unmarshal(value, &stored); err != nil {
+					return err
+				}
+				job := stored.Job
+				job.Request = stored.Request
+				job.Response = stored.Response
+				jobs = append(jobs, &job)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return jobs, err
+}
@@ -0,0 +1,406 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/node"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// JobStatus is the lifecycle state of an asynchronous job.
+type JobStatus string
+
+const (
+	JobQueued     JobStatus = "queued"
+	JobDispatched JobStatus = "dispatched"
+	JobRunning    JobStatus = "running"
+	JobSucceeded  JobStatus = "succeeded"
+	JobFailed     JobStatus = "failed"
+	// JobDeadLettered is the state of a job that was delivered to the dispatcher the maximum number of
+	// times without finishing, for example because the node restarted during each attempt.
+	JobDeadLettered JobStatus = "dead-lettered"
+)
+
+// ErrJobQueueFull is returned when a job is submitted while the job queue is at capacity.
+var ErrJobQueueFull = errors.New("job queue is full")
+
+// ErrJobNotDeadLettered is returned when requeueing a job that is not dead-lettered.
+var ErrJobNotDeadLettered = errors.New("job is not dead-lettered")
+
+// Job tracks an asynchronous work request and, once finished, its response.
+type Job struct {
+	ID           string                   `json:"id"`
+	Status       JobStatus                `json:"status"`
+	WorkType     data_types.WorkerType    `json:"workType"`
+	WorkerPeerId string                   `json:"workerPeerId,omitempty"`
+	Error        string                   `json:"error,omitempty"`
+	ErrorCode    data_types.ErrorCode     `json:"errorCode,omitempty"`
+	CreatedAt    time.Time                `json:"createdAt"`
+	UpdatedAt    time.Time                `json:"updatedAt"`
+	CompletedAt  time.Time                `json:"completedAt,omitempty"`
+	Attempts     int                      `json:"attempts"`
+	LeaseExpires time.Time                `json:"leaseExpires,omitempty"` // when a dispatched job becomes visible for redelivery
+	Request      data_types.WorkRequest   `json:"-"`
+	Response     *data_types.WorkResponse `json:"-"`
+}
+
+// IsDone returns true if the job has reached a terminal state.
+func (j *Job) IsDone() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobDeadLettered
+}
+
+// dispatchFunc distributes a work request, calling onRunning when a worker starts processing it.
+type dispatchFunc func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse
+
+// JobManager accepts work requests, queues them and runs them in the background through
+// the WorkHandlerManager, keeping each job's status and response until it expires.
+//
+// Jobs are persisted in the node's job store and delivered at least once: a job taken off the queue is
+// leased for the visibility timeout, and is queued again if it has not finished when the lease expires
+// or when the node restarts. A job that was delivered JobMaxAttempts times without finishing is
+// dead-lettered, and stays so until it is requeued.
+type JobManager struct {
+	mu       sync.RWMutex
+	jobs     map[string]*Job
+	queue    chan string
+	dispatch dispatchFunc
+	config   *WorkerConfig
+	store    jobStore
+}
+
+// NewJobManager creates a JobManager that dispatches jobs through the given WorkHandlerManager and keeps
+// them in its job store. Unfinished jobs found in the store are queued again.
+// If the store cannot be opened the jobs are only kept in memory.
+func NewJobManager(node *node.OracleNode, whm *WorkHandlerManager) *JobManager {
+	var store jobStore
+	db, err := whm.database()
+	if err != nil {
+		logrus.Errorf("[-] Failed to open the job store, jobs will not survive a restart: %v", err)
+	} else {
+		store = newBadgerJobStore(db)
+	}
+	return newJobManager(workerConfig, store, func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+		response := whm.distributeCachedWork(context.Background(), node, workRequest, onRunning)
+		if response.Error == "" {
+			if err := response.UnsealDataIfNeeded(workRequest, node.Host.Peerstore().PrivKey(node.Host.ID())); err != nil {
+				response = data_types.NewErrorResponse(data_types.ErrorInternal, "failed to get response data: %v", err)
+			}
+		}
+		return response
+	})
+}
+
+func newJobManager(config *WorkerConfig, store jobStore, dispatch dispatchFunc) *JobManager {
+	jm := &JobManager{
+		jobs:     make(map[string]*Job),
+		queue:    make(chan string, config.JobQueueSize),
+		dispatch: dispatch,
+		config:   config,
+		store:    store,
+	}
+	jm.restore()
+	for i := 0; i < config.JobConcurrency; i++ {
+		go jm.runDispatcher()
+	}
+	go jm.removeExpiredJobs()
+	go jm.redeliverExpiredLeases()
+	return jm
+}
+
+// restore loads the jobs of the store, queueing again the ones that had not finished.
+func (jm *JobManager) restore() {
+	if jm.store == nil {
+		return
+	}
+	jobs, err := jm.store.Load()
+	if err != nil {
+		logrus.Errorf("[-] Failed to load jobs from the job store: %v", err)
+		return
+	}
+	requeued := 0
+	for _, job := range jobs {
+		jm.jobs[job.ID] = job
+		if !job.IsDone() {
+			job.Status = JobQueued
+			job.LeaseExpires = time.Time{}
+			jm.save(job)
+			jm.enqueue(job.ID)
+			requeued++
+		}
+	}
+	if len(jobs) > 0 {
+		logrus.Infof("[+] Restored %d jobs from the job store, %d of them queued again", len(jobs), requeued)
+	}
+}
+
+// save persists the job. The caller must hold jm.mu, or be the only one with access to the job.
+func (jm *JobManager) save(job *Job) {
+	if jm.store == nil {
+		return
+	}
+	if err := jm.store.Save(job); err != nil {
+		logrus.Errorf("[-] Failed to persist job %s: %v", job.ID, err)
+	}
+}
+
+// enqueue puts a job back on the queue, waiting for room in the background if the queue is full.
+func (jm *JobManager) enqueue(id string) {
+	select {
+	case jm.queue <- id:
+	default:
+		go func() { jm.queue <- id }()
+	}
+}
+
+// Submit queues a work request for asynchronous execution and returns the created job.
+// A new request ID is assigned to the request if it does not already have one.
+func (jm *JobManager) Submit(workRequest data_types.WorkRequest) (Job, error) {
+	if workRequest.RequestId == "" {
+		workRequest.RequestId = uuid.New().String()
+	}
+	now := time.Now()
+	job := &Job{
+		ID:        workRequest.RequestId,
+		Status:    JobQueued,
+		WorkType:  workRequest.WorkType,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Request:   workRequest,
+	}
+
+	jm.mu.Lock()
+	if _, exists := jm.jobs[job.ID]; exists {
+		jm.mu.Unlock()
+		return Job{}, fmt.Errorf("job %s already exists", job.ID)
+	}
+	jm.jobs[job.ID] = job
+	jm.save(job)
+	jm.mu.Unlock()
+
+	select {
+	case jm.queue <- job.ID:
+		logrus.Infof("[+] Queued job %s for %s", job.ID, job.WorkType)
+		return jm.snapshot(job), nil
+	default:
+		jm.remove(job.ID)
+		return Job{}, ErrJobQueueFull
+	}
+}
+
+// remove drops a job from memory and from the store.
+func (jm *JobManager) remove(id string) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	delete(jm.jobs, id)
+	if jm.store != nil {
+		if err := jm.store.Delete(id); err != nil {
+			logrus.Errorf("[-] Failed to delete job %s from the job store: %v", id, err)
+		}
+	}
+}
+
+// DeadLettered returns the jobs that were dead-lettered, oldest first.
+func (jm *JobManager) DeadLettered() []Job {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	var jobs []Job
+	for _, job := range jm.jobs {
+		if job.Status == JobDeadLettered {
+			jobs = append(jobs, *job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	return jobs
+}
+
+// Requeue queues a dead-lettered job again, with its delivery attempts reset.
+func (jm *JobManager) Requeue(id string) (Job, error) {
+	jm.mu.Lock()
+	job, exists := jm.jobs[id]
+	if !exists {
+		jm.mu.Unlock()
+		return Job{}, fmt.Errorf("job %s not found", id)
+	}
+	if job.Status != JobDeadLettered {
+		jm.mu.Unlock()
+		return Job{}, ErrJobNotDeadLettered
+	}
+	job.Status = JobQueued
+	job.Attempts = 0
+	job.Error = ""
+	job.ErrorCode = ""
+	job.CompletedAt = time.Time{}
+	job.UpdatedAt = time.Now()
+	jm.save(job)
+	snapshot := *job
+	jm.mu.Unlock()
+
+	jm.enqueue(id)
+	logrus.Infof("[+] Requeued dead-lettered job %s", id)
+	return snapshot, nil
+}
+
+// Get returns a copy of the job with the given ID.
+func (jm *JobManager) Get(id string) (Job, bool) {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	job, exists := jm.jobs[id]
+	if !exists {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+func (jm *JobManager) snapshot(job *Job) Job {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	return *job
+}
+
+// setStatus updates the status of a job that has not yet finished.
+func (jm *JobManager) setStatus(id string, status JobStatus, workerPeerId string) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	job, exists := jm.jobs[id]
+	if !exists || job.IsDone() {
+		return
+	}
+	job.Status = status
+	if workerPeerId != "" {
+		job.WorkerPeerId = workerPeerId
+	}
+	job.UpdatedAt = time.Now()
+	jm.save(job)
+}
+
+// lease takes a queued job for delivery to the dispatcher, leasing it for the visibility timeout. It returns
+// false if the job is not queued, for instance because it was already finished by an earlier delivery, or
+// if the job was dead-lettered because it used up its delivery attempts.
+func (jm *JobManager) lease(id string) (Job, bool) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	job, exists := jm.jobs[id]
+	if !exists || job.Status != JobQueued {
+		return Job{}, false
+	}
+	now := time.Now()
+	job.UpdatedAt = now
+	if jm.config.JobMaxAttempts > 0 && job.Attempts >= jm.config.JobMaxAttempts {
+		logrus.Errorf("[-] Job %s did not finish in %d attempts, moving it to the dead letters", id, job.Attempts)
+		job.Status = JobDeadLettered
+		job.Error = fmt.Sprintf("job did not finish in %d attempts", job.Attempts)
+		job.ErrorCode = data_types.ErrorInternal
+		job.CompletedAt = now
+		job.LeaseExpires = time.Time{}
+		jm.save(job)
+		return Job{}, false
+	}
+	job.Attempts++
+	job.Status = JobDispatched
+	job.LeaseExpires = now.Add(jm.config.JobVisibilityTimeout)
+	jm.save(job)
+	return *job, true
+}
+
+// redeliverExpiredLeases periodically queues again the jobs whose lease expired before they finished.
+func (jm *JobManager) redeliverExpiredLeases() {
+	interval := jm.config.JobVisibilityTimeout / 2
+	if interval <= 0 || interval > time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		var expired []string
+		now := time.Now()
+		jm.mu.Lock()
+		for id, job := range jm.jobs {
+			if !job.IsDone() && job.Status != JobQueued && now.After(job.LeaseExpires) {
+				logrus.Warnf("[-] Lease of job %s expired during attempt %d, queueing it again", id, job.Attempts)
+				job.Status = JobQueued
+				job.LeaseExpires = time.Time{}
+				job.UpdatedAt = now
+				jm.save(job)
+				expired = append(expired, id)
+			}
+		}
+		jm.mu.Unlock()
+		for _, id := range expired {
+			jm.enqueue(id)
+		}
+	}
+}
+
+// complete records the response of a job and moves it to its terminal state.
+func (jm *JobManager) complete(id string, response data_types.WorkResponse) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	job, exists := jm.jobs[id]
+	if !exists || job.IsDone() {
+		// An earlier delivery of the job finished first
+		return
+	}
+	now := time.Now()
+	job.Response = &response
+	job.Error = response.Error
+	job.ErrorCode = response.Code()
+	if response.WorkerPeerId != "" {
+		job.WorkerPeerId = response.WorkerPeerId
+	}
+	if response.Error != "" {
+		job.Status = JobFailed
+	} else {
+		job.Status = JobSucceeded
+	}
+	job.UpdatedAt = now
+	job.CompletedAt = now
+	job.LeaseExpires = time.Time{}
+	jm.save(job)
+}
+
+// runDispatcher takes jobs off the queue and runs them until the queue is closed.
+func (jm *JobManager) runDispatcher() {
+	for id := range jm.queue {
+		job, ok := jm.lease(id)
+		if !ok {
+			continue
+		}
+		response := jm.dispatch(job.Request, func(peerId string) {
+			jm.setStatus(id, JobRunning, peerId)
+		})
+		jm.complete(id, response)
+		if response.Error != "" {
+			logrus.Errorf("[-] Job %s failed: %s", id, response.Error)
+		} else {
+			logrus.Infof("[+] Job %s succeeded", id)
+		}
+	}
+}
+
+// removeExpiredJobs periodically drops finished jobs that are older than the configured retention.
+// Dead-lettered jobs are kept until they are requeued.
+func (jm *JobManager) removeExpiredJobs() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		var expired []string
+		jm.mu.RLock()
+		for id, job := range jm.jobs {
+			if job.IsDone() && job.Status != JobDeadLettered && time.Since(job.CompletedAt) > jm.config.JobRetention {
+				expired = append(expired, id)
+			}
+		}
+		jm.mu.RUnlock()
+		for _, id := range expired {
+			jm.remove(id)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package workers
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func testJobConfig() *WorkerConfig {
+	config := DefaultConfig
+	config.JobQueueSize = 1
+	config.JobConcurrency = 1
+	return &config
+}
+
+func waitForJob(t *testing.T, jm *JobManager, id string) Job {
+	var job Job
+	require.Eventually(t, func() bool {
+		var exists bool
+		job, exists = jm.Get(id)
+		return exists && job.IsDone()
+	}, time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestJobManager(t *testing.T) {
+	t.Setenv("KEEP_SEALED_DATA", "true")
+
+	t.Run("job succeeds and moves through every status", func(t *testing.T) {
+		release := make(chan struct{})
+		running := make(chan struct{})
+		jm := newJobManager(testJobConfig(), nil, func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+			onRunning("peer-1")
+			close(running)
+			<-release
+			return data_types.WorkResponse{Data: "result", WorkerPeerId: "peer-1"}
+		})
+
+		job, err := jm.Submit(data_types.WorkRequest{WorkType: data_types.Web, Data: []byte(`{"url":"https://masa.ai"}`)})
+		require.NoError(t, err)
+		assert.NotEmpty(t, job.ID)
+
+		<-running
+		current, exists := jm.Get(job.ID)
+		require.True(t, exists)
+		assert.Equal(t, JobRunning, current.Status)
+		assert.Equal(t, "peer-1", current.WorkerPeerId)
+
+		close(release)
+		done := waitForJob(t, jm, job.ID)
+		assert.Equal(t, JobSucceeded, done.Status)
+		require.NotNil(t, done.Response)
+		assert.Equal(t, "result", done.Response.Data)
+		assert.False(t, done.CompletedAt.IsZero())
+	})
+
+	t.Run("job fails when the work fails", func(t *testing.T) {
+		jm := newJobManager(testJobConfig(), nil, func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+			return data_types.WorkResponse{Error: "no eligible workers found"}
+		})
+
+		job, err := jm.Submit(data_types.WorkRequest{WorkType: data_types.Twitter})
+		require.NoError(t, err)
+
+		done := waitForJob(t, jm, job.ID)
+		assert.Equal(t, JobFailed, done.Status)
+		assert.Equal(t, "no eligible workers found", done.Error)
+	})
+
+	t.Run("submit fails when the queue is full", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		started := make(chan struct{}, 1)
+		jm := newJobManager(testJobConfig(), nil, func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+			started <- struct{}{}
+			<-release
+			return data_types.WorkResponse{Data: "ok"}
+		})
+
+		_, err := jm.Submit(data_types.WorkRequest{WorkType: data_types.Web})
+		require.NoError(t, err)
+		<-started
+
+		_, err = jm.Submit(data_types.WorkRequest{WorkType: data_types.Web})
+		require.NoError(t, err)
+
+		_, err = jm.Submit(data_types.WorkRequest{WorkType: data_types.Web})
+		assert.ErrorIs(t, err, ErrJobQueueFull)
+	})
+
+	t.Run("unknown job is not found", func(t *testing.T) {
+		jm := newJobManager(testJobConfig(), nil, nil)
+		_, exists := jm.Get("missing")
+		assert.False(t, exists)
+	})
+}
+
+func TestJobManagerDelivery(t *testing.T) {
+	t.Setenv("KEEP_SEALED_DATA", "true")
+
+	t.Run("unfinished jobs are dispatched again after a restart", func(t *testing.T) {
+		path := t.TempDir()
+		db, err := openDatabase(path)
+		require.NoError(t, err)
+		store := newBadgerJobStore(db)
+
+		running := make(chan struct{})
+		jm := newJobManager(testJobConfig(), store, func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+			onRunning("peer-1")
+			close(running)
+			select {} // the node stops before the work finishes
+		})
+		job, err := jm.Submit(data_types.WorkRequest{WorkType: data_types.Web, Data: []byte(`{"url":"https://masa.ai"}`)})
+		require.NoError(t, err)
+		<-running
+		require.NoError(t, db.Close())
+
+		db, err = openDatabase(path)
+		require.NoError(t, err)
+		defer db.Close()
+		store = newBadgerJobStore(db)
+		var dispatched data_types.WorkRequest
+		jm = newJobManager(testJobConfig(), store, func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+			dispatched = workRequest
+			return data_types.WorkResponse{Data: "result"}
+		})
+		done := waitForJob(t, jm, job.ID)
+		assert.Equal(t, JobSucceeded, done.Status)
+		assert.Equal(t, 2, done.Attempts)
+		assert.Equal(t, job.ID, dispatched.RequestId)
+		assert.JSONEq(t, `{"url":"https://masa.ai"}`, string(dispatched.Data))
+	})
+
+	t.Run("jobs whose lease expires are delivered again", func(t *testing.T) {
+		config := testJobConfig()
+		config.JobVisibilityTimeout = 50 * time.Millisecond
+		release := make(chan struct{})
+		defer close(release)
+		var calls atomic.Int32
+		jm := newJobManager(config, nil, func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+			if calls.Add(1) == 1 {
+				<-release
+			}
+			return data_types.WorkResponse{Data: "result"}
+		})
+		go jm.runDispatcher()
+
+		job, err := jm.Submit(data_types.WorkRequest{WorkType: data_types.Web})
+		require.NoError(t, err)
+		done := waitForJob(t, jm, job.ID)
+		assert.Equal(t, JobSucceeded, done.Status)
+		assert.Equal(t, 2, done.Attempts)
+	})
+
+	t.Run("jobs that never finish are dead-lettered", func(t *testing.T) {
+		db, err := openDatabase(t.TempDir())
+		require.NoError(t, err)
+		defer db.Close()
+		store := newBadgerJobStore(db)
+		require.NoError(t, store.Save(&Job{ID: "stuck", Status: JobRunning, WorkType: data_types.Web, Attempts: 3, Request: data_types.WorkRequest{WorkType: data_types.Web, RequestId: "stuck"}}))
+
+		jm := newJobManager(testJobConfig(), store, func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+			return data_types.WorkResponse{Data: "result"}
+		})
+		done := waitForJob(t, jm, "stuck")
+		assert.Equal(t, JobDeadLettered, done.Status)
+		assert.Equal(t, []string{"stuck"}, []string{jm.DeadLettered()[0].ID})
+
+		_, err = jm.Requeue("stuck")
+		require.NoError(t, err)
+		done = waitForJob(t, jm, "stuck")
+		assert.Equal(t, JobSucceeded, done.Status)
+		assert.Equal(t, 1, done.Attempts)
+		assert.Empty(t, jm.DeadLettered())
+
+		_, err = jm.Requeue("stuck")
+		assert.ErrorIs(t, err, ErrJobNotDeadLettered)
+	})
+}
@@ -0,0 +1,28 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/masa-finance/masa-oracle/pkg/metrics"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// observeRequest records a work request distributed by this node that was started at the given time.
+func observeRequest(workRequest data_types.WorkRequest, response data_types.WorkResponse, started time.Time) {
+	outcome := metrics.Outcome(string(response.Code()))
+	metrics.WorkRequests.WithLabelValues(string(workRequest.WorkType), outcome).Inc()
+	metrics.WorkRequestDuration.WithLabelValues(string(workRequest.WorkType), outcome).Observe(time.Since(started).Seconds())
+}
+
+// observeExecution records an attempt at a distributed work request that was started at the given time,
+// executed by this node or a remote worker as given by execution.
+func observeExecution(workRequest data_types.WorkRequest, execution string, response data_types.WorkResponse, started time.Time) {
+	metrics.WorkExecutions.WithLabelValues(string(workRequest.WorkType), execution, metrics.Outcome(string(response.Code()))).Inc()
+	metrics.WorkExecutionDuration.WithLabelValues(string(workRequest.WorkType), execution).Observe(time.Since(started).Seconds())
+}
+
+// observeHandler records a run of a work handler of this node.
+func observeHandler(workRequest data_types.WorkRequest, response data_types.WorkResponse, duration time.Duration) {
+	metrics.HandlerExecutions.WithLabelValues(string(workRequest.WorkType), metrics.Outcome(string(response.Code()))).Inc()
+	metrics.HandlerDuration.WithLabelValues(string(workRequest.WorkType)).Observe(duration.Seconds())
+}
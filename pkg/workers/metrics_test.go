@@ -0,0 +1,28 @@
+package workers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masa-finance/masa-oracle/pkg/metrics"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func TestHandlerMetrics(t *testing.T) {
+	whm := NewWorkHandlerManager()
+	whm.addWorkHandler(data_types.Web, stubHandler{})
+	whm.addWorkHandler(data_types.Twitter, panickingHandler{})
+	succeeded := metrics.HandlerExecutions.WithLabelValues(string(data_types.Web), metrics.OutcomeSuccess)
+	failed := metrics.HandlerExecutions.WithLabelValues(string(data_types.Twitter), string(data_types.ErrorInternal))
+	succeededBefore, failedBefore := testutil.ToFloat64(succeeded), testutil.ToFloat64(failed)
+
+	whm.ExecuteWork(context.Background(), data_types.WorkRequest{WorkType: data_types.Web})
+	whm.ExecuteWork(context.Background(), data_types.WorkRequest{WorkType: data_types.Web})
+	whm.ExecuteWork(context.Background(), data_types.WorkRequest{WorkType: data_types.Twitter})
+
+	assert.Equal(t, succeededBefore+2, testutil.ToFloat64(succeeded))
+	assert.Equal(t, failedBefore+1, testutil.ToFloat64(failed))
+}
@@ -1,10 +1,32 @@
 package workers
 
+import (
+	"time"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+)
+
 type WorkerOption struct {
-	isTwitterWorker        bool
-	isWebScraperWorker     bool
-	isDiscordScraperWorker bool
-	masaDir                string
+	isTwitterWorker         bool
+	isWebScraperWorker      bool
+	isDiscordScraperWorker  bool
+	isTelegramScraperWorker bool
+	masaDir                 string
+	workerSelection         string
+	workerConcurrency       string
+	jobExecutors            string
+	jobFixturesDir          string
+	attestationMeasurements string
+	requiredAttestation     string
+	attestor                tee.AttestationProvider
+	attestationVerifiers    map[string]tee.ReportVerifier
+	isResultStoreEnabled    bool
+	resultStoreMaxAge       time.Duration
+	resultStoreMaxSize      int64
+	webhookAllowlist        string
+	isExportSpoolEnabled    bool
+	exportSpoolMaxAge       time.Duration
+	exportSpoolMaxSize      int64
 }
 
 type WorkerOptionFunc func(*WorkerOption)
@@ -21,12 +43,118 @@ var EnableDiscordScraperWorker = func(o *WorkerOption) {
 	o.isDiscordScraperWorker = true
 }
 
+var EnableTelegramScraperWorker = func(o *WorkerOption) {
+	o.isTelegramScraperWorker = true
+}
+
+// EnableResultStore keeps the successful results of the node's work requests in the result store.
+var EnableResultStore = func(o *WorkerOption) {
+	o.isResultStoreEnabled = true
+}
+
+// EnableExportSpool keeps the successful results of the node's work requests in the export spool, from
+// which they are exported.
+var EnableExportSpool = func(o *WorkerOption) {
+	o.isExportSpoolEnabled = true
+}
+
 func WithMasaDir(dir string) WorkerOptionFunc {
 	return func(o *WorkerOption) {
 		o.masaDir = dir
 	}
 }
 
+// WithWorkerSelection sets the worker selection strategy per category, in the form
+// "twitter=reliability,web=least-loaded". Categories that are not listed keep their default strategy.
+func WithWorkerSelection(spec string) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.workerSelection = spec
+	}
+}
+
+// WithWorkerConcurrency sets the maximum number of requests executed at once per work type, in the
+// form "twitter=2,web=8". Work types that are not listed use the default limit from the worker config.
+func WithWorkerConcurrency(spec string) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.workerConcurrency = spec
+	}
+}
+
+// WithJobExecutors sets the executor that runs the jobs of each work type, in the form
+// "web=local,twitter=fixture". Work types that are not listed run their jobs on the remote TEE worker.
+func WithJobExecutors(spec string) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.jobExecutors = spec
+	}
+}
+
+// WithJobFixturesDir sets the directory the fixture job executor loads its recorded results from.
+func WithJobFixturesDir(dir string) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.jobFixturesDir = dir
+	}
+}
+
+// WithAttestationMeasurements sets the comma-separated hex measurements of the enclaves whose attested
+// results are trusted.
+func WithAttestationMeasurements(measurements string) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.attestationMeasurements = measurements
+	}
+}
+
+// WithRequiredAttestation sets the comma-separated work types, e.g. "twitter,web", whose results are
+// rejected unless their attestation is verified.
+func WithRequiredAttestation(spec string) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.requiredAttestation = spec
+	}
+}
+
+// WithAttestationProvider sets the provider that attests the sealed results of this worker.
+func WithAttestationProvider(provider tee.AttestationProvider) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.attestor = provider
+	}
+}
+
+// WithAttestationVerifier accepts the attestations of the named provider, checking their reports with
+// the verifier.
+func WithAttestationVerifier(provider string, verifier tee.ReportVerifier) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		if o.attestationVerifiers == nil {
+			o.attestationVerifiers = make(map[string]tee.ReportVerifier)
+		}
+		o.attestationVerifiers[provider] = verifier
+	}
+}
+
+// WithResultRetention sets how long the result store keeps results, and the size in bytes it is pruned
+// down to. Zero values keep the defaults of the worker config.
+func WithResultRetention(maxAge time.Duration, maxSize int64) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.resultStoreMaxAge = maxAge
+		o.resultStoreMaxSize = maxSize
+	}
+}
+
+// WithWebhookAllowlist sets the internal hosts and networks that the webhook sinks of schedules may be sent
+// to, see ParseWebhookAllowlist.
+func WithWebhookAllowlist(spec string) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.webhookAllowlist = spec
+	}
+}
+
+// WithExportSpoolRetention sets how long the export spool keeps results, and the size in bytes it is
+// pruned down to. Zero values keep the defaults of the worker config.
+func WithExportSpoolRetention(maxAge time.Duration, maxSize int64) WorkerOptionFunc {
+	return func(o *WorkerOption) {
+		o.exportSpoolMaxAge = maxAge
+		o.exportSpoolMaxSize = maxSize
+	}
+}
+
 func (a *WorkerOption) Apply(opts ...WorkerOptionFunc) {
 	for _, opt := range opts {
 		opt(a)
@@ -0,0 +1,309 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/node"
+	"github.com/masa-finance/masa-oracle/pkg/metrics"
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// decryptResult unseals a result sealed by the TEE worker so it can be compared with the results of other
+// workers. Results of job executors without a TEE are plain JSON and compared as they are.
+var decryptResult = func(sealed string) (string, error) {
+	return tee.NewClient().Decrypt(sealed)
+}
+
+// quorumSettings returns the number of workers to ask and the number of them that have to agree.
+func quorumSettings(opts *data_types.QuorumOptions) (workers, agree int) {
+	workers = opts.Workers
+	if workers > workerConfig.MaxQuorumWorkers {
+		workers = workerConfig.MaxQuorumWorkers
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	agree = opts.Agree
+	if agree <= 0 {
+		agree = workers/2 + 1
+	}
+	if agree > workers {
+		agree = workers
+	}
+	return workers, agree
+}
+
+// quorumVote is a successful answer of a worker taking part in a quorum.
+type quorumVote struct {
+	peerId   string
+	hash     string
+	response data_types.WorkResponse
+}
+
+// quorumTally counts the answers of the workers taking part in a quorum.
+type quorumTally struct {
+	workers int
+	agree   int
+	votes   []quorumVote
+	counts  map[string]int
+	failed  []string
+}
+
+func newQuorumTally(workers, agree int) *quorumTally {
+	return &quorumTally{workers: workers, agree: agree, counts: make(map[string]int)}
+}
+
+// add records the answer of a worker. Failed answers do not count as votes.
+func (t *quorumTally) add(peerId string, response data_types.WorkResponse) {
+	if response.Error != "" {
+		t.failed = append(t.failed, peerId)
+		return
+	}
+	hash, err := hashResult(response)
+	if err != nil {
+		logrus.Warnf("Failed to normalise the result of worker %s: %v", peerId, err)
+		t.failed = append(t.failed, peerId)
+		return
+	}
+	t.votes = append(t.votes, quorumVote{peerId: peerId, hash: hash, response: response})
+	t.counts[hash]++
+}
+
+// winner returns the hash that enough workers agreed on, if any.
+func (t *quorumTally) winner() (string, bool) {
+	for hash, count := range t.counts {
+		if count >= t.agree {
+			return hash, true
+		}
+	}
+	return "", false
+}
+
+// needed returns the number of additional votes still useful to reach the quorum.
+func (t *quorumTally) needed() int {
+	return t.workers - len(t.votes)
+}
+
+// possible reports whether the quorum can still be reached when up to pending more workers answer.
+func (t *quorumTally) possible(pending int) bool {
+	best := 0
+	for _, count := range t.counts {
+		if count > best {
+			best = count
+		}
+	}
+	remaining := t.needed()
+	if pending < remaining {
+		remaining = pending
+	}
+	return best+remaining >= t.agree
+}
+
+// plurality returns the hash most workers returned, the one returned first if several were returned by as
+// many workers.
+func (t *quorumTally) plurality() string {
+	best := ""
+	for _, vote := range t.votes {
+		if best == "" || t.counts[vote.hash] > t.counts[best] {
+			best = vote.hash
+		}
+	}
+	return best
+}
+
+// result builds the response for the tally, using the response of the first agreeing worker on success.
+// If the quorum was not reached, the disagreeing peers are those that did not return the most common result.
+func (t *quorumTally) result() (response data_types.WorkResponse, ok bool) {
+	hash, ok := t.winner()
+	if !ok {
+		hash = t.plurality()
+	}
+	summary := &data_types.QuorumResult{
+		Workers:     t.workers,
+		Agree:       t.agree,
+		FailedPeers: t.failed,
+	}
+	for _, vote := range t.votes {
+		if vote.hash != hash {
+			summary.DisagreeingPeers = append(summary.DisagreeingPeers, vote.peerId)
+			continue
+		}
+		if summary.Agreed == 0 {
+			response = vote.response
+		}
+		summary.Agreed++
+		if ok {
+			summary.AgreeingPeers = append(summary.AgreeingPeers, vote.peerId)
+		}
+	}
+	if ok {
+		summary.ResultHash = hash
+	} else {
+		response = data_types.NewErrorResponse(data_types.ErrorInternal, "quorum not reached: %d of %d workers agreed, %d required", summary.Agreed, t.workers, t.agree)
+	}
+	response.Quorum = summary
+	return response, ok
+}
+
+// distributeQuorum sends the work request to several distinct workers at once, replacing workers that
+// fail with the next candidate, until the configured number of them agree on the result or agreement
+// is no longer possible. The local worker, if eligible, is the last candidate. Whether each worker
+// agreed with the quorum is recorded in the node tracker so that divergent workers are deprioritized.
+func (whm *WorkHandlerManager) distributeQuorum(ctx context.Context, node *node.OracleNode, remoteWorkers []data_types.Worker, localWorker *data_types.Worker, workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+	workers, agree := quorumSettings(workRequest.Quorum)
+	tally := newQuorumTally(workers, agree)
+
+	candidates := append([]data_types.Worker(nil), remoteWorkers...)
+	if localWorker != nil {
+		candidates = append(candidates, *localWorker)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type answer struct {
+		peerId   string
+		response data_types.WorkResponse
+	}
+	answers := make(chan answer, len(candidates))
+	next, inFlight := 0, 0
+	launch := func() {
+		worker := candidates[next]
+		next++
+		inFlight++
+		go func() {
+			var response data_types.WorkResponse
+			if worker.IsLocal {
+				peerId := worker.AddrInfo.ID.String()
+				if onRunning != nil {
+					onRunning(peerId)
+				}
+				started := time.Now()
+				response = whm.verifyAttestation(workRequest, whm.executeWorkLimited(ctx, workRequest))
+				observeExecution(workRequest, metrics.ExecutionLocal, response, started)
+				whm.eventTracker.TrackWorkCompletion(workRequest.WorkType, response.Error == "", peerId)
+				answers <- answer{peerId: peerId, response: response}
+				return
+			}
+			response = whm.tryRemoteWorker(ctx, node, worker, workRequest, onRunning)
+			answers <- answer{peerId: worker.NodeData.PeerId.String(), response: response}
+		}()
+	}
+	fill := func() {
+		for next < len(candidates) && inFlight < tally.needed() {
+			launch()
+		}
+	}
+
+	logrus.Infof("Sending %s request to %d workers, %d of which have to agree", workRequest.WorkType, workers, agree)
+	fill()
+	for inFlight > 0 {
+		a := <-answers
+		inFlight--
+		if ctx.Err() != nil {
+			logrus.Infof("Quorum for %s request cancelled by the requester", workRequest.WorkType)
+			return cancelledResponse()
+		}
+		tally.add(a.peerId, a.response)
+		if _, ok := tally.winner(); ok {
+			break
+		}
+		if !tally.possible(inFlight + len(candidates) - next) {
+			break
+		}
+		fill()
+	}
+
+	response, ok := tally.result()
+	if ok {
+		logrus.Infof("Quorum reached for %s request: %d of %d workers agreed", workRequest.WorkType, response.Quorum.Agreed, workers)
+		whm.recordQuorum(node, response.Quorum)
+	} else {
+		logrus.Warnf("Quorum not reached for %s request: %s", workRequest.WorkType, response.Error)
+	}
+	return response
+}
+
+// recordQuorum updates the quorum statistics of the workers that took part in a successful quorum.
+func (whm *WorkHandlerManager) recordQuorum(node *node.OracleNode, result *data_types.QuorumResult) {
+	update := func(peerId string, agreed bool) {
+		if err := node.NodeTracker.UpdateNodeDataQuorum(peerId, agreed); err != nil {
+			logrus.Warnf("Failed to update quorum data for peer %s: %v", peerId, err)
+		}
+	}
+	for _, peerId := range result.AgreeingPeers {
+		update(peerId, true)
+	}
+	for _, peerId := range result.DisagreeingPeers {
+		logrus.Warnf("Worker %s returned data that disagrees with the quorum", peerId)
+		update(peerId, false)
+	}
+}
+
+// hashResult normalises the result of a response and returns its hash. Results sealed by the TEE worker
+// are unsealed first, JSON results are re-encoded with sorted keys and without the configured ignored fields.
+func hashResult(response data_types.WorkResponse) (string, error) {
+	normalized, err := normalizeResult(response.Data, !response.Unsealed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(normalized)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func normalizeResult(data interface{}, sealed bool) ([]byte, error) {
+	var raw []byte
+	switch v := data.(type) {
+	case string:
+		raw = []byte(v)
+		if !sealed {
+			break
+		}
+		// Results that cannot be unsealed are compared as they are
+		if unsealed, err := decryptResult(v); err == nil {
+			raw = []byte(unsealed)
+		}
+	case []byte:
+		raw = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw = encoded
+	}
+
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return bytes.TrimSpace(raw), nil
+	}
+	return json.Marshal(removeIgnoredFields(decoded))
+}
+
+// removeIgnoredFields drops the fields listed in QuorumIgnoredFields, at any depth, from a decoded JSON value.
+func removeIgnoredFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, field := range workerConfig.QuorumIgnoredFields {
+			delete(v, field)
+		}
+		for key, item := range v {
+			v[key] = removeIgnoredFields(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = removeIgnoredFields(item)
+		}
+	}
+	return value
+}
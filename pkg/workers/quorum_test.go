@@ -0,0 +1,109 @@
+package workers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func TestQuorumSettings(t *testing.T) {
+	workers, agree := quorumSettings(&data_types.QuorumOptions{Workers: 3})
+	assert.Equal(t, 3, workers)
+	assert.Equal(t, 2, agree)
+
+	workers, agree = quorumSettings(&data_types.QuorumOptions{Workers: 100, Agree: 100})
+	assert.Equal(t, workerConfig.MaxQuorumWorkers, workers)
+	assert.Equal(t, workerConfig.MaxQuorumWorkers, agree)
+}
+
+func TestQuorumTally(t *testing.T) {
+	decrypt := decryptResult
+	decryptResult = func(sealed string) (string, error) { return "", errors.New("not sealed") }
+	defer func() { decryptResult = decrypt }()
+
+	t.Run("agreement ignores key order and reports disagreeing peers", func(t *testing.T) {
+		tally := newQuorumTally(3, 2)
+		tally.add("peer-1", data_types.WorkResponse{Data: map[string]interface{}{"title": "Masa", "links": []interface{}{"a", "b"}}, WorkerPeerId: "peer-1"})
+		tally.add("peer-2", data_types.WorkResponse{Data: map[string]interface{}{"title": "Other"}})
+		_, ok := tally.winner()
+		assert.False(t, ok)
+		assert.True(t, tally.possible(1))
+
+		tally.add("peer-3", data_types.WorkResponse{Data: `{"links":["a","b"], "title":"Masa"}`})
+		response, ok := tally.result()
+		require.True(t, ok)
+		assert.Empty(t, response.Error)
+		assert.Equal(t, "peer-1", response.WorkerPeerId)
+		assert.Equal(t, 2, response.Quorum.Agreed)
+		assert.Equal(t, []string{"peer-1", "peer-3"}, response.Quorum.AgreeingPeers)
+		assert.Equal(t, []string{"peer-2"}, response.Quorum.DisagreeingPeers)
+		assert.NotEmpty(t, response.Quorum.ResultHash)
+	})
+
+	t.Run("failed workers do not vote", func(t *testing.T) {
+		tally := newQuorumTally(2, 2)
+		tally.add("peer-1", data_types.WorkResponse{Data: "same"})
+		tally.add("peer-2", data_types.WorkResponse{Error: "timeout"})
+		assert.Equal(t, 1, tally.needed())
+		assert.False(t, tally.possible(0))
+
+		response, ok := tally.result()
+		assert.False(t, ok)
+		assert.Equal(t, "quorum not reached: 1 of 2 workers agreed, 2 required", response.Error)
+		assert.Equal(t, []string{"peer-2"}, response.Quorum.FailedPeers)
+		assert.Empty(t, response.Quorum.DisagreeingPeers)
+	})
+
+	t.Run("sealed results are compared unsealed", func(t *testing.T) {
+		decryptResult = func(sealed string) (string, error) { return `{"text":"gm"}`, nil }
+		tally := newQuorumTally(2, 2)
+		tally.add("peer-1", data_types.WorkResponse{Data: "ciphertext-1"})
+		tally.add("peer-2", data_types.WorkResponse{Data: "ciphertext-2"})
+		_, ok := tally.winner()
+		assert.True(t, ok)
+	})
+
+	t.Run("results of executors without a TEE are compared without unsealing", func(t *testing.T) {
+		decryptResult = func(sealed string) (string, error) {
+			t.Errorf("unsealed result %q sent to the TEE worker", sealed)
+			return "", errors.New("not sealed")
+		}
+		tally := newQuorumTally(2, 2)
+		tally.add("peer-1", data_types.WorkResponse{Data: `{"text":"gm","likes":1}`, Unsealed: true})
+		tally.add("peer-2", data_types.WorkResponse{Data: `{"likes":1, "text":"gm"}`, Unsealed: true})
+		_, ok := tally.winner()
+		assert.True(t, ok)
+	})
+
+	t.Run("failed quorums report the workers disagreeing with the most common result", func(t *testing.T) {
+		decryptResult = func(sealed string) (string, error) { return "", errors.New("not sealed") }
+		tally := newQuorumTally(4, 3)
+		tally.add("peer-1", data_types.WorkResponse{Data: "a"})
+		tally.add("peer-2", data_types.WorkResponse{Data: "b"})
+		tally.add("peer-3", data_types.WorkResponse{Data: "b"})
+		tally.add("peer-4", data_types.WorkResponse{Data: "c"})
+
+		response, ok := tally.result()
+		assert.False(t, ok)
+		assert.Equal(t, "quorum not reached: 2 of 4 workers agreed, 3 required", response.Error)
+		assert.Equal(t, 2, response.Quorum.Agreed)
+		assert.Empty(t, response.Quorum.AgreeingPeers)
+		assert.Equal(t, []string{"peer-1", "peer-4"}, response.Quorum.DisagreeingPeers)
+	})
+
+	t.Run("ignored fields do not affect agreement", func(t *testing.T) {
+		ignored := workerConfig.QuorumIgnoredFields
+		workerConfig.QuorumIgnoredFields = []string{"scrapedAt"}
+		defer func() { workerConfig.QuorumIgnoredFields = ignored }()
+
+		first, err := hashResult(data_types.WorkResponse{Data: map[string]interface{}{"items": []interface{}{map[string]interface{}{"text": "gm", "scrapedAt": 1}}}})
+		require.NoError(t, err)
+		second, err := hashResult(data_types.WorkResponse{Data: map[string]interface{}{"items": []interface{}{map[string]interface{}{"text": "gm", "scrapedAt": 2}}}})
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+}
@@ -0,0 +1,230 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// Cache statuses reported in WorkResponse.CacheStatus.
+const (
+	CacheHit       = "hit"
+	CacheMiss      = "miss"
+	CacheCoalesced = "coalesced"
+	CacheBypass    = "bypass"
+)
+
+// CacheStats holds the counters of a ResultCache.
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Coalesced uint64 `json:"coalesced"`
+	Bypassed  uint64 `json:"bypassed"`
+	Entries   int    `json:"entries"`
+}
+
+// ResultCache caches successful work responses by the hash of the work type and request data, and
+// coalesces identical requests that are in flight at the same time into a single dispatch.
+// Each work type is cached for its own TTL; work types without a TTL are never cached.
+type ResultCache struct {
+	mu         sync.Mutex
+	ttls       map[data_types.WorkerType]time.Duration
+	maxEntries int
+	entries    map[string]cacheEntry
+	inFlight   map[string]*inFlightCall
+	now        func() time.Time
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	coalesced atomic.Uint64
+	bypassed  atomic.Uint64
+}
+
+type cacheEntry struct {
+	response  data_types.WorkResponse
+	expiresAt time.Time
+}
+
+// inFlightCall is a fetch shared by identical requests. It is cancelled once every request waiting for it has gone.
+type inFlightCall struct {
+	done     chan struct{}
+	response data_types.WorkResponse
+	waiters  int
+	cancel   context.CancelFunc
+}
+
+// NewResultCache creates a ResultCache with the given TTL per work type, holding at most maxEntries responses.
+func NewResultCache(ttls map[data_types.WorkerType]time.Duration, maxEntries int) *ResultCache {
+	return &ResultCache{
+		ttls:       ttls,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cacheEntry),
+		inFlight:   make(map[string]*inFlightCall),
+		now:        time.Now,
+	}
+}
+
+// Do returns the cached response for the work request if there is one, waits for an identical request
+// that is already in flight, or calls fetch and caches its response if it succeeded.
+// The returned response reports which of these happened in its CacheStatus field. If the request
+// has NoCache set, fetch is always called and its response replaces the cached one.
+//
+// A request whose ctx is done stops waiting and gets a cancelled response. A fetch shared with identical
+// requests keeps running until all of them stopped waiting, then the context passed to fetch is cancelled.
+func (rc *ResultCache) Do(ctx context.Context, workRequest data_types.WorkRequest, fetch func(ctx context.Context) data_types.WorkResponse) data_types.WorkResponse {
+	ttl := rc.ttls[workRequest.WorkType]
+	if ttl <= 0 {
+		return fetch(ctx)
+	}
+	key := cacheKey(workRequest)
+
+	if workRequest.NoCache {
+		rc.bypassed.Add(1)
+		response := fetch(ctx)
+		rc.store(key, response, ttl)
+		response.CacheStatus = CacheBypass
+		return response
+	}
+
+	rc.mu.Lock()
+	if entry, ok := rc.entries[key]; ok {
+		if rc.now().Before(entry.expiresAt) {
+			rc.mu.Unlock()
+			rc.hits.Add(1)
+			return withCacheStatus(entry.response, workRequest, CacheHit)
+		}
+		delete(rc.entries, key)
+	}
+	if call, ok := rc.inFlight[key]; ok {
+		call.waiters++
+		rc.mu.Unlock()
+		rc.coalesced.Add(1)
+		return rc.wait(ctx, key, call, workRequest, CacheCoalesced)
+	}
+	fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &inFlightCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+	rc.inFlight[key] = call
+	rc.mu.Unlock()
+	rc.misses.Add(1)
+
+	go func() {
+		defer cancel()
+		call.response = fetch(fetchCtx)
+		rc.store(key, call.response, ttl)
+		rc.mu.Lock()
+		rc.forget(key, call)
+		rc.mu.Unlock()
+		close(call.done)
+	}()
+	return rc.wait(ctx, key, call, workRequest, CacheMiss)
+}
+
+// wait waits for a shared fetch to complete, or for ctx to be done. The last waiter to give up cancels the fetch.
+func (rc *ResultCache) wait(ctx context.Context, key string, call *inFlightCall, workRequest data_types.WorkRequest, status string) data_types.WorkResponse {
+	select {
+	case <-call.done:
+		return withCacheStatus(call.response, workRequest, status)
+	case <-ctx.Done():
+		rc.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Later identical requests start a new fetch instead of joining the cancelled one
+			rc.forget(key, call)
+			call.cancel()
+		}
+		rc.mu.Unlock()
+		response := cancelledResponse()
+		response.CacheStatus = status
+		return response
+	}
+}
+
+// forget removes the call from the in-flight calls, unless another call already replaced it. rc.mu must be held.
+func (rc *ResultCache) forget(key string, call *inFlightCall) {
+	if rc.inFlight[key] == call {
+		delete(rc.inFlight, key)
+	}
+}
+
+// Stats returns the current cache counters.
+func (rc *ResultCache) Stats() CacheStats {
+	rc.mu.Lock()
+	entries := len(rc.entries)
+	rc.mu.Unlock()
+	return CacheStats{
+		Hits:      rc.hits.Load(),
+		Misses:    rc.misses.Load(),
+		Coalesced: rc.coalesced.Load(),
+		Bypassed:  rc.bypassed.Load(),
+		Entries:   entries,
+	}
+}
+
+// store caches a successful response, evicting expired entries, or the entry closest to expiry, if the cache is full.
+func (rc *ResultCache) store(key string, response data_types.WorkResponse, ttl time.Duration) {
+	if response.Error != "" || response.Data == nil || response.Data == "" || rc.maxEntries <= 0 {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	now := rc.now()
+	if _, exists := rc.entries[key]; !exists && len(rc.entries) >= rc.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range rc.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(rc.entries, k)
+				continue
+			}
+			if oldestKey == "" || entry.expiresAt.Before(oldest) {
+				oldestKey, oldest = k, entry.expiresAt
+			}
+		}
+		if len(rc.entries) >= rc.maxEntries {
+			delete(rc.entries, oldestKey)
+		}
+	}
+	rc.entries[key] = cacheEntry{response: response, expiresAt: now.Add(ttl)}
+}
+
+// withCacheStatus returns a copy of a shared response for the given request.
+func withCacheStatus(response data_types.WorkResponse, workRequest data_types.WorkRequest, status string) data_types.WorkResponse {
+	if response.WorkRequest != nil {
+		response.WorkRequest = &workRequest
+	}
+	response.CacheStatus = status
+	return response
+}
+
+// cacheKey hashes the work type and the request data. JSON data is re-encoded first so that
+// requests differing only in whitespace or key order share a key.
+func cacheKey(workRequest data_types.WorkRequest) string {
+	data := workRequest.Data
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err == nil {
+		if canonical, err := json.Marshal(decoded); err == nil {
+			data = canonical
+		}
+	}
+
+	h := sha256.New()
+	h.Write([]byte(workRequest.WorkType))
+	h.Write([]byte{0})
+	h.Write(data)
+	if q := workRequest.Quorum; q != nil {
+		// Quorum results are only shared between requests asking for the same quorum
+		fmt.Fprintf(h, "\x00quorum:%d/%d", q.Workers, q.Agree)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
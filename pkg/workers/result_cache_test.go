@@ -0,0 +1,155 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func TestResultCache(t *testing.T) {
+	ttls := map[data_types.WorkerType]time.Duration{data_types.Twitter: time.Minute}
+	search := data_types.WorkRequest{WorkType: data_types.Twitter, Data: []byte(`{"query":"$MASA","count":10}`)}
+
+	t.Run("identical requests are served from the cache", func(t *testing.T) {
+		rc := NewResultCache(ttls, 10)
+		var calls atomic.Int32
+		fetch := func(context.Context) data_types.WorkResponse {
+			calls.Add(1)
+			return data_types.WorkResponse{Data: "tweets"}
+		}
+
+		first := rc.Do(context.Background(), search, fetch)
+		assert.Equal(t, CacheMiss, first.CacheStatus)
+
+		// Key order and whitespace do not matter
+		reordered := data_types.WorkRequest{WorkType: data_types.Twitter, Data: []byte(`{ "count": 10, "query": "$MASA" }`)}
+		second := rc.Do(context.Background(), reordered, fetch)
+		assert.Equal(t, CacheHit, second.CacheStatus)
+		assert.Equal(t, "tweets", second.Data)
+		assert.Equal(t, int32(1), calls.Load())
+
+		stats := rc.Stats()
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, 1, stats.Entries)
+	})
+
+	t.Run("entries expire after their TTL", func(t *testing.T) {
+		rc := NewResultCache(ttls, 10)
+		now := time.Now()
+		rc.now = func() time.Time { return now }
+		fetch := func(context.Context) data_types.WorkResponse { return data_types.WorkResponse{Data: "tweets"} }
+
+		rc.Do(context.Background(), search, fetch)
+		now = now.Add(2 * time.Minute)
+		assert.Equal(t, CacheMiss, rc.Do(context.Background(), search, fetch).CacheStatus)
+	})
+
+	t.Run("errors and uncached work types are not stored", func(t *testing.T) {
+		rc := NewResultCache(ttls, 10)
+		failed := rc.Do(context.Background(), search, func(context.Context) data_types.WorkResponse { return data_types.WorkResponse{Error: "rate limited"} })
+		assert.Equal(t, CacheMiss, failed.CacheStatus)
+		assert.Equal(t, CacheMiss, rc.Do(context.Background(), search, func(context.Context) data_types.WorkResponse { return data_types.WorkResponse{Data: "tweets"} }).CacheStatus)
+
+		web := data_types.WorkRequest{WorkType: data_types.Web, Data: []byte(`{"url":"https://masa.ai"}`)}
+		response := rc.Do(context.Background(), web, func(context.Context) data_types.WorkResponse { return data_types.WorkResponse{Data: "page"} })
+		assert.Empty(t, response.CacheStatus)
+		assert.Equal(t, 1, rc.Stats().Entries)
+	})
+
+	t.Run("bypass fetches and refreshes the entry", func(t *testing.T) {
+		rc := NewResultCache(ttls, 10)
+		rc.Do(context.Background(), search, func(context.Context) data_types.WorkResponse { return data_types.WorkResponse{Data: "old"} })
+
+		bypass := search
+		bypass.NoCache = true
+		response := rc.Do(context.Background(), bypass, func(context.Context) data_types.WorkResponse { return data_types.WorkResponse{Data: "new"} })
+		assert.Equal(t, CacheBypass, response.CacheStatus)
+		assert.Equal(t, "new", rc.Do(context.Background(), search, nil).Data)
+		assert.Equal(t, uint64(1), rc.Stats().Bypassed)
+	})
+
+	t.Run("concurrent duplicates share a single dispatch", func(t *testing.T) {
+		rc := NewResultCache(ttls, 10)
+		release := make(chan struct{})
+		var calls atomic.Int32
+		fetch := func(context.Context) data_types.WorkResponse {
+			calls.Add(1)
+			<-release
+			return data_types.WorkResponse{Data: "tweets"}
+		}
+
+		var wg sync.WaitGroup
+		statuses := make(chan string, 5)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				statuses <- rc.Do(context.Background(), search, fetch).CacheStatus
+			}()
+		}
+		require.Eventually(t, func() bool { return rc.Stats().Coalesced == 4 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(statuses)
+
+		counts := map[string]int{}
+		for status := range statuses {
+			counts[status]++
+		}
+		assert.Equal(t, map[string]int{CacheMiss: 1, CacheCoalesced: 4}, counts)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("a shared dispatch is cancelled once every waiter is gone", func(t *testing.T) {
+		rc := NewResultCache(ttls, 10)
+		started, stopped := make(chan struct{}), make(chan struct{})
+		fetch := func(ctx context.Context) data_types.WorkResponse {
+			close(started)
+			<-ctx.Done()
+			close(stopped)
+			return data_types.WorkResponse{Error: errWorkCancelled.Error()}
+		}
+
+		first, cancelFirst := context.WithCancel(context.Background())
+		second, cancelSecond := context.WithCancel(context.Background())
+		responses := make(chan data_types.WorkResponse, 2)
+		go func() { responses <- rc.Do(first, search, fetch) }()
+		<-started
+		go func() { responses <- rc.Do(second, search, fetch) }()
+		require.Eventually(t, func() bool { return rc.Stats().Coalesced == 1 }, time.Second, time.Millisecond)
+
+		// The remaining waiter keeps the dispatch running
+		cancelFirst()
+		assert.Equal(t, errWorkCancelled.Error(), (<-responses).Error)
+		select {
+		case <-stopped:
+			t.Fatal("dispatch cancelled while a request is still waiting for it")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		cancelSecond()
+		assert.Equal(t, errWorkCancelled.Error(), (<-responses).Error)
+		<-stopped
+		assert.Equal(t, 0, rc.Stats().Entries)
+	})
+
+	t.Run("the entry closest to expiry is evicted when full", func(t *testing.T) {
+		rc := NewResultCache(ttls, 1)
+		fetch := func(context.Context) data_types.WorkResponse { return data_types.WorkResponse{Data: "tweets"} }
+		other := data_types.WorkRequest{WorkType: data_types.Twitter, Data: []byte(`{"query":"other"}`)}
+
+		rc.Do(context.Background(), search, fetch)
+		rc.Do(context.Background(), other, fetch)
+		assert.Equal(t, 1, rc.Stats().Entries)
+		assert.Equal(t, CacheHit, rc.Do(context.Background(), other, fetch).CacheStatus)
+		assert.Equal(t, CacheMiss, rc.Do(context.Background(), search, fetch).CacheStatus)
+	})
+}
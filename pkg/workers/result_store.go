@@ -0,0 +1,355 @@
+package workers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+const (
+	// storedResultKeyPrefix prefixes the keys of stored results. The key of a result is the prefix, the
+	// zero-padded time it was stored at and its request ID, so results sort by time.
+	storedResultKeyPrefix = "stored/"
+	// storedResultIDPrefix prefixes the index from request IDs to the keys of their results.
+	storedResultIDPrefix = "stored-id/"
+	// spooledResultKeyPrefix and spooledResultIDPrefix are the prefixes of the export spool.
+	spooledResultKeyPrefix = "spooled/"
+	spooledResultIDPrefix  = "spooled-id/"
+)
+
+// ErrResultNotFound is returned for a stored result that does not exist.
+var ErrResultNotFound = errors.New("result not found")
+
+// StoredResult is a successful result kept in the result store, with the request it answered.
+type StoredResult struct {
+	ID           string                `json:"id"` // the request ID
+	WorkType     data_types.WorkerType `json:"workType"`
+	Request      json.RawMessage       `json:"request"`             // the request data
+	Requester    string                `json:"requester,omitempty"` // the client that sent the request, see WorkRequest.Requester
+	WorkerPeerId string                `json:"workerPeerId,omitempty"`
+	StoredAt     time.Time             `json:"storedAt"`
+	ContentHash  string                `json:"contentHash"` // hex SHA-256 of the result as returned by the worker
+	Data         interface{}           `json:"data"`        // the result as returned by the worker, sealed unless Unsealed
+	Unsealed     bool                  `json:"unsealed,omitempty"`
+	Verification tee.AttestationStatus `json:"verification,omitempty"`
+}
+
+// Response returns the stored result as a work response.
+func (r StoredResult) Response() data_types.WorkResponse {
+	return data_types.WorkResponse{
+		Data:         r.Data,
+		WorkerPeerId: r.WorkerPeerId,
+		Unsealed:     r.Unsealed,
+		Verification: r.Verification,
+	}
+}
+
+// UnsealableBy reports whether the sealed data of the result may be unsealed for the client, the identity of
+// an API client authenticated with a token: only for the client that requested it, like the sealed results
+// of /unseal, or for any authenticated client if a schedule requested it.
+func (r StoredResult) UnsealableBy(client string) bool {
+	return client != "" && (client == r.Requester || strings.HasPrefix(r.Requester, ScheduleRequesterPrefix))
+}
+
+// ResultQuery filters stored results. Zero fields match any result.
+type ResultQuery struct {
+	WorkType data_types.WorkerType
+	Worker   string    // peer ID of the worker that produced the result
+	Query    string    // case-insensitive text the request data has to contain
+	From     time.Time // stored at or after
+	To       time.Time // stored before
+	Limit    int       // the maximum number of results, all if zero
+}
+
+func (q ResultQuery) matches(result StoredResult) bool {
+	return (q.WorkType == "" || result.WorkType == q.WorkType) &&
+		(q.Worker == "" || result.WorkerPeerId == q.Worker) &&
+		(q.Query == "" || strings.Contains(strings.ToLower(string(result.Request)), strings.ToLower(q.Query)))
+}
+
+// ResultStore keeps the successful results of the work requests of the node. Results are dropped once they
+// are older than the maximum age, and the oldest results are dropped while the store is larger than the
+// maximum size. A node has up to two: the result store, whose results can be queried later without scraping
+// again, and the export spool, whose results are exported to data warehouses.
+type ResultStore struct {
+	name      string
+	db        *badger.DB
+	keyPrefix string
+	idPrefix  string
+	maxAge    time.Duration
+	maxSize   int64
+	closed    chan struct{}
+}
+
+// NewResultStore returns the result store kept in the given database. A zero maxAge or maxSize does not
+// limit the store.
+func NewResultStore(db *badger.DB, maxAge time.Duration, maxSize int64) *ResultStore {
+	return newResultStore("result store", db, storedResultKeyPrefix, storedResultIDPrefix, maxAge, maxSize)
+}
+
+// NewExportSpool returns the export spool kept in the given database, which is independent of the result
+// store. A zero maxAge or maxSize does not limit the spool.
+func NewExportSpool(db *badger.DB, maxAge time.Duration, maxSize int64) *ResultStore {
+	return newResultStore("export spool", db, spooledResultKeyPrefix, spooledResultIDPrefix, maxAge, maxSize)
+}
+
+func newResultStore(name string, db *badger.DB, keyPrefix, idPrefix string, maxAge time.Duration, maxSize int64) *ResultStore {
+	return &ResultStore{name: name, db: db, keyPrefix: keyPrefix, idPrefix: idPrefix, maxAge: maxAge, maxSize: maxSize, closed: make(chan struct{})}
+}
+
+// Save stores the successful response to the request.
+func (s *ResultStore) Save(workRequest data_types.WorkRequest, response data_types.WorkResponse, now time.Time) error {
+	hash, err := tee.ResultHash(response.Data)
+	if err != nil {
+		return err
+	}
+	request := json.RawMessage(workRequest.Data)
+	if !json.Valid(request) {
+		request, _ = json.Marshal(string(workRequest.Data))
+	}
+	result := StoredResult{
+		ID:           workRequest.RequestId,
+		WorkType:     workRequest.WorkType,
+		Request:      request,
+		Requester:    workRequest.Requester,
+		WorkerPeerId: response.WorkerPeerId,
+		StoredAt:     now.UTC(),
+		ContentHash:  hex.EncodeToString(hash),
+		Data:         response.Data,
+		Unsealed:     response.Unsealed,
+		Verification: response.Verification,
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	key := []byte(fmt.Sprintf("%s%020d/%s", s.keyPrefix, result.StoredAt.UnixNano(), result.ID))
+	return s.db.Update(func(txn *badger.Txn) error {
+		if err := txn.Set(key, data); err != nil {
+			return err
+		}
+		return txn.Set([]byte(s.idPrefix+result.ID), key)
+	})
+}
+
+// Get returns the stored result of the request with the given ID.
+func (s *ResultStore) Get(id string) (StoredResult, error) {
+	var result StoredResult
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(s.idPrefix + id))
+		if err != nil {
+			return err
+		}
+		key, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		if item, err = txn.Get(key); err != nil {
+			return err
+		}
+		return item.Value(func(value []byte) error {
+			return json.Unmarshal(value, &result)
+		})
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return result, ErrResultNotFound
+	}
+	return result, err
+}
+
+// Query returns the stored results matching the query, newest first.
+func (s *ResultStore) Query(query ResultQuery) ([]StoredResult, error) {
+	var results []StoredResult
+	err := s.db.View(func(txn *badger.Txn) error {
+		options := badger.DefaultIteratorOptions
+		options.Reverse = true
+		iterator := txn.NewIterator(options)
+		defer iterator.Close()
+
+		// A reverse iteration starts at the last key that is not greater than the seek key
+		prefix := []byte(s.keyPrefix)
+		seek := append([]byte(s.keyPrefix), 0xff)
+		if !query.To.IsZero() {
+			seek = []byte(fmt.Sprintf("%s%020d", s.keyPrefix, query.To.UnixNano()))
+		}
+		for iterator.Seek(seek); iterator.ValidForPrefix(prefix); iterator.Next() {
+			if query.Limit > 0 && len(results) >= query.Limit {
+				break
+			}
+			var result StoredResult
+			err := iterator.Item().Value(func(value []byte) error {
+				return json.Unmarshal(value, &result)
+			})
+			if err != nil {
+				return err
+			}
+			if !query.From.IsZero() && result.StoredAt.Before(query.From) {
+				break
+			}
+			if !query.To.IsZero() && !result.StoredAt.Before(query.To) {
+				continue
+			}
+			if query.matches(result) {
+				results = append(results, result)
+			}
+		}
+		return nil
+	})
+	return results, err
+}
+
+// openResultStores opens the result store and the export spool that are enabled, in the database of the
+// worker stores. Stores that cannot be opened are left nil.
+func (whm *WorkHandlerManager) openResultStores(options *WorkerOption) {
+	if !options.isResultStoreEnabled && !options.isExportSpoolEnabled {
+		return
+	}
+	db, err := whm.database()
+	if err != nil {
+		logrus.Errorf("[-] Failed to open the result store and export spool, results will not be kept: %v", err)
+		return
+	}
+	if options.isResultStoreEnabled {
+		maxAge := orDefault(options.resultStoreMaxAge, workerConfig.ResultStoreMaxAge)
+		maxSize := orDefault(options.resultStoreMaxSize, workerConfig.ResultStoreMaxSize)
+		whm.results = NewResultStore(db, maxAge, maxSize)
+		go whm.results.pruneEvery(workerConfig.ResultStorePruneEvery)
+		logrus.Infof("[+] Keeping results for %s, up to %d bytes", maxAge, maxSize)
+	}
+	if options.isExportSpoolEnabled {
+		maxAge := orDefault(options.exportSpoolMaxAge, workerConfig.ExportSpoolMaxAge)
+		maxSize := orDefault(options.exportSpoolMaxSize, workerConfig.ExportSpoolMaxSize)
+		whm.spool = NewExportSpool(db, maxAge, maxSize)
+		go whm.spool.pruneEvery(workerConfig.ResultStorePruneEvery)
+		logrus.Infof("[+] Spooling results for export for %s, up to %d bytes", maxAge, maxSize)
+	}
+}
+
+// orDefault returns value, or the default if it is not set.
+func orDefault[T time.Duration | int64](value, def T) T {
+	if value > 0 {
+		return value
+	}
+	return def
+}
+
+// storeResult keeps a successful response in the result store and the export spool, if they are enabled.
+// It is called once per fetch of the result cache, so results served from the cache are not stored again.
+func (whm *WorkHandlerManager) storeResult(workRequest data_types.WorkRequest, response data_types.WorkResponse) {
+	if response.Error != "" || response.Data == nil || response.Data == "" {
+		return
+	}
+	for _, store := range []*ResultStore{whm.results, whm.spool} {
+		if store == nil {
+			continue
+		}
+		if err := store.Save(workRequest, response, time.Now()); err != nil {
+			logrus.Errorf("[-] Failed to keep the result of request %s in the %s: %v", workRequest.RequestId, store.name, err)
+		}
+	}
+}
+
+// storedEntry is a stored result key with its size, used for retention.
+type storedEntry struct {
+	key      []byte
+	id       string
+	storedAt time.Time
+	size     int64
+}
+
+// entries returns the keys of the stored results, in the order they were stored, with their total size.
+func (s *ResultStore) entries() ([]storedEntry, int64, error) {
+	var entries []storedEntry
+	var total int64
+	err := s.db.View(func(txn *badger.Txn) error {
+		options := badger.DefaultIteratorOptions
+		options.PrefetchValues = false
+		iterator := txn.NewIterator(options)
+		defer iterator.Close()
+
+		prefix := []byte(s.keyPrefix)
+		for iterator.Seek(prefix); iterator.ValidForPrefix(prefix); iterator.Next() {
+			item := iterator.Item()
+			key := item.KeyCopy(nil)
+			stamp, id, _ := strings.Cut(string(key[len(prefix):]), "/")
+			nanos, err := strconv.ParseInt(stamp, 10, 64)
+			if err != nil {
+				continue
+			}
+			entry := storedEntry{key: key, id: id, storedAt: time.Unix(0, nanos), size: item.EstimatedSize()}
+			entries = append(entries, entry)
+			total += entry.size
+		}
+		return nil
+	})
+	return entries, total, err
+}
+
+// Prune drops the results that are older than the maximum age, and then the oldest results while the
+// store is larger than the maximum size. It returns the number of dropped results.
+func (s *ResultStore) Prune(now time.Time) (int, error) {
+	entries, total, err := s.entries()
+	if err != nil {
+		return 0, err
+	}
+	// The keys, and so the entries, are in the order the results were stored
+	dropped := 0
+	for _, entry := range entries {
+		expired := s.maxAge > 0 && now.Sub(entry.storedAt) > s.maxAge
+		oversized := s.maxSize > 0 && total > s.maxSize
+		if !expired && !oversized {
+			break
+		}
+		dropped++
+		total -= entry.size
+	}
+	if dropped == 0 {
+		return 0, nil
+	}
+
+	batch := s.db.NewWriteBatch()
+	defer batch.Cancel()
+	for _, entry := range entries[:dropped] {
+		if err := batch.Delete(entry.key); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete([]byte(s.idPrefix + entry.id)); err != nil {
+			return 0, err
+		}
+	}
+	return dropped, batch.Flush()
+}
+
+// pruneEvery prunes the store at the given interval until it is closed.
+func (s *ResultStore) pruneEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closed:
+			return
+		case now := <-ticker.C:
+			dropped, err := s.Prune(now)
+			if err != nil {
+				logrus.Errorf("[-] Failed to prune the %s: %v", s.name, err)
+			} else if dropped > 0 {
+				logrus.Infof("[+] Dropped %d results from the %s", dropped, s.name)
+			}
+		}
+	}
+}
+
+// Close stops pruning the store. The database is closed by its owner.
+func (s *ResultStore) Close() {
+	close(s.closed)
+}
@@ -0,0 +1,158 @@
+package workers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func openTestResultStore(t *testing.T, maxAge time.Duration, maxSize int64) *ResultStore {
+	db, err := openDatabase(t.TempDir())
+	require.NoError(t, err)
+	store := NewResultStore(db, maxAge, maxSize)
+	t.Cleanup(func() {
+		store.Close()
+		_ = db.Close()
+	})
+	return store
+}
+
+func TestResultStore(t *testing.T) {
+	start := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	save := func(t *testing.T, store *ResultStore, id string, workType data_types.WorkerType, data string, worker string, at time.Time) {
+		request := data_types.WorkRequest{WorkType: workType, RequestId: id, Data: []byte(data)}
+		response := data_types.WorkResponse{Data: "sealed-" + id, WorkerPeerId: worker, Verification: tee.AttestationVerified}
+		require.NoError(t, store.Save(request, response, at))
+	}
+
+	t.Run("results are saved and retrieved by request ID", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		save(t, store, "req-1", data_types.Twitter, `{"query":"$MASA","count":10}`, "worker-1", start)
+
+		result, err := store.Get("req-1")
+		require.NoError(t, err)
+		assert.Equal(t, data_types.Twitter, result.WorkType)
+		assert.JSONEq(t, `{"query":"$MASA","count":10}`, string(result.Request))
+		assert.Equal(t, "worker-1", result.WorkerPeerId)
+		assert.Equal(t, "sealed-req-1", result.Data)
+		assert.Equal(t, tee.AttestationVerified, result.Verification)
+		assert.True(t, result.StoredAt.Equal(start))
+		assert.Len(t, result.ContentHash, 64)
+
+		response := result.Response()
+		assert.Equal(t, "sealed-req-1", response.Data)
+		assert.Equal(t, "worker-1", response.WorkerPeerId)
+
+		_, err = store.Get("req-2")
+		assert.ErrorIs(t, err, ErrResultNotFound)
+	})
+
+	t.Run("requests that are not JSON are stored as strings", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		save(t, store, "req-1", data_types.Web, "not json", "worker-1", start)
+
+		result, err := store.Get("req-1")
+		require.NoError(t, err)
+		assert.Equal(t, `"not json"`, string(result.Request))
+	})
+
+	t.Run("queries filter results and return the newest first", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		save(t, store, "req-1", data_types.Twitter, `{"query":"$MASA"}`, "worker-1", start)
+		save(t, store, "req-2", data_types.Web, `{"url":"https://masa.ai"}`, "worker-1", start.Add(time.Minute))
+		save(t, store, "req-3", data_types.Twitter, `{"query":"bitcoin"}`, "worker-2", start.Add(2*time.Minute))
+		save(t, store, "req-4", data_types.Twitter, `{"query":"$masa news"}`, "worker-2", start.Add(3*time.Minute))
+
+		ids := func(query ResultQuery) []string {
+			results, err := store.Query(query)
+			require.NoError(t, err)
+			ids := []string{}
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			return ids
+		}
+		assert.Equal(t, []string{"req-4", "req-3", "req-2", "req-1"}, ids(ResultQuery{}))
+		assert.Equal(t, []string{"req-4", "req-3", "req-1"}, ids(ResultQuery{WorkType: data_types.Twitter}))
+		assert.Equal(t, []string{"req-4", "req-3"}, ids(ResultQuery{Worker: "worker-2"}))
+		assert.Equal(t, []string{"req-4", "req-1"}, ids(ResultQuery{Query: "$MASA"}))
+		assert.Equal(t, []string{"req-3", "req-2"}, ids(ResultQuery{From: start.Add(time.Minute), To: start.Add(3 * time.Minute)}))
+		assert.Equal(t, []string{"req-4", "req-3"}, ids(ResultQuery{Limit: 2}))
+		assert.Equal(t, []string{"req-3"}, ids(ResultQuery{WorkType: data_types.Twitter, To: start.Add(3 * time.Minute), Limit: 1}))
+		assert.Empty(t, ids(ResultQuery{WorkType: data_types.Discord}))
+	})
+
+	t.Run("results older than the maximum age are pruned", func(t *testing.T) {
+		store := openTestResultStore(t, time.Hour, 0)
+		save(t, store, "req-1", data_types.Twitter, `{"query":"old"}`, "worker-1", start)
+		save(t, store, "req-2", data_types.Twitter, `{"query":"new"}`, "worker-1", start.Add(50*time.Minute))
+
+		dropped, err := store.Prune(start.Add(90 * time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, dropped)
+		_, err = store.Get("req-1")
+		assert.ErrorIs(t, err, ErrResultNotFound)
+		_, err = store.Get("req-2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("the oldest results are pruned while the store is too large", func(t *testing.T) {
+		store := openTestResultStore(t, 0, 0)
+		for i := 0; i < 5; i++ {
+			save(t, store, fmt.Sprintf("req-%d", i), data_types.Twitter, `{"query":"$MASA"}`, "worker-1", start.Add(time.Duration(i)*time.Minute))
+		}
+		entries, total, err := store.entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 5)
+
+		// Keep room for the two newest results
+		store.maxSize = total - entries[0].size - entries[1].size - entries[2].size
+
+		dropped, err := store.Prune(start.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 3, dropped)
+		remaining, err := store.Query(ResultQuery{})
+		require.NoError(t, err)
+		require.Len(t, remaining, 2)
+		assert.Equal(t, "req-4", remaining[0].ID)
+		assert.Equal(t, "req-3", remaining[1].ID)
+	})
+
+	t.Run("the work manager stores successful results only", func(t *testing.T) {
+		whm := &WorkHandlerManager{results: openTestResultStore(t, 0, 0)}
+		request := data_types.WorkRequest{WorkType: data_types.Twitter, RequestId: "req-1", Data: []byte(`{"query":"$MASA"}`)}
+
+		whm.storeResult(request, data_types.WorkResponse{Data: "tweets", WorkerPeerId: "worker-1"})
+		whm.storeResult(data_types.WorkRequest{RequestId: "req-2"}, data_types.WorkResponse{Error: "rate limited"})
+		whm.storeResult(data_types.WorkRequest{RequestId: "req-3"}, data_types.WorkResponse{})
+
+		results, err := whm.Results().Query(ResultQuery{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "req-1", results[0].ID)
+
+		// Without a store nothing is kept
+		(&WorkHandlerManager{}).storeResult(request, data_types.WorkResponse{Data: "tweets"})
+	})
+}
+
+func TestStoredResultUnsealableBy(t *testing.T) {
+	result := StoredResult{ID: "req-1", Requester: "client-1"}
+	assert.True(t, result.UnsealableBy("client-1"))
+	assert.False(t, result.UnsealableBy("client-2"))
+	assert.False(t, result.UnsealableBy(""), "clients without an API token cannot unseal results")
+
+	anonymous := StoredResult{ID: "req-2", Requester: data_types.AnonymousRequesterPrefix + "0123"}
+	assert.False(t, anonymous.UnsealableBy("client-1"))
+	assert.False(t, anonymous.UnsealableBy(""))
+
+	scheduled := StoredResult{ID: "req-3", Requester: ScheduleRequesterPrefix + "schedule-1"}
+	assert.True(t, scheduled.UnsealableBy("client-1"))
+	assert.False(t, scheduled.UnsealableBy(""))
+}
@@ -0,0 +1,99 @@
+package workers
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/node"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// RetryPolicy decides whether and when a work request whose dispatch failed is dispatched again.
+type RetryPolicy struct {
+	MaxAttempts int                    // number of dispatches, including the first one
+	BaseDelay   time.Duration          // delay before the first retry, doubled for every further retry
+	MaxDelay    time.Duration          // upper bound of the delay between dispatches
+	RetryOn     []data_types.ErrorCode // error codes that are worth another dispatch
+}
+
+// defaultRetryOn are the error codes retried by the default retry policy.
+var defaultRetryOn = []data_types.ErrorCode{
+	data_types.ErrorTimeout,
+	data_types.ErrorUpstreamUnavailable,
+	data_types.ErrorWorkerBusy,
+	data_types.ErrorRateLimited,
+}
+
+// retryPolicy returns the retry policy of the work type, or the default policy built from MaxRetries,
+// RetryBaseDelay and RetryMaxDelay if the work type has none.
+func retryPolicy(wType data_types.WorkerType) RetryPolicy {
+	if policy, ok := workerConfig.RetryPolicies[wType]; ok {
+		return policy
+	}
+	return RetryPolicy{
+		MaxAttempts: workerConfig.MaxRetries + 1,
+		BaseDelay:   workerConfig.RetryBaseDelay,
+		MaxDelay:    workerConfig.RetryMaxDelay,
+		RetryOn:     defaultRetryOn,
+	}
+}
+
+// backoff returns how long to wait before the given retry, counting from 1. The delay doubles with every
+// retry up to MaxDelay, and a random half of it is dropped so that requesters do not retry in lockstep.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// delay returns how long to wait before dispatching the failed request again, and false if it should
+// not be retried. A retry-after hint of the response is honoured, unless it exceeds MaxDelay.
+func (p RetryPolicy) delay(attempt int, response data_types.WorkResponse) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !slices.Contains(p.RetryOn, response.Code()) {
+		return 0, false
+	}
+	delay := p.backoff(attempt)
+	if retryAfter := time.Duration(response.RetryAfterMs) * time.Millisecond; retryAfter > delay {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return 0, false
+		}
+		delay = retryAfter
+	}
+	return delay, true
+}
+
+// distributeWithRetries dispatches the work request and, as long as the retry policy of its work type
+// allows, dispatches it again after a failure. Quorum requests are only dispatched once.
+func (whm *WorkHandlerManager) distributeWithRetries(ctx context.Context, node *node.OracleNode, workRequest data_types.WorkRequest, onRunning func(peerId string)) (response data_types.WorkResponse) {
+	policy := retryPolicy(workRequest.WorkType)
+	for attempt := 1; ; attempt++ {
+		response = whm.distributeWork(ctx, node, workRequest, onRunning)
+		if response.Error == "" || workRequest.Quorum != nil {
+			return response
+		}
+		delay, retry := policy.delay(attempt, response)
+		if !retry {
+			return response
+		}
+		logrus.Infof("[-] %s request failed with %s, dispatching it again in %s (attempt %d/%d)", workRequest.WorkType, response.Code(), delay, attempt+1, policy.MaxAttempts)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response
+		case <-timer.C:
+		}
+	}
+}
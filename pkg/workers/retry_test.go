@@ -0,0 +1,49 @@
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    300 * time.Millisecond,
+		RetryOn:     []data_types.ErrorCode{data_types.ErrorTimeout, data_types.ErrorRateLimited},
+	}
+
+	for retry, maxDelay := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 5: 300 * time.Millisecond} {
+		delay := policy.backoff(retry)
+		assert.GreaterOrEqual(t, delay, maxDelay/2)
+		assert.LessOrEqual(t, delay, maxDelay)
+	}
+
+	timeout := data_types.NewErrorResponse(data_types.ErrorTimeout, "timed out")
+	_, retry := policy.delay(1, timeout)
+	assert.True(t, retry)
+	_, retry = policy.delay(3, timeout)
+	assert.False(t, retry, "attempts exhausted")
+	_, retry = policy.delay(1, data_types.NewErrorResponse(data_types.ErrorNotFound, "not found"))
+	assert.False(t, retry, "error code not retried")
+
+	rateLimited := data_types.NewErrorResponse(data_types.ErrorRateLimited, "rate limited")
+	rateLimited.RetryAfterMs = 250
+	delay, retry := policy.delay(1, rateLimited)
+	assert.True(t, retry)
+	assert.Equal(t, 250*time.Millisecond, delay)
+	rateLimited.RetryAfterMs = 10000
+	_, retry = policy.delay(1, rateLimited)
+	assert.False(t, retry, "retry-after beyond the maximum delay")
+}
+
+func TestRetryPolicyDefault(t *testing.T) {
+	policy := retryPolicy(data_types.DiscordProfile)
+	assert.Equal(t, workerConfig.MaxRetries+1, policy.MaxAttempts)
+	assert.Equal(t, defaultRetryOn, policy.RetryOn)
+	assert.Equal(t, workerConfig.RetryPolicies[data_types.Web], retryPolicy(data_types.Web))
+}
@@ -0,0 +1,157 @@
+package workers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dgraph-io/badger"
+)
+
+const (
+	// scheduleKeyPrefix prefixes the keys of schedules in the schedule store.
+	scheduleKeyPrefix = "schedule/"
+	// resultKeyPrefix prefixes the keys of the results of schedules with a "store" sink. The key of a result
+	// is the prefix, the schedule ID and the zero-padded start time, so a schedule's results sort by time.
+	resultKeyPrefix = "result/"
+)
+
+// scheduleStore persists schedules, and the results of schedules whose sink is the local store.
+type scheduleStore interface {
+	SaveSchedule(schedule *Schedule) error
+	DeleteSchedule(id string) error
+	LoadSchedules() ([]*Schedule, error)
+	// SaveResult stores a result, dropping the oldest results of the schedule beyond limit.
+	SaveResult(result ScheduleResult, limit int) error
+	// Results returns at most limit results of the schedule, newest first.
+	Results(scheduleID string, limit int) ([]ScheduleResult, error)
+}
+
+// badgerScheduleStore is a scheduleStore backed by a badger database.
+type badgerScheduleStore struct {
+	db *badger.DB
+}
+
+// newBadgerScheduleStore returns the schedule store kept in the given database.
+func newBadgerScheduleStore(db *badger.DB) *badgerScheduleStore {
+	return &badgerScheduleStore{db: db}
+}
+
+func (s *badgerScheduleStore) SaveSchedule(schedule *Schedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(scheduleKeyPrefix+schedule.ID), data)
+	})
+}
+
+// DeleteSchedule deletes the schedule and its results.
+func (s *badgerScheduleStore) DeleteSchedule(id string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		for _, key := range keysWithPrefix(txn, resultPrefix(id)) {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return txn.Delete([]byte(scheduleKeyPrefix + id))
+	})
+}
+
+// LoadSchedules returns every stored schedule.
+func (s *badgerScheduleStore) LoadSchedules() ([]*Schedule, error) {
+	var schedules []*Schedule
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterator := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iterator.Close()
+
+		prefix := []byte(scheduleKeyPrefix)
+		for iterator.Seek(prefix); iterator.ValidForPrefix(prefix); iterator.Next() {
+			err := iterator.Item().Value(func(value []byte) error {
+				var schedule Schedule
+				if err := json.Unmarshal(value, &schedule); err != nil {
+					return err
+				}
+				schedules = append(schedules, &schedule)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return schedules, err
+}
+
+func (s *badgerScheduleStore) SaveResult(result ScheduleResult, limit int) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		key := fmt.Sprintf("%s%020d/%s", resultPrefix(result.ScheduleID), result.StartedAt.UnixNano(), result.JobID)
+		if err := txn.Set([]byte(key), data); err != nil {
+			return err
+		}
+		if limit <= 0 {
+			return nil
+		}
+		keys := keysWithPrefix(txn, resultPrefix(result.ScheduleID))
+		for i := 0; i < len(keys)-limit; i++ {
+			if err := txn.Delete(keys[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *badgerScheduleStore) Results(scheduleID string, limit int) ([]ScheduleResult, error) {
+	var results []ScheduleResult
+	err := s.db.View(func(txn *badger.Txn) error {
+		options := badger.DefaultIteratorOptions
+		options.Reverse = true
+		iterator := txn.NewIterator(options)
+		defer iterator.Close()
+
+		// A reverse iteration starts at the last key that is not greater than the seek key
+		prefix := resultPrefix(scheduleID)
+		for iterator.Seek(append(prefix, 0xff)); iterator.ValidForPrefix(prefix); iterator.Next() {
+			if limit > 0 && len(results) >= limit {
+				break
+			}
+			err := iterator.Item().Value(func(value []byte) error {
+				var result ScheduleResult
+				if err := json.Unmarshal(value, &result); err != nil {
+					return err
+				}
+				results = append(results, result)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return results, err
+}
+
+func resultPrefix(scheduleID string) []byte {
+	return []byte(resultKeyPrefix + scheduleID + "/")
+}
+
+// keysWithPrefix returns the keys with the given prefix in ascending order.
+func keysWithPrefix(txn *badger.Txn, prefix []byte) [][]byte {
+	options := badger.DefaultIteratorOptions
+	options.PrefetchValues = false
+	iterator := txn.NewIterator(options)
+	defer iterator.Close()
+
+	var keys [][]byte
+	for iterator.Seek(prefix); iterator.ValidForPrefix(prefix); iterator.Next() {
+		keys = append(keys, iterator.Item().KeyCopy(nil))
+	}
+	return keys
+}
@@ -0,0 +1,500 @@
+package workers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/node"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+// ScheduleRequesterPrefix prefixes the requester of the work requests of a schedule, followed by its ID.
+// Schedules do not belong to an API client, so neither do their results.
+const ScheduleRequesterPrefix = "schedule/"
+
+// SinkType is where the results of a schedule are delivered.
+type SinkType string
+
+const (
+	SinkStore   SinkType = "store"   // the node's schedule store, read back through Scheduler.Results
+	SinkFile    SinkType = "file"    // a JSON lines file in the node's schedule results directory
+	SinkWebhook SinkType = "webhook" // an HTTP POST of the result to a URL
+)
+
+var (
+	// ErrScheduleNotFound is returned for operations on a schedule that does not exist.
+	ErrScheduleNotFound = errors.New("schedule not found")
+	// ErrInvalidSchedule wraps the reason a schedule was rejected.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+)
+
+// Sink configures where the results of a schedule are delivered. Path is the file of a file sink, relative
+// to the schedule results directory, and defaults to "<schedule ID>.jsonl". URL is the URL of a webhook sink.
+type Sink struct {
+	Type SinkType `json:"type"`
+	Path string   `json:"path,omitempty"`
+	URL  string   `json:"url,omitempty"`
+}
+
+// ScheduleRun is the outcome of a run of a schedule.
+type ScheduleRun struct {
+	JobID      string               `json:"jobId,omitempty"`
+	Status     JobStatus            `json:"status"`
+	Error      string               `json:"error,omitempty"`
+	ErrorCode  data_types.ErrorCode `json:"errorCode,omitempty"`
+	SinkError  string               `json:"sinkError,omitempty"` // why the result could not be delivered to the sink
+	StartedAt  time.Time            `json:"startedAt"`
+	FinishedAt time.Time            `json:"finishedAt"`
+}
+
+// Schedule is a work request that is submitted as a job whenever its cron expression fires.
+type Schedule struct {
+	ID           string                `json:"id"`
+	Name         string                `json:"name,omitempty"`
+	Cron         string                `json:"cron"`
+	WorkType     data_types.WorkerType `json:"workType"`
+	Payload      json.RawMessage       `json:"payload"`
+	Sink         Sink                  `json:"sink"`
+	Paused       bool                  `json:"paused"`
+	CreatedAt    time.Time             `json:"createdAt"`
+	UpdatedAt    time.Time             `json:"updatedAt"`
+	NextRun      time.Time             `json:"nextRun"`
+	RunningJobID string                `json:"runningJobId,omitempty"`
+	LastRun      *ScheduleRun          `json:"lastRun,omitempty"`
+	SkippedRuns  int                   `json:"skippedRuns"` // runs skipped because the previous run was still going
+
+	parsed     cron.Schedule
+	delivering bool
+}
+
+// ScheduleResult is the result of a run of a schedule, as delivered to its sink.
+type ScheduleResult struct {
+	ScheduleID   string                `json:"scheduleId"`
+	ScheduleName string                `json:"scheduleName,omitempty"`
+	JobID        string                `json:"jobId"`
+	WorkType     data_types.WorkerType `json:"workType"`
+	Status       JobStatus             `json:"status"`
+	Error        string                `json:"error,omitempty"`
+	ErrorCode    data_types.ErrorCode  `json:"errorCode,omitempty"`
+	WorkerPeerId string                `json:"workerPeerId,omitempty"`
+	Data         interface{}           `json:"data,omitempty"`
+	StartedAt    time.Time             `json:"startedAt"`
+	FinishedAt   time.Time             `json:"finishedAt"`
+}
+
+// Scheduler submits the work requests of schedules to the JobManager whenever their cron expressions fire,
+// and delivers the results to the schedules' sinks.
+//
+// Schedules are persisted in the node's schedule store, and a run that was in progress when the node stopped
+// is picked up again since its job is persisted too. Runs missed while the node was down are not made up.
+// A schedule never runs twice at once: a run that is due while the previous one has not finished is skipped.
+type Scheduler struct {
+	mu        sync.Mutex
+	schedules map[string]*Schedule
+	store     scheduleStore
+	jobs      *JobManager
+	config    *WorkerConfig
+	resultDir string
+	webhooks  WebhookAllowlist
+	client    *http.Client
+}
+
+// NewScheduler creates a Scheduler that submits jobs to the given JobManager and keeps the schedules in the
+// schedule store of the given WorkHandlerManager. If the store cannot be opened the schedules are only kept in
+// memory, and results cannot be delivered to the store sink.
+func NewScheduler(node *node.OracleNode, whm *WorkHandlerManager, jobs *JobManager) *Scheduler {
+	var store scheduleStore
+	db, err := whm.database()
+	if err != nil {
+		logrus.Errorf("[-] Failed to open the schedule store, schedules will not survive a restart: %v", err)
+	} else {
+		store = newBadgerScheduleStore(db)
+	}
+	s := newScheduler(workerConfig, store, jobs, filepath.Join(node.Options.MasaDir, "schedule-results"), whm.webhookAllowlist)
+	go s.run()
+	return s
+}
+
+func newScheduler(config *WorkerConfig, store scheduleStore, jobs *JobManager, resultDir string, webhooks WebhookAllowlist) *Scheduler {
+	s := &Scheduler{
+		schedules: make(map[string]*Schedule),
+		store:     store,
+		jobs:      jobs,
+		config:    config,
+		resultDir: resultDir,
+		webhooks:  webhooks,
+		client:    webhooks.client(config.WebhookTimeout),
+	}
+	s.restore(time.Now())
+	return s
+}
+
+// restore loads the schedules of the store.
+func (s *Scheduler) restore(now time.Time) {
+	if s.store == nil {
+		return
+	}
+	schedules, err := s.store.LoadSchedules()
+	if err != nil {
+		logrus.Errorf("[-] Failed to load schedules from the schedule store: %v", err)
+		return
+	}
+	for _, schedule := range schedules {
+		parsed, err := cron.ParseStandard(schedule.Cron)
+		if err != nil {
+			logrus.Errorf("[-] Dropping schedule %s with invalid cron expression %q: %v", schedule.ID, schedule.Cron, err)
+			continue
+		}
+		schedule.parsed = parsed
+		if schedule.NextRun.Before(now) {
+			schedule.NextRun = parsed.Next(now)
+		}
+		s.schedules[schedule.ID] = schedule
+	}
+	if len(schedules) > 0 {
+		logrus.Infof("[+] Restored %d schedules from the schedule store", len(s.schedules))
+	}
+}
+
+// save persists the schedule. The caller must hold s.mu.
+func (s *Scheduler) save(schedule *Schedule) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.SaveSchedule(schedule); err != nil {
+		logrus.Errorf("[-] Failed to persist schedule %s: %v", schedule.ID, err)
+	}
+}
+
+// Create validates and adds a schedule built from the name, cron expression, work type, payload and sink of
+// the given one. The payload is validated against the request schema of the work type, and the sink defaults
+// to the store. Errors caused by the schedule itself wrap ErrInvalidSchedule.
+func (s *Scheduler) Create(spec Schedule) (Schedule, error) {
+	parsed, err := cron.ParseStandard(spec.Cron)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+	now := time.Now()
+	nextRun := parsed.Next(now)
+	if nextRun.IsZero() {
+		return Schedule{}, fmt.Errorf("%w: cron expression %q never fires", ErrInvalidSchedule, spec.Cron)
+	}
+	payload, err := data_types.NormalizeRequestData(spec.WorkType, spec.Payload)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+	if spec.Sink.Type == "" {
+		spec.Sink.Type = SinkStore
+	}
+	if err := s.validateSink(spec.Sink); err != nil {
+		return Schedule{}, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+
+	schedule := &Schedule{
+		ID:        uuid.New().String(),
+		Name:      spec.Name,
+		Cron:      spec.Cron,
+		WorkType:  spec.WorkType,
+		Payload:   payload,
+		Sink:      spec.Sink,
+		CreatedAt: now,
+		UpdatedAt: now,
+		NextRun:   nextRun,
+		parsed:    parsed,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedules[schedule.ID] = schedule
+	s.save(schedule)
+	logrus.Infof("[+] Created schedule %s for %s, next run at %s", schedule.ID, schedule.WorkType, schedule.NextRun)
+	return *schedule, nil
+}
+
+func (s *Scheduler) validateSink(sink Sink) error {
+	switch sink.Type {
+	case SinkStore:
+		return nil
+	case SinkFile:
+		if sink.Path != "" && !filepath.IsLocal(sink.Path) {
+			return fmt.Errorf("file sink path %q must be relative and stay within the schedule results directory", sink.Path)
+		}
+		return nil
+	case SinkWebhook:
+		return s.webhooks.checkURL(sink.URL)
+	default:
+		return fmt.Errorf("unknown sink type %q, expected %q, %q or %q", sink.Type, SinkStore, SinkFile, SinkWebhook)
+	}
+}
+
+// List returns every schedule, oldest first.
+func (s *Scheduler) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedules := make([]Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, *schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].CreatedAt.Before(schedules[j].CreatedAt) })
+	return schedules
+}
+
+// Get returns a copy of the schedule with the given ID.
+func (s *Scheduler) Get(id string) (Schedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedule, exists := s.schedules[id]
+	if !exists {
+		return Schedule{}, false
+	}
+	return *schedule, true
+}
+
+// SetPaused pauses or resumes a schedule. A paused schedule does not start new runs, but a run that is in
+// progress is finished and delivered. A resumed schedule next runs when its cron expression next fires.
+func (s *Scheduler) SetPaused(id string, paused bool) (Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedule, exists := s.schedules[id]
+	if !exists {
+		return Schedule{}, ErrScheduleNotFound
+	}
+	if schedule.Paused != paused {
+		now := time.Now()
+		schedule.Paused = paused
+		schedule.UpdatedAt = now
+		if !paused {
+			schedule.NextRun = schedule.parsed.Next(now)
+		}
+		s.save(schedule)
+	}
+	return *schedule, nil
+}
+
+// Delete removes a schedule and its stored results. A run that is in progress is not delivered.
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.schedules[id]; !exists {
+		return ErrScheduleNotFound
+	}
+	delete(s.schedules, id)
+	if s.store != nil {
+		if err := s.store.DeleteSchedule(id); err != nil {
+			logrus.Errorf("[-] Failed to delete schedule %s from the schedule store: %v", id, err)
+		}
+	}
+	logrus.Infof("[+] Deleted schedule %s", id)
+	return nil
+}
+
+// Results returns at most limit results of the schedule stored by the store sink, newest first.
+func (s *Scheduler) Results(id string, limit int) ([]ScheduleResult, error) {
+	if _, exists := s.Get(id); !exists {
+		return nil, ErrScheduleNotFound
+	}
+	if s.store == nil {
+		return nil, nil
+	}
+	return s.store.Results(id, limit)
+}
+
+// run checks the schedules every SchedulerInterval.
+func (s *Scheduler) run() {
+	ticker := time.NewTicker(s.config.SchedulerInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		s.runDue(now)
+	}
+}
+
+// runDue delivers the runs that finished, and starts the runs that are due at the given time.
+func (s *Scheduler) runDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, schedule := range s.schedules {
+		if schedule.RunningJobID != "" && !schedule.delivering {
+			s.checkRun(schedule, now)
+		}
+		if schedule.Paused || now.Before(schedule.NextRun) {
+			continue
+		}
+		schedule.NextRun = schedule.parsed.Next(now)
+		if schedule.RunningJobID != "" {
+			schedule.SkippedRuns++
+			logrus.Warnf("[-] Skipping run of schedule %s, its previous run %s has not finished", schedule.ID, schedule.RunningJobID)
+			s.save(schedule)
+			continue
+		}
+		s.start(schedule, now)
+	}
+}
+
+// start submits the work request of the schedule as a job. The caller must hold s.mu.
+func (s *Scheduler) start(schedule *Schedule, now time.Time) {
+	job, err := s.jobs.Submit(data_types.WorkRequest{
+		WorkType:   schedule.WorkType,
+		RequestId:  uuid.New().String(),
+		Data:       schedule.Payload,
+		Requester:  ScheduleRequesterPrefix + schedule.ID,
+		KeepSealed: data_types.KeepSealedByDefault(),
+		// A recurring scrape is after new data, not a result cached for an earlier request
+		NoCache: true,
+	})
+	if err != nil {
+		logrus.Errorf("[-] Failed to start a run of schedule %s: %v", schedule.ID, err)
+		schedule.LastRun = &ScheduleRun{
+			Status:     JobFailed,
+			Error:      fmt.Sprintf("failed to submit job: %v", err),
+			StartedAt:  now,
+			FinishedAt: now,
+		}
+	} else {
+		logrus.Infof("[+] Started run %s of schedule %s", job.ID, schedule.ID)
+		schedule.RunningJobID = job.ID
+	}
+	s.save(schedule)
+}
+
+// checkRun delivers the result of the schedule's running job in the background once the job is done.
+// The caller must hold s.mu.
+func (s *Scheduler) checkRun(schedule *Schedule, now time.Time) {
+	job, exists := s.jobs.Get(schedule.RunningJobID)
+	if !exists {
+		logrus.Errorf("[-] Job %s of schedule %s was lost", schedule.RunningJobID, schedule.ID)
+		schedule.LastRun = &ScheduleRun{
+			JobID:      schedule.RunningJobID,
+			Status:     JobFailed,
+			Error:      "job was lost",
+			ErrorCode:  data_types.ErrorInternal,
+			FinishedAt: now,
+		}
+		schedule.RunningJobID = ""
+		s.save(schedule)
+		return
+	}
+	if !job.IsDone() {
+		return
+	}
+	schedule.delivering = true
+	go s.deliver(*schedule, job)
+}
+
+// deliver sends the result of the finished job to the schedule's sink and records the run.
+func (s *Scheduler) deliver(schedule Schedule, job Job) {
+	result := ScheduleResult{
+		ScheduleID:   schedule.ID,
+		ScheduleName: schedule.Name,
+		JobID:        job.ID,
+		WorkType:     job.WorkType,
+		Status:       job.Status,
+		Error:        job.Error,
+		ErrorCode:    job.ErrorCode,
+		WorkerPeerId: job.WorkerPeerId,
+		StartedAt:    job.CreatedAt,
+		FinishedAt:   job.CompletedAt,
+	}
+	if job.Response != nil {
+		result.Data = job.Response.Data
+	}
+	run := &ScheduleRun{
+		JobID:      job.ID,
+		Status:     job.Status,
+		Error:      job.Error,
+		ErrorCode:  job.ErrorCode,
+		StartedAt:  job.CreatedAt,
+		FinishedAt: job.CompletedAt,
+	}
+	if err := s.writeResult(schedule.Sink, result); err != nil {
+		logrus.Errorf("[-] Failed to deliver the result of run %s of schedule %s to its %s sink: %v", job.ID, schedule.ID, schedule.Sink.Type, err)
+		run.SinkError = err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, exists := s.schedules[schedule.ID]
+	if !exists {
+		return
+	}
+	current.LastRun = run
+	current.RunningJobID = ""
+	current.delivering = false
+	s.save(current)
+}
+
+// writeResult delivers a result to a sink.
+func (s *Scheduler) writeResult(sink Sink, result ScheduleResult) error {
+	switch sink.Type {
+	case SinkStore:
+		if s.store == nil {
+			return errors.New("the schedule store is not available")
+		}
+		return s.store.SaveResult(result, s.config.ScheduleResultLimit)
+	case SinkFile:
+		return s.appendResult(sink.Path, result)
+	case SinkWebhook:
+		return s.postResult(sink.URL, result)
+	default:
+		return fmt.Errorf("unknown sink type %q", sink.Type)
+	}
+}
+
+// appendResult appends the result as a line of JSON to the file sink's file.
+func (s *Scheduler) appendResult(path string, result ScheduleResult) error {
+	if path == "" {
+		path = result.ScheduleID + ".jsonl"
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.resultDir, 0o755); err != nil {
+		return err
+	}
+	path = filepath.Join(s.resultDir, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// postResult sends the result as JSON to the webhook sink's URL.
+func (s *Scheduler) postResult(target string, result ScheduleResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", response.Status)
+	}
+	return nil
+}
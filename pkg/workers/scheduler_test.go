@@ -0,0 +1,183 @@
+package workers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func newTestScheduler(t *testing.T, dir string, dispatch dispatchFunc) (*Scheduler, *badgerScheduleStore) {
+	db, err := openDatabase(filepath.Join(dir, "store"))
+	require.NoError(t, err)
+	store := newBadgerScheduleStore(db)
+	jm := newJobManager(testJobConfig(), nil, dispatch)
+	// The webhook servers of the tests listen on 127.0.0.1, other internal addresses are refused
+	webhooks, err := ParseWebhookAllowlist("127.0.0.1")
+	require.NoError(t, err)
+	return newScheduler(testJobConfig(), store, jm, filepath.Join(dir, "schedule-results"), webhooks), store
+}
+
+// waitForRun runs the scheduler until the schedule's current run has been delivered.
+func waitForRun(t *testing.T, s *Scheduler, id string) Schedule {
+	var schedule Schedule
+	require.Eventually(t, func() bool {
+		s.runDue(time.Now())
+		schedule, _ = s.Get(id)
+		return schedule.RunningJobID == "" && schedule.LastRun != nil
+	}, time.Second, 10*time.Millisecond)
+	return schedule
+}
+
+func TestScheduler(t *testing.T) {
+	t.Setenv("KEEP_SEALED_DATA", "true")
+	webPayload := json.RawMessage(`{"url":"https://masa.ai"}`)
+	succeed := func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+		return data_types.WorkResponse{Data: "result", WorkerPeerId: "peer-1"}
+	}
+
+	t.Run("rejects invalid schedules", func(t *testing.T) {
+		s, store := newTestScheduler(t, t.TempDir(), succeed)
+		defer store.db.Close()
+
+		for _, spec := range []Schedule{
+			{Cron: "not a cron", WorkType: data_types.Web, Payload: webPayload},
+			{Cron: "0 0 31 2 *", WorkType: data_types.Web, Payload: webPayload},
+			{Cron: "@hourly", WorkType: data_types.Web, Payload: json.RawMessage(`{"depth":1}`)},
+			{Cron: "@hourly", WorkType: data_types.Web, Payload: webPayload, Sink: Sink{Type: SinkFile, Path: "../escape.jsonl"}},
+			{Cron: "@hourly", WorkType: data_types.Web, Payload: webPayload, Sink: Sink{Type: SinkWebhook, URL: "ftp://example.com"}},
+			{Cron: "@hourly", WorkType: data_types.Web, Payload: webPayload, Sink: Sink{Type: SinkWebhook, URL: "http://169.254.169.254/latest/meta-data/"}},
+			{Cron: "@hourly", WorkType: data_types.Web, Payload: webPayload, Sink: Sink{Type: SinkWebhook, URL: "http://localhost:8080/api/v1/jobs"}},
+			{Cron: "@hourly", WorkType: data_types.Web, Payload: webPayload, Sink: Sink{Type: "s3"}},
+		} {
+			_, err := s.Create(spec)
+			assert.ErrorIs(t, err, ErrInvalidSchedule, spec.Cron)
+		}
+		assert.Empty(t, s.List())
+	})
+
+	t.Run("runs due schedules, stores results and survives a restart", func(t *testing.T) {
+		dir := t.TempDir()
+		s, store := newTestScheduler(t, dir, succeed)
+
+		schedule, err := s.Create(Schedule{Name: "masa", Cron: "@every 1m", WorkType: data_types.Web, Payload: webPayload})
+		require.NoError(t, err)
+		assert.Equal(t, SinkStore, schedule.Sink.Type)
+		assert.JSONEq(t, `{"url":"https://masa.ai","depth":1}`, string(schedule.Payload))
+
+		s.runDue(time.Now())
+		current, _ := s.Get(schedule.ID)
+		assert.Empty(t, current.RunningJobID, "not due yet")
+
+		s.runDue(schedule.NextRun)
+		done := waitForRun(t, s, schedule.ID)
+		assert.Equal(t, JobSucceeded, done.LastRun.Status)
+		assert.Empty(t, done.LastRun.SinkError)
+		assert.True(t, done.NextRun.After(schedule.NextRun))
+
+		results, err := s.Results(schedule.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "result", results[0].Data)
+		assert.Equal(t, done.LastRun.JobID, results[0].JobID)
+
+		_, err = s.SetPaused(schedule.ID, true)
+		require.NoError(t, err)
+		require.NoError(t, store.db.Close())
+
+		restarted, store := newTestScheduler(t, dir, succeed)
+		defer store.db.Close()
+		restored, exists := restarted.Get(schedule.ID)
+		require.True(t, exists)
+		assert.True(t, restored.Paused)
+		require.NotNil(t, restored.LastRun)
+		assert.Equal(t, done.LastRun.JobID, restored.LastRun.JobID)
+
+		restarted.runDue(restored.NextRun.Add(time.Hour))
+		restored, _ = restarted.Get(schedule.ID)
+		assert.Empty(t, restored.RunningJobID, "paused schedules do not run")
+
+		require.NoError(t, restarted.Delete(schedule.ID))
+		_, err = restarted.Results(schedule.ID, 10)
+		assert.ErrorIs(t, err, ErrScheduleNotFound)
+		results, err = store.Results(schedule.ID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("skips runs while the previous run is going", func(t *testing.T) {
+		release := make(chan struct{})
+		s, store := newTestScheduler(t, t.TempDir(), func(workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+			<-release
+			return data_types.WorkResponse{Data: "result"}
+		})
+		defer store.db.Close()
+
+		schedule, err := s.Create(Schedule{Cron: "* * * * *", WorkType: data_types.Web, Payload: webPayload})
+		require.NoError(t, err)
+		s.runDue(schedule.NextRun)
+		running, _ := s.Get(schedule.ID)
+		require.NotEmpty(t, running.RunningJobID)
+
+		s.runDue(running.NextRun)
+		skipped, _ := s.Get(schedule.ID)
+		assert.Equal(t, running.RunningJobID, skipped.RunningJobID)
+		assert.Equal(t, 1, skipped.SkippedRuns)
+
+		close(release)
+		done := waitForRun(t, s, schedule.ID)
+		assert.Equal(t, running.RunningJobID, done.LastRun.JobID)
+	})
+
+	t.Run("delivers results to file and webhook sinks", func(t *testing.T) {
+		received := make(chan ScheduleResult, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var result ScheduleResult
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&result))
+			received <- result
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		s, store := newTestScheduler(t, dir, succeed)
+		defer store.db.Close()
+
+		fileSchedule, err := s.Create(Schedule{Cron: "@hourly", WorkType: data_types.Web, Payload: webPayload, Sink: Sink{Type: SinkFile, Path: "masa/results.jsonl"}})
+		require.NoError(t, err)
+		webhookSchedule, err := s.Create(Schedule{Cron: "@hourly", WorkType: data_types.Web, Payload: webPayload, Sink: Sink{Type: SinkWebhook, URL: server.URL}})
+		require.NoError(t, err)
+
+		s.runDue(fileSchedule.NextRun)
+		fileRun := waitForRun(t, s, fileSchedule.ID)
+		webhookRun := waitForRun(t, s, webhookSchedule.ID)
+		assert.Empty(t, fileRun.LastRun.SinkError)
+		assert.Empty(t, webhookRun.LastRun.SinkError)
+
+		file, err := os.Open(filepath.Join(dir, "schedule-results", "masa", "results.jsonl"))
+		require.NoError(t, err)
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		require.True(t, scanner.Scan())
+		var result ScheduleResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		assert.Equal(t, fileRun.LastRun.JobID, result.JobID)
+		assert.Equal(t, "result", result.Data)
+
+		select {
+		case result := <-received:
+			assert.Equal(t, webhookRun.LastRun.JobID, result.JobID)
+			assert.Equal(t, JobSucceeded, result.Status)
+		default:
+			t.Fatal("webhook was not called")
+		}
+	})
+}
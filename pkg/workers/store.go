@@ -0,0 +1,44 @@
+package workers
+
+import (
+	"path/filepath"
+
+	"github.com/dgraph-io/badger"
+)
+
+// The job store, the schedule store and the result store share one badger database in the masa directory,
+// each under its own key prefixes: jobKeyPrefix for jobs, scheduleKeyPrefix and resultKeyPrefix for
+// schedules and their results, storedResultKeyPrefix and storedResultIDPrefix for stored results, and
+// spooledResultKeyPrefix and spooledResultIDPrefix for the export spool.
+
+// openDatabase opens, or creates, the badger database in the given directory.
+func openDatabase(path string) (*badger.DB, error) {
+	options := badger.DefaultOptions(path)
+	options.Logger = nil
+	options.SyncWrites = true
+	return badger.Open(options)
+}
+
+// database returns the database of the worker stores, opening it on first use.
+func (whm *WorkHandlerManager) database() (*badger.DB, error) {
+	whm.dbOnce.Do(func() {
+		whm.db, whm.dbErr = openDatabase(filepath.Join(whm.masaDir, "store"))
+	})
+	return whm.db, whm.dbErr
+}
+
+// Close stops pruning the result store and the export spool, and closes the database of the worker stores. It is called once
+// the node stops.
+func (whm *WorkHandlerManager) Close() error {
+	// The database cannot be opened once the manager is closed
+	whm.dbOnce.Do(func() {})
+	for _, store := range []*ResultStore{whm.results, whm.spool} {
+		if store != nil {
+			store.Close()
+		}
+	}
+	if whm.db == nil {
+		return nil
+	}
+	return whm.db.Close()
+}
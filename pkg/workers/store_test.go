@@ -0,0 +1,46 @@
+package workers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func TestWorkerStoresShareOneDatabase(t *testing.T) {
+	dir := t.TempDir()
+	whm := NewWorkHandlerManager(WithMasaDir(dir), EnableResultStore)
+	require.NotNil(t, whm.Results())
+	db, err := whm.database()
+	require.NoError(t, err)
+
+	request := data_types.WorkRequest{WorkType: data_types.Web, RequestId: "req-1", Data: []byte(`{"url":"https://masa.ai"}`)}
+	require.NoError(t, newBadgerJobStore(db).Save(&Job{ID: "job-1", Status: JobQueued, WorkType: data_types.Web, Request: request}))
+	require.NoError(t, newBadgerScheduleStore(db).SaveSchedule(&Schedule{ID: "schedule-1", Cron: "@hourly", WorkType: data_types.Web}))
+	require.NoError(t, whm.Results().Save(request, data_types.WorkResponse{Data: "page", Unsealed: true}, time.Now()))
+	require.NoError(t, whm.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "store", entries[0].Name())
+
+	// Each store only sees its own keys once the database is opened again
+	db, err = openDatabase(filepath.Join(dir, "store"))
+	require.NoError(t, err)
+	defer db.Close()
+	jobs, err := newBadgerJobStore(db).Load()
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	schedules, err := newBadgerScheduleStore(db).LoadSchedules()
+	require.NoError(t, err)
+	assert.Len(t, schedules, 1)
+	results, err := NewResultStore(db, 0, 0).Query(ResultQuery{})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
@@ -0,0 +1,69 @@
+package data_types
+
+import "fmt"
+
+// ErrorCode classifies why a work request failed, so that requesters can decide how to answer and whether to
+// retry without parsing the error message.
+type ErrorCode string
+
+const (
+	ErrorRateLimited         ErrorCode = "rate_limited"         // the data source rate limited the worker
+	ErrorAuthFailed          ErrorCode = "auth_failed"          // the worker's credentials for the data source were rejected
+	ErrorInvalidRequest      ErrorCode = "invalid_request"      // the request data does not match its schema
+	ErrorTimeout             ErrorCode = "timeout"              // the work did not finish in time
+	ErrorWorkerBusy          ErrorCode = "worker_busy"          // the worker had no capacity left and did not execute the request
+	ErrorUpstreamUnavailable ErrorCode = "upstream_unavailable" // the worker, or the data source behind it, could not be reached
+	ErrorNotFound            ErrorCode = "not_found"            // the requested data does not exist
+	ErrorInternal            ErrorCode = "internal"             // any other failure
+	ErrorCancelled           ErrorCode = "cancelled"            // the requester abandoned the work
+	ErrorAttestationFailed   ErrorCode = "attestation_failed"   // the worker did not prove its result comes from a trusted enclave
+	ErrorUnsupported         ErrorCode = "unsupported"          // no worker in the network offers the work type
+)
+
+// Retryable reports whether a request that failed with the code may succeed when sent again, to the same
+// worker later or to another worker.
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case ErrorInvalidRequest, ErrorNotFound, ErrorCancelled, ErrorUnsupported:
+		return false
+	default:
+		return true
+	}
+}
+
+// WorkerFault reports whether a failure with the code is attributed to the worker and counts against its
+// reputation. Requests that were invalid, asked for missing data, were abandoned, were refused for lack
+// of capacity or found no worker at all say nothing about the worker.
+func (c ErrorCode) WorkerFault() bool {
+	switch c {
+	case ErrorInvalidRequest, ErrorNotFound, ErrorCancelled, ErrorWorkerBusy, ErrorUnsupported:
+		return false
+	default:
+		return true
+	}
+}
+
+// NewErrorResponse returns a failed WorkResponse with the given code and formatted message.
+// Retryable is set according to the code.
+func NewErrorResponse(code ErrorCode, format string, args ...interface{}) WorkResponse {
+	return WorkResponse{
+		Error:     fmt.Sprintf(format, args...),
+		ErrorCode: code,
+		Retryable: code.Retryable(),
+	}
+}
+
+// Code returns the error code of the response, or "" if it succeeded. Responses of peers that predate
+// error codes are classified from their Busy flag, or as internal errors.
+func (wr *WorkResponse) Code() ErrorCode {
+	switch {
+	case wr.ErrorCode != "":
+		return wr.ErrorCode
+	case wr.Busy:
+		return ErrorWorkerBusy
+	case wr.Error != "":
+		return ErrorInternal
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,31 @@
+package data_types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	response := NewErrorResponse(ErrorNotFound, "profile %s not found", "masa")
+	assert.Equal(t, "profile masa not found", response.Error)
+	assert.Equal(t, ErrorNotFound, response.Code())
+	assert.False(t, response.Retryable)
+
+	response = NewErrorResponse(ErrorRateLimited, "rate limited")
+	assert.True(t, response.Retryable)
+	assert.True(t, response.Code().WorkerFault())
+}
+
+func TestWorkResponseCode(t *testing.T) {
+	// Peers that predate error codes only report the message and the busy flag
+	assert.Equal(t, ErrorCode(""), (&WorkResponse{}).Code())
+	assert.Equal(t, ErrorWorkerBusy, (&WorkResponse{Error: "worker busy", Busy: true}).Code())
+	assert.Equal(t, ErrorInternal, (&WorkResponse{Error: "something broke"}).Code())
+
+	assert.False(t, ErrorWorkerBusy.WorkerFault())
+	assert.True(t, ErrorWorkerBusy.Retryable())
+	assert.False(t, ErrorCancelled.Retryable())
+	assert.False(t, ErrorUnsupported.Retryable())
+	assert.False(t, ErrorUnsupported.WorkerFault())
+}
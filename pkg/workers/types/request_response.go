@@ -1,8 +1,9 @@
 package data_types
 
 import (
-	"os"
+	"encoding/json"
 
+	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/multiformats/go-multiaddr"
 	"github.com/sirupsen/logrus"
@@ -10,7 +11,6 @@ import (
 	"github.com/masa-finance/masa-oracle/node"
 	"github.com/masa-finance/masa-oracle/pkg/pubsub"
 	"github.com/masa-finance/masa-oracle/pkg/tee"
-	"github.com/masa-finance/masa-oracle/pkg/utils"
 )
 
 type Worker struct {
@@ -47,33 +47,85 @@ func NewWorker(isLocal bool, nd *pubsub.NodeData) *Worker {
 }
 
 type WorkRequest struct {
-	WorkType  WorkerType `json:"workType,omitempty"`
-	RequestId string     `json:"requestId,omitempty"`
-	Data      []byte     `json:"data,omitempty"`
+	WorkType   WorkerType       `json:"workType,omitempty"`
+	RequestId  string           `json:"requestId,omitempty"`
+	Data       []byte           `json:"data,omitempty"`
+	Dispatch   *DispatchOptions `json:"dispatch,omitempty"`
+	Requester  string           `json:"requester,omitempty"`
+	NoCache    bool             `json:"noCache,omitempty"`
+	KeepSealed bool             `json:"keepSealed,omitempty"` // return the result sealed, in a SealedResult, rather than decrypted
+	Quorum     *QuorumOptions   `json:"quorum,omitempty"`
+}
+
+// DispatchOptions controls how the requesting node spreads a work request over remote workers.
+// FanOut is the number of workers that are sent the request at once, and HedgeDelayMs is the time
+// to wait for an answer before sending the request to one more worker. Zero values fall back to
+// the node's worker configuration.
+type DispatchOptions struct {
+	FanOut       int   `json:"fanOut,omitempty"`
+	HedgeDelayMs int64 `json:"hedgeDelayMs,omitempty"`
+}
+
+// QuorumOptions asks for a request to be executed by Workers distinct workers, returning data only
+// if at least Agree of them returned the same result. Agree defaults to a simple majority.
+type QuorumOptions struct {
+	Workers int `json:"workers"`
+	Agree   int `json:"agree,omitempty"`
+}
+
+// QuorumResult describes the outcome of a quorum request. ResultHash is the hash of the normalised
+// result the agreeing peers returned.
+type QuorumResult struct {
+	Workers          int      `json:"workers"`
+	Agree            int      `json:"agree"`
+	Agreed           int      `json:"agreed"`
+	ResultHash       string   `json:"resultHash,omitempty"`
+	AgreeingPeers    []string `json:"agreeingPeers,omitempty"`
+	DisagreeingPeers []string `json:"disagreeingPeers,omitempty"`
+	FailedPeers      []string `json:"failedPeers,omitempty"`
 }
 
 type WorkResponse struct {
-	WorkRequest  *WorkRequest `json:"workRequest,omitempty"`
-	Data         interface{}  `json:"data,omitempty"`
-	Error        string       `json:"error,omitempty"`
-	WorkerPeerId string       `json:"workerPeerId,omitempty"`
+	WorkRequest  *WorkRequest          `json:"workRequest,omitempty"`
+	Data         interface{}           `json:"data,omitempty"`
+	Error        string                `json:"error,omitempty"`
+	ErrorCode    ErrorCode             `json:"errorCode,omitempty"` // classifies Error, see Code
+	Retryable    bool                  `json:"retryable,omitempty"` // whether the request may succeed if it is sent again
+	WorkerPeerId string                `json:"workerPeerId,omitempty"`
+	CacheStatus  string                `json:"cacheStatus,omitempty"`
+	Quorum       *QuorumResult         `json:"quorum,omitempty"`
+	Busy         bool                  `json:"busy,omitempty"`         // the worker had no capacity left and did not execute the request, kept for older peers
+	RetryAfterMs int64                 `json:"retryAfterMs,omitempty"` // how long to wait before retrying, for busy workers and rate limits
+	CrashId      string                `json:"crashId,omitempty"`      // identifies the crash log entry of a handler that panicked
+	Unsealed     bool                  `json:"unsealed,omitempty"`     // Data is plain JSON from a job executor without a TEE, rather than sealed
+	Attestation  *tee.Attestation      `json:"attestation,omitempty"`  // proves Data was produced by an enclave for WorkRequest, attached by the worker
+	Verification tee.AttestationStatus `json:"verification,omitempty"` // the outcome of verifying Attestation, set by the requester
 }
 
-func (wr *WorkResponse) UnsealDataIfNeeded() (err error) {
-	unsealData := os.Getenv("KEEP_SEALED_DATA") != "true"
-	if !unsealData {
+// UnsealDataIfNeeded decodes the data of a successful response for the client of the request. Sealed data
+// is decrypted through the TEE worker, or, if the request asks to keep it sealed, wrapped into a
+// SealedResult signed with the key of this node. Data of job executors without a TEE is plain JSON.
+func (wr *WorkResponse) UnsealDataIfNeeded(workRequest WorkRequest, issuer crypto.PrivKey) (err error) {
+	v, ok := wr.Data.(string)
+	if !ok {
 		return
 	}
-
-	switch v := wr.Data.(type) {
-	case string:
-		client := tee.NewClient()
-		var resData string
-		resData, err = client.Decrypt(v)
-		if err == nil {
-			wr.Data, err = utils.BytesToMap([]byte(resData))
+	switch {
+	case wr.Unsealed:
+		var data interface{}
+		if err = json.Unmarshal([]byte(v), &data); err == nil {
+			wr.Data = data
+		}
+	case workRequest.KeepSealed:
+		sealed := NewSealedResult(workRequest, *wr, v)
+		if err = sealed.Sign(issuer); err == nil {
+			wr.Data = sealed
+		}
+	default:
+		var data interface{}
+		if data, err = unseal(v); err == nil {
+			wr.Data = data
 		}
 	}
-
 	return
 }
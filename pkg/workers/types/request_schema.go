@@ -0,0 +1,316 @@
+package data_types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The fields of a request type are described with a `schema` struct tag holding a comma separated list of
+// rules, which drive both the validation of the request and its published JSON Schema:
+//
+//	required      the field must be set
+//	default=V     value given to the field when it is not set
+//	min=N, max=N  bounds of an integer field
+//	format=F      format of a string field, "uri" for absolute http(s) URLs
+//
+// Integer fields are not set when zero, so bounds are only checked for explicit values and defaults.
+const schemaTag = "schema"
+
+// FieldError describes why a field of a work request is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError is returned when the data of a work request does not match the schema of its WorkerType.
+// It lists every invalid field.
+type ValidationError struct {
+	WorkType WorkerType   `json:"workType"`
+	Fields   []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return fmt.Sprintf("invalid %s request: %s", e.WorkType, strings.Join(messages, "; "))
+}
+
+// RequestData is implemented by the typed request of a WorkerType. Check is called once the fields
+// passed the schema rules, to validate constraints between fields.
+type RequestData interface {
+	Check() []FieldError
+}
+
+// ErrUnknownRequestType is returned for work types that have no request schema.
+var ErrUnknownRequestType = errors.New("work type has no request schema")
+
+// DecodeRequestData decodes data into request, applying the defaults and validating it against the schema
+// of workType. The returned error is a *ValidationError if data does not match the schema.
+func DecodeRequestData(workType WorkerType, data []byte, request RequestData) error {
+	fields := decodeStrict(data, request)
+	if fields == nil {
+		value := reflect.ValueOf(request).Elem()
+		fields = validateFields(value)
+		if len(fields) == 0 {
+			fields = request.Check()
+		}
+	}
+	if len(fields) > 0 {
+		return &ValidationError{WorkType: workType, Fields: fields}
+	}
+	return nil
+}
+
+// NormalizeRequestData validates the data of a work request of the given type and returns it
+// re-encoded with the defaults applied, so equivalent requests are sent in the same form.
+func NormalizeRequestData(workType WorkerType, data []byte) ([]byte, error) {
+	request, ok := NewRequestData(workType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRequestType, workType)
+	}
+	if err := DecodeRequestData(workType, data, request); err != nil {
+		return nil, err
+	}
+	return json.Marshal(request)
+}
+
+// decodeStrict decodes data into v, reporting unknown fields and fields of the wrong type as field errors.
+func decodeStrict(data []byte, v interface{}) []FieldError {
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err == nil {
+		if _, extra := decoder.Token(); extra != io.EOF {
+			return []FieldError{{Field: "(body)", Message: "must contain a single JSON object"}}
+		}
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return []FieldError{{Field: typeErr.Field, Message: "must be of type " + jsonType(typeErr.Type)}}
+	case errors.As(err, &typeErr):
+		return []FieldError{{Field: "(body)", Message: "must be a JSON object"}}
+	case errors.As(err, &timeErr):
+		return []FieldError{{Field: "(body)", Message: "contains a date that is not in RFC 3339 format"}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field, _ := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		return []FieldError{{Field: field, Message: "is not a known field"}}
+	default:
+		return []FieldError{{Field: "(body)", Message: "is not valid JSON: " + err.Error()}}
+	}
+}
+
+// schemaRules holds the parsed rules of a schema tag.
+type schemaRules struct {
+	required bool
+	def      string
+	min, max *int64
+	format   string
+}
+
+func parseSchemaRules(tag string) schemaRules {
+	var rules schemaRules
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "required":
+			rules.required = true
+		case "default":
+			rules.def = value
+		case "min", "max":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				panic(fmt.Sprintf("invalid schema rule %q", rule))
+			}
+			if key == "min" {
+				rules.min = &n
+			} else {
+				rules.max = &n
+			}
+		case "format":
+			rules.format = value
+		case "":
+		default:
+			panic(fmt.Sprintf("unknown schema rule %q", rule))
+		}
+	}
+	return rules
+}
+
+// jsonFieldName returns the JSON name of a struct field, or "" if it is not encoded.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// validateFields applies the defaults to the fields of the request struct v and checks their rules.
+func validateFields(v reflect.Value) []FieldError {
+	var fields []FieldError
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+		rules := parseSchemaRules(field.Tag.Get(schemaTag))
+		value := v.Field(i)
+
+		if value.IsZero() && rules.def != "" {
+			if err := setDefault(value, rules.def); err != nil {
+				panic(fmt.Sprintf("invalid default for %s: %v", name, err))
+			}
+		}
+		if value.IsZero() {
+			if rules.required {
+				fields = append(fields, FieldError{Field: name, Message: "is required"})
+			}
+			continue
+		}
+
+		switch value.Kind() {
+		case reflect.Int, reflect.Int64:
+			if rules.min != nil && value.Int() < *rules.min {
+				fields = append(fields, FieldError{Field: name, Message: fmt.Sprintf("must be at least %d", *rules.min)})
+			}
+			if rules.max != nil && value.Int() > *rules.max {
+				fields = append(fields, FieldError{Field: name, Message: fmt.Sprintf("must be at most %d", *rules.max)})
+			}
+		case reflect.String:
+			if rules.format == "uri" && !isHTTPURL(value.String()) {
+				fields = append(fields, FieldError{Field: name, Message: "must be an absolute http or https URL"})
+			}
+		}
+	}
+	return fields
+}
+
+func setDefault(value reflect.Value, def string) error {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(def, 10, 64)
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.String:
+		value.SetString(def)
+	default:
+		return fmt.Errorf("defaults are not supported for %s fields", value.Kind())
+	}
+	return nil
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonType returns the JSON Schema type of values of the Go type t.
+func jsonType(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType, t.Kind() == reflect.String:
+		return "string"
+	case t.Kind() == reflect.Int, t.Kind() == reflect.Int64:
+		return "integer"
+	case t.Kind() == reflect.Struct, t.Kind() == reflect.Map:
+		return "object"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// JSONSchema is the subset of JSON Schema used to describe work requests.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Minimum              *int64                 `json:"minimum,omitempty"`
+	Maximum              *int64                 `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+}
+
+// RequestJSONSchema returns the JSON Schema of the request data of workType.
+func RequestJSONSchema(workType WorkerType) (*JSONSchema, bool) {
+	request, ok := NewRequestData(workType)
+	if !ok {
+		return nil, false
+	}
+	additional := false
+	schema := &JSONSchema{
+		Schema:               "https://json-schema.org/draft/2020-12/schema",
+		Title:                string(workType),
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: &additional,
+	}
+
+	t := reflect.TypeOf(request).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+		rules := parseSchemaRules(field.Tag.Get(schemaTag))
+		property := &JSONSchema{
+			Type:    jsonType(field.Type),
+			Format:  rules.format,
+			Minimum: rules.min,
+			Maximum: rules.max,
+		}
+		if field.Type == timeType || field.Type == reflect.PointerTo(timeType) {
+			property.Format = "date-time"
+		}
+		if rules.def != "" {
+			property.Default = rules.def
+			if property.Type == "integer" {
+				property.Default, _ = strconv.ParseInt(rules.def, 10, 64)
+			}
+		}
+		if rules.required {
+			schema.Required = append(schema.Required, name)
+			if property.Type == "string" {
+				minLength := 1
+				property.MinLength = &minLength
+			}
+		}
+		schema.Properties[name] = property
+	}
+	return schema, true
+}
@@ -0,0 +1,108 @@
+package data_types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fieldErrors(t *testing.T, err error) []FieldError {
+	t.Helper()
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "expected a validation error, got %v", err)
+	return validationErr.Fields
+}
+
+func TestNormalizeRequestData(t *testing.T) {
+	t.Run("applies defaults", func(t *testing.T) {
+		data, err := NormalizeRequestData(Web, []byte(`{"url":"https://masa.ai"}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"url":"https://masa.ai","depth":1}`, string(data))
+
+		data, err = NormalizeRequestData(TwitterFollowers, []byte(`{"username":"getmasafi"}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"username":"getmasafi","count":20}`, string(data))
+	})
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		_, err := NormalizeRequestData(Web, []byte(`{"url":"masa.ai","depth":10}`))
+		assert.ElementsMatch(t, []FieldError{
+			{Field: "url", Message: "must be an absolute http or https URL"},
+			{Field: "depth", Message: "must be at most 3"},
+		}, fieldErrors(t, err))
+		assert.Contains(t, err.Error(), "invalid web request")
+	})
+
+	t.Run("rejects missing required fields", func(t *testing.T) {
+		_, err := NormalizeRequestData(Twitter, []byte(`{"count":5}`))
+		assert.Equal(t, []FieldError{{Field: "query", Message: "is required"}}, fieldErrors(t, err))
+
+		_, err = NormalizeRequestData(Twitter, nil)
+		assert.Equal(t, []FieldError{{Field: "query", Message: "is required"}}, fieldErrors(t, err))
+	})
+
+	t.Run("rejects malformed bodies instead of panicking", func(t *testing.T) {
+		_, err := NormalizeRequestData(Twitter, []byte(`{"query":"masa","count":"ten"}`))
+		assert.Equal(t, []FieldError{{Field: "count", Message: "must be of type integer"}}, fieldErrors(t, err))
+
+		_, err = NormalizeRequestData(Twitter, []byte(`{"query":"masa","extra":true}`))
+		assert.Equal(t, []FieldError{{Field: "extra", Message: "is not a known field"}}, fieldErrors(t, err))
+
+		_, err = NormalizeRequestData(Twitter, []byte(`["masa"]`))
+		assert.Equal(t, "(body)", fieldErrors(t, err)[0].Field)
+
+		_, err = NormalizeRequestData(Twitter, []byte(`{"query":"masa"} {}`))
+		assert.Equal(t, "(body)", fieldErrors(t, err)[0].Field)
+	})
+
+	t.Run("checks constraints between fields", func(t *testing.T) {
+		_, err := NormalizeRequestData(TelegramChannelMessages, []byte(`{"username":"masa","since":"2024-02-01T00:00:00Z","until":"2024-01-01T00:00:00Z"}`))
+		assert.Equal(t, []FieldError{{Field: "until", Message: "must not be before since"}}, fieldErrors(t, err))
+	})
+
+	t.Run("rejects work types without a schema", func(t *testing.T) {
+		_, err := NormalizeRequestData(Test, []byte(`{}`))
+		assert.ErrorIs(t, err, ErrUnknownRequestType)
+	})
+}
+
+func TestRequestJSONSchema(t *testing.T) {
+	t.Run("every request type has a valid schema", func(t *testing.T) {
+		for _, workType := range RequestWorkerTypes() {
+			schema, ok := RequestJSONSchema(workType)
+			require.True(t, ok, workType)
+			assert.Equal(t, "object", schema.Type)
+			assert.Equal(t, string(workType), schema.Title)
+		}
+	})
+
+	t.Run("describes rules, defaults and formats", func(t *testing.T) {
+		schema, ok := RequestJSONSchema(Web)
+		require.True(t, ok)
+		data, err := json.Marshal(schema)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "web",
+			"type": "object",
+			"properties": {
+				"url": {"type": "string", "format": "uri", "minLength": 1},
+				"depth": {"type": "integer", "default": 1, "minimum": 1, "maximum": 3}
+			},
+			"required": ["url"],
+			"additionalProperties": false
+		}`, string(data))
+
+		schema, _ = RequestJSONSchema(TelegramChannelMessages)
+		assert.Equal(t, "date-time", schema.Properties["since"].Format)
+		assert.Equal(t, "integer", schema.Properties["offsetID"].Type)
+	})
+
+	t.Run("unknown work types have no schema", func(t *testing.T) {
+		_, ok := RequestJSONSchema(Discord)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,113 @@
+package data_types
+
+import (
+	"sort"
+	"time"
+)
+
+// The request types bound what can be asked of a worker, for example the number of tweets or the crawl
+// depth, so that workers are not sent requests they cannot serve in time. See the schema tag rules in
+// request_schema.go.
+
+// TwitterQueryRequest is the request data of Twitter.
+type TwitterQueryRequest struct {
+	Query string `json:"query" schema:"required"`
+	Count int    `json:"count" schema:"default=10,min=1,max=1000"`
+}
+
+// TwitterFollowersRequest is the request data of TwitterFollowers.
+type TwitterFollowersRequest struct {
+	Username string `json:"username" schema:"required"`
+	Count    int    `json:"count" schema:"default=20,min=1,max=1000"`
+}
+
+// TwitterProfileRequest is the request data of TwitterProfile.
+type TwitterProfileRequest struct {
+	Username string `json:"username" schema:"required"`
+}
+
+// WebRequest is the request data of Web. Depth is the number of links followed from URL.
+type WebRequest struct {
+	URL   string `json:"url" schema:"required,format=uri"`
+	Depth int    `json:"depth" schema:"default=1,min=1,max=3"`
+}
+
+// DiscordProfileRequest is the request data of DiscordProfile.
+type DiscordProfileRequest struct {
+	UserID string `json:"userID" schema:"required"`
+}
+
+// DiscordChannelMessagesRequest is the request data of DiscordChannelMessages.
+// Limit and Before are optional, Before pages back from the given message ID.
+type DiscordChannelMessagesRequest struct {
+	ChannelID string `json:"channelID" schema:"required"`
+	Limit     int    `json:"limit,omitempty" schema:"min=1,max=100"`
+	Before    string `json:"before,omitempty"`
+}
+
+// DiscordGuildChannelsRequest is the request data of DiscordGuildChannels.
+type DiscordGuildChannelsRequest struct {
+	GuildID string `json:"guildID" schema:"required"`
+}
+
+// DiscordUserGuildsRequest is the request data of DiscordUserGuilds, which takes no arguments.
+type DiscordUserGuildsRequest struct{}
+
+// TelegramChannelMessagesRequest is the request data of TelegramChannelMessages.
+// OffsetID pages back from the given message ID, Since and Until restrict the messages to a date range.
+// All fields except Username are optional.
+type TelegramChannelMessagesRequest struct {
+	Username string     `json:"username" schema:"required"`
+	Limit    int        `json:"limit,omitempty" schema:"min=1,max=1000"`
+	OffsetID int64      `json:"offsetID,omitempty" schema:"min=1"`
+	Since    *time.Time `json:"since,omitempty"`
+	Until    *time.Time `json:"until,omitempty"`
+}
+
+func (r *TwitterQueryRequest) Check() []FieldError           { return nil }
+func (r *TwitterFollowersRequest) Check() []FieldError       { return nil }
+func (r *TwitterProfileRequest) Check() []FieldError         { return nil }
+func (r *WebRequest) Check() []FieldError                    { return nil }
+func (r *DiscordProfileRequest) Check() []FieldError         { return nil }
+func (r *DiscordChannelMessagesRequest) Check() []FieldError { return nil }
+func (r *DiscordGuildChannelsRequest) Check() []FieldError   { return nil }
+func (r *DiscordUserGuildsRequest) Check() []FieldError      { return nil }
+
+func (r *TelegramChannelMessagesRequest) Check() []FieldError {
+	if r.Since != nil && r.Until != nil && r.Since.After(*r.Until) {
+		return []FieldError{{Field: "until", Message: "must not be before since"}}
+	}
+	return nil
+}
+
+// requestTypes maps each WorkerType that can be requested to a constructor of its request data.
+var requestTypes = map[WorkerType]func() RequestData{
+	Twitter:                 func() RequestData { return &TwitterQueryRequest{} },
+	TwitterFollowers:        func() RequestData { return &TwitterFollowersRequest{} },
+	TwitterProfile:          func() RequestData { return &TwitterProfileRequest{} },
+	Web:                     func() RequestData { return &WebRequest{} },
+	DiscordProfile:          func() RequestData { return &DiscordProfileRequest{} },
+	DiscordChannelMessages:  func() RequestData { return &DiscordChannelMessagesRequest{} },
+	DiscordGuildChannels:    func() RequestData { return &DiscordGuildChannelsRequest{} },
+	DiscordUserGuilds:       func() RequestData { return &DiscordUserGuildsRequest{} },
+	TelegramChannelMessages: func() RequestData { return &TelegramChannelMessagesRequest{} },
+}
+
+// NewRequestData returns an empty request of the given WorkerType, or false if it cannot be requested.
+func NewRequestData(workType WorkerType) (RequestData, bool) {
+	newRequest, ok := requestTypes[workType]
+	if !ok {
+		return nil, false
+	}
+	return newRequest(), true
+}
+
+// RequestWorkerTypes returns the WorkerTypes that can be requested, in alphabetical order.
+func RequestWorkerTypes() []WorkerType {
+	types := make([]WorkerType, 0, len(requestTypes))
+	for workType := range requestTypes {
+		types = append(types, workType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
@@ -0,0 +1,137 @@
+package data_types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/masa-finance/masa-oracle/pkg/tee"
+	"github.com/masa-finance/masa-oracle/pkg/utils"
+)
+
+// SealedResultVersion is the version of the SealedResult format.
+const SealedResultVersion = 1
+
+// How the TEE worker seals results: ego's AES-GCM sealing with the product key of the enclave, which is
+// shared by the enclaves of the same signer and product.
+const (
+	SealAlgorithmEgoAESGCM = "ego-aes-gcm"
+	SealKeyProduct         = "product"
+)
+
+// SealedResult wraps a result that was kept sealed at the request of the client, so that it can be
+// forwarded to another enclave or unsealed later. It is signed by the node that issued it, which only
+// unseals results it issued itself, and only for the requester named in it.
+type SealedResult struct {
+	Version      int        `json:"version"`
+	Algorithm    string     `json:"algorithm"`
+	KeyID        string     `json:"keyId"`
+	WorkType     WorkerType `json:"workType"`
+	RequestId    string     `json:"requestId,omitempty"`
+	WorkerPeerId string     `json:"workerPeerId,omitempty"`
+	Requester    string     `json:"requester,omitempty"` // the client allowed to unseal the result
+	SealedAt     time.Time  `json:"sealedAt"`
+	Data         string     `json:"data"`   // the sealed result, as returned by the TEE worker
+	Issuer       string     `json:"issuer"` // peer ID of the node that issued the envelope
+	Signature    []byte     `json:"signature,omitempty"`
+}
+
+// ErrUnsupportedSeal is returned for sealed results of another version, algorithm or key than this node
+// can unseal.
+var ErrUnsupportedSeal = errors.New("unsupported sealed result")
+
+// AnonymousRequesterPrefix marks the requesters of API clients without an API token. Their sealed results do
+// not name a requester, so that they cannot be unsealed through the node, only through the TEE worker.
+const AnonymousRequesterPrefix = "anonymous/"
+
+// KeepSealedByDefault reports whether results are kept sealed for requests that do not say, as set by
+// KEEP_SEALED_DATA.
+func KeepSealedByDefault() bool {
+	return os.Getenv("KEEP_SEALED_DATA") == "true"
+}
+
+// NewSealedResult returns the envelope of the sealed data of a response to the request.
+func NewSealedResult(workRequest WorkRequest, response WorkResponse, data string) *SealedResult {
+	requester := workRequest.Requester
+	if strings.HasPrefix(requester, AnonymousRequesterPrefix) {
+		requester = ""
+	}
+	return &SealedResult{
+		Version:      SealedResultVersion,
+		Algorithm:    SealAlgorithmEgoAESGCM,
+		KeyID:        SealKeyProduct,
+		WorkType:     workRequest.WorkType,
+		RequestId:    workRequest.RequestId,
+		WorkerPeerId: response.WorkerPeerId,
+		Requester:    requester,
+		SealedAt:     time.Now().UTC(),
+		Data:         data,
+	}
+}
+
+// signedBytes returns the encoding of the envelope covered by its signature.
+func (s SealedResult) signedBytes() ([]byte, error) {
+	s.Signature = nil
+	return json.Marshal(s)
+}
+
+// Sign signs the envelope with the private key of the issuing node.
+func (s *SealedResult) Sign(key crypto.PrivKey) error {
+	if key == nil {
+		return errors.New("no key to sign the sealed result with")
+	}
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	s.Issuer = id.String()
+	payload, err := s.signedBytes()
+	if err != nil {
+		return err
+	}
+	s.Signature, err = key.Sign(payload)
+	return err
+}
+
+// Verify checks that the envelope was issued and signed by the node with the given key, and has not been
+// altered since.
+func (s *SealedResult) Verify(key crypto.PubKey) error {
+	id, err := peer.IDFromPublicKey(key)
+	if err != nil {
+		return err
+	}
+	if s.Issuer != id.String() {
+		return fmt.Errorf("the sealed result was issued by %s", s.Issuer)
+	}
+	payload, err := s.signedBytes()
+	if err != nil {
+		return err
+	}
+	if ok, err := key.Verify(payload, s.Signature); err != nil || !ok {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// Unseal decrypts the result through the TEE worker.
+func (s *SealedResult) Unseal() (interface{}, error) {
+	if s.Version != SealedResultVersion || s.Algorithm != SealAlgorithmEgoAESGCM || s.KeyID != SealKeyProduct {
+		return nil, fmt.Errorf("%w: version %d with %s and key %s", ErrUnsupportedSeal, s.Version, s.Algorithm, s.KeyID)
+	}
+	return unseal(s.Data)
+}
+
+// unseal decrypts a result sealed by the TEE worker.
+func unseal(data string) (interface{}, error) {
+	plain, err := tee.NewClient().Decrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	return utils.BytesToMap([]byte(plain))
+}
@@ -0,0 +1,89 @@
+package data_types
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTEE decrypts results by stripping their "sealed:" prefix.
+func fakeTEE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			EncryptedResult string `json:"encrypted_result"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = w.Write([]byte(req.EncryptedResult[len("sealed:"):]))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("TEE_WORKER_URL", server.URL)
+}
+
+func TestSealedResult(t *testing.T) {
+	fakeTEE(t)
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	request := WorkRequest{WorkType: Web, RequestId: "req-1", Requester: "client", KeepSealed: true}
+
+	response := WorkResponse{Data: `sealed:{"pages":[]}`, WorkerPeerId: "worker"}
+	require.NoError(t, response.UnsealDataIfNeeded(request, key))
+	sealed, ok := response.Data.(*SealedResult)
+	require.True(t, ok)
+	assert.Equal(t, `sealed:{"pages":[]}`, sealed.Data)
+	assert.Equal(t, "worker", sealed.WorkerPeerId)
+	assert.Equal(t, "client", sealed.Requester)
+	assert.Equal(t, SealAlgorithmEgoAESGCM, sealed.Algorithm)
+
+	// The envelope survives the trip through the client
+	encoded, err := json.Marshal(sealed)
+	require.NoError(t, err)
+	var received SealedResult
+	require.NoError(t, json.Unmarshal(encoded, &received))
+	require.NoError(t, received.Verify(key.GetPublic()))
+	data, err := received.Unseal()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"pages": []interface{}{}}, data)
+
+	// Changes to the envelope break the signature
+	altered := received
+	altered.Requester = "someone else"
+	assert.Error(t, altered.Verify(key.GetPublic()))
+
+	otherKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	assert.Error(t, received.Verify(otherKey.GetPublic()))
+
+	// Results of clients without an API token name no requester
+	anonymous := WorkRequest{WorkType: Web, RequestId: "req-2", Requester: AnonymousRequesterPrefix + "0123", KeepSealed: true}
+	response = WorkResponse{Data: `sealed:{"pages":[]}`, WorkerPeerId: "worker"}
+	require.NoError(t, response.UnsealDataIfNeeded(anonymous, key))
+	assert.Empty(t, response.Data.(*SealedResult).Requester)
+
+	unsupported := received
+	unsupported.Version = 2
+	_, err = unsupported.Unseal()
+	assert.ErrorIs(t, err, ErrUnsupportedSeal)
+}
+
+func TestUnsealDataIfNeeded(t *testing.T) {
+	fakeTEE(t)
+
+	response := WorkResponse{Data: `sealed:{"a":1}`}
+	require.NoError(t, response.UnsealDataIfNeeded(WorkRequest{}, nil))
+	assert.Equal(t, map[string]interface{}{"a": float64(1)}, response.Data)
+
+	// Results of executors without a TEE cannot be kept sealed
+	response = WorkResponse{Data: `{"a":1}`, Unsealed: true}
+	require.NoError(t, response.UnsealDataIfNeeded(WorkRequest{KeepSealed: true}, nil))
+	assert.Equal(t, map[string]interface{}{"a": float64(1)}, response.Data)
+
+	// Sealed results need a key to sign their envelope
+	response = WorkResponse{Data: `sealed:{"a":1}`}
+	assert.Error(t, response.UnsealDataIfNeeded(WorkRequest{KeepSealed: true}, nil))
+}
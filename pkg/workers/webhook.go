@@ -0,0 +1,126 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errWebhookAddress is returned for webhooks to addresses of the node's own network.
+var errWebhookAddress = errors.New("webhooks cannot be sent to loopback, private or link-local addresses")
+
+// sharedAddressSpace is the carrier-grade NAT range, where some clouds run their metadata services.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// WebhookAllowlist holds the hosts and networks that the webhook sinks of schedules may be sent to even
+// though they are internal. Other webhooks may only be sent to public addresses, so that API clients cannot
+// make the node call the services of its own network, such as cloud metadata services.
+type WebhookAllowlist struct {
+	hosts    map[string]bool
+	networks []*net.IPNet
+}
+
+// ParseWebhookAllowlist parses a comma-separated list of host names, IP addresses and CIDR networks,
+// e.g. "hooks.internal,10.1.2.3,192.168.0.0/24".
+func ParseWebhookAllowlist(spec string) (WebhookAllowlist, error) {
+	allowlist := WebhookAllowlist{hosts: make(map[string]bool)}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return WebhookAllowlist{}, fmt.Errorf("invalid network %q: %w", entry, err)
+			}
+			allowlist.networks = append(allowlist.networks, network)
+			continue
+		}
+		allowlist.hosts[entry] = true
+	}
+	return allowlist, nil
+}
+
+// allowsIP reports whether webhooks may be sent to the address.
+func (a WebhookAllowlist) allowsIP(ip net.IP) bool {
+	if a.hosts[ip.String()] {
+		return true
+	}
+	for _, network := range a.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	internal := ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || sharedAddressSpace.Contains(ip)
+	return !internal
+}
+
+// checkURL checks that the webhook URL is an http or https URL of an allowed host. Host names are checked
+// against the addresses they resolve to now, and again on every delivery, see client.
+func (a WebhookAllowlist) checkURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("webhook sink URL %q must be an http or https URL", target)
+	}
+	host := strings.ToLower(u.Hostname())
+	if a.hosts[host] {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if !a.allowsIP(ip) {
+			return fmt.Errorf("webhook sink URL %q: %w", target, errWebhookAddress)
+		}
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("webhook sink URL %q: %w", target, errWebhookAddress)
+	}
+	// Hosts that do not resolve yet are checked once they do
+	ips, _ := net.LookupIP(host)
+	for _, ip := range ips {
+		if !a.allowsIP(ip) {
+			return fmt.Errorf("webhook sink URL %q resolves to %s: %w", target, ip, errWebhookAddress)
+		}
+	}
+	return nil
+}
+
+// client returns the HTTP client that delivers webhooks. It refuses to connect to addresses that are not
+// allowed whatever the host name resolves to when the webhook is sent, and after redirects, and does not
+// use the proxy of the environment.
+func (a WebhookAllowlist) client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	guarded := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !a.allowsIP(ip) {
+				return fmt.Errorf("connecting to %s: %w", host, errWebhookAddress)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				if host, _, err := net.SplitHostPort(address); err == nil && a.hosts[strings.ToLower(host)] {
+					return dialer.DialContext(ctx, network, address)
+				}
+				return guarded.DialContext(ctx, network, address)
+			},
+			TLSHandshakeTimeout: timeout,
+		},
+	}
+}
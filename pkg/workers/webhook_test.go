@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookAllowlist(t *testing.T) {
+	t.Run("only public addresses are allowed by default", func(t *testing.T) {
+		allowlist, err := ParseWebhookAllowlist("")
+		require.NoError(t, err)
+		for _, ip := range []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.100.100.200", "fd00:ec2::254", "fe80::1", "0.0.0.0", "::ffff:127.0.0.1"} {
+			assert.False(t, allowlist.allowsIP(net.ParseIP(ip)), ip)
+		}
+		for _, ip := range []string{"8.8.8.8", "104.16.0.1", "2606:4700::1"} {
+			assert.True(t, allowlist.allowsIP(net.ParseIP(ip)), ip)
+		}
+	})
+
+	t.Run("listed hosts and networks are allowed", func(t *testing.T) {
+		allowlist, err := ParseWebhookAllowlist(" Hooks.Internal, 10.1.2.3,192.168.0.0/24,")
+		require.NoError(t, err)
+		assert.True(t, allowlist.allowsIP(net.ParseIP("10.1.2.3")))
+		assert.True(t, allowlist.allowsIP(net.ParseIP("192.168.0.42")))
+		assert.False(t, allowlist.allowsIP(net.ParseIP("192.168.1.42")))
+		assert.NoError(t, allowlist.checkURL("https://hooks.internal/masa"))
+		assert.NoError(t, allowlist.checkURL("http://10.1.2.3:8080/masa"))
+
+		_, err = ParseWebhookAllowlist("10.0.0.0/33")
+		assert.Error(t, err)
+	})
+
+	t.Run("webhook URLs of internal hosts are refused", func(t *testing.T) {
+		allowlist, err := ParseWebhookAllowlist("")
+		require.NoError(t, err)
+		for _, target := range []string{"http://127.0.0.1:8080/", "http://[::1]/", "http://169.254.169.254/latest/meta-data/", "http://localhost/", "http://api.localhost/", "http://10.0.0.1/"} {
+			assert.ErrorIs(t, allowlist.checkURL(target), errWebhookAddress, target)
+		}
+		assert.Error(t, allowlist.checkURL("ftp://example.com/"))
+		assert.Error(t, allowlist.checkURL("http:///path"))
+	})
+
+	t.Run("deliveries to internal addresses are refused when connecting", func(t *testing.T) {
+		// Host names are resolved again when connecting, so one that resolved to a public address when the
+		// schedule was created cannot be pointed at the node's network later
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		refused, err := ParseWebhookAllowlist("")
+		require.NoError(t, err)
+		_, err = refused.client(time.Second).Post(server.URL, "application/json", strings.NewReader("{}"))
+		assert.ErrorIs(t, err, errWebhookAddress)
+
+		allowed, err := ParseWebhookAllowlist("127.0.0.1")
+		require.NoError(t, err)
+		response, err := allowed.client(time.Second).Post(server.URL, "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+}
@@ -0,0 +1,343 @@
+// Package wire implements the framing used to exchange work requests and responses between nodes.
+//
+// Every message is sent as a frame made of a fixed-size header followed by the encoded payload:
+//
+//	magic "MASA" (4) | version (1) | message type (1) | codec (1) | compression (1) | accepted compressions (1) | reserved (1) | payload length (4)
+//
+// The codec identifies how the payload is encoded, the compression how it was compressed after
+// encoding, and the accepted compressions which compressions the sender is able to read in an answer.
+// Peers that predate the framing exchange a 4-byte big-endian length followed by JSON, which is
+// still supported through ReadLegacy and WriteLegacy.
+package wire
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ugorji/go/codec"
+)
+
+// Version is the version of the framing written by this package.
+const Version uint8 = 1
+
+// HeaderSize is the size in bytes of a frame header.
+const HeaderSize = 14
+
+var magic = [4]byte{'M', 'A', 'S', 'A'}
+
+// MessageType identifies what a frame carries.
+type MessageType uint8
+
+const (
+	MessageRequest MessageType = iota + 1
+	MessageResponse
+	// MessageCancel is sent by the requester on the stream of a request it no longer wants an answer to.
+	MessageCancel
+)
+
+// Codec identifies how a payload is encoded.
+type Codec uint8
+
+const (
+	CodecJSON Codec = iota + 1
+	CodecCBOR
+)
+
+// String returns the name of the codec.
+func (c Codec) String() string {
+	switch c {
+	case CodecJSON:
+		return "json"
+	case CodecCBOR:
+		return "cbor"
+	default:
+		return fmt.Sprintf("codec(%d)", uint8(c))
+	}
+}
+
+// ParseCodec returns the codec with the given name.
+func ParseCodec(name string) (Codec, error) {
+	switch name {
+	case "json":
+		return CodecJSON, nil
+	case "cbor":
+		return CodecCBOR, nil
+	default:
+		return 0, fmt.Errorf("unknown wire codec %q", name)
+	}
+}
+
+// Compression identifies how a payload is compressed.
+type Compression uint8
+
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionZstd
+)
+
+// CompressionSet is a set of compressions, as advertised by a peer.
+type CompressionSet uint8
+
+// SupportedCompressions are the compressions this package can read.
+var SupportedCompressions = NewCompressionSet(CompressionGzip, CompressionZstd)
+
+// NewCompressionSet returns the set of the given compressions.
+func NewCompressionSet(compressions ...Compression) CompressionSet {
+	var set CompressionSet
+	for _, c := range compressions {
+		set |= 1 << c
+	}
+	return set
+}
+
+// Has reports whether the set contains the compression.
+func (s CompressionSet) Has(c Compression) bool {
+	return c == CompressionNone || s&(1<<c) != 0
+}
+
+// Negotiate returns the preferred compression that both this package and a peer accepting the set support.
+func (s CompressionSet) Negotiate() Compression {
+	for _, c := range []Compression{CompressionZstd, CompressionGzip} {
+		if s.Has(c) && SupportedCompressions.Has(c) {
+			return c
+		}
+	}
+	return CompressionNone
+}
+
+var (
+	// ErrNotFramed is returned when a message does not start with the frame magic.
+	ErrNotFramed = errors.New("message is not framed")
+	// ErrMessageTooLarge is returned when a message exceeds the size limit.
+	ErrMessageTooLarge = errors.New("message too large")
+)
+
+// Header is the header of a frame.
+type Header struct {
+	Version     uint8
+	Type        MessageType
+	Codec       Codec
+	Compression Compression
+	Accept      CompressionSet
+	Length      uint32
+}
+
+// Frame is a received message whose payload has not been decoded yet.
+type Frame struct {
+	Header
+	payload []byte
+}
+
+// Decode decodes the payload of the frame into v.
+func (f *Frame) Decode(v interface{}) error {
+	return Unmarshal(f.Codec, f.payload, v)
+}
+
+// WriteOptions controls how a message is written.
+type WriteOptions struct {
+	Codec Codec
+	// Compression is used for payloads of at least CompressThreshold bytes.
+	Compression       Compression
+	CompressThreshold int
+	// Accept advertises the compressions the sender can read in an answer.
+	Accept  CompressionSet
+	MaxSize int
+}
+
+// WriteFrame encodes v and writes it as a single frame of the given type.
+func WriteFrame(w io.Writer, msgType MessageType, v interface{}, opts WriteOptions) error {
+	payload, err := Marshal(opts.Codec, v)
+	if err != nil {
+		return err
+	}
+
+	compression := CompressionNone
+	if opts.Compression != CompressionNone && len(payload) >= opts.CompressThreshold {
+		compressed, err := compress(opts.Compression, payload)
+		if err != nil {
+			return err
+		}
+		if len(compressed) < len(payload) {
+			payload, compression = compressed, opts.Compression
+		}
+	}
+	if opts.MaxSize > 0 && len(payload) > opts.MaxSize {
+		return fmt.Errorf("%w: %d bytes, the limit is %d", ErrMessageTooLarge, len(payload), opts.MaxSize)
+	}
+
+	header := make([]byte, HeaderSize, HeaderSize+len(payload))
+	copy(header, magic[:])
+	header[4] = Version
+	header[5] = byte(msgType)
+	header[6] = byte(opts.Codec)
+	header[7] = byte(compression)
+	header[8] = byte(opts.Accept)
+	binary.BigEndian.PutUint32(header[10:], uint32(len(payload)))
+	_, err = w.Write(append(header, payload...))
+	return err
+}
+
+// ReadFrame reads a single frame, refusing payloads larger than maxSize bytes before and after decompression.
+func ReadFrame(r io.Reader, maxSize int) (*Frame, error) {
+	buf := make([]byte, HeaderSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("error reading frame header: %w", err)
+	}
+	if !bytes.Equal(buf[:4], magic[:]) {
+		return nil, ErrNotFramed
+	}
+
+	frame := &Frame{Header: Header{
+		Version:     buf[4],
+		Type:        MessageType(buf[5]),
+		Codec:       Codec(buf[6]),
+		Compression: Compression(buf[7]),
+		Accept:      CompressionSet(buf[8]),
+		Length:      binary.BigEndian.Uint32(buf[10:]),
+	}}
+	if frame.Version == 0 || frame.Version > Version {
+		return nil, fmt.Errorf("unsupported frame version %d", frame.Version)
+	}
+	if maxSize > 0 && int64(frame.Length) > int64(maxSize) {
+		return nil, fmt.Errorf("%w: %d bytes, the limit is %d", ErrMessageTooLarge, frame.Length, maxSize)
+	}
+
+	payload := make([]byte, frame.Length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, fmt.Errorf("error reading frame payload: %w", err)
+	}
+	payload, err := decompress(frame.Compression, payload, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	frame.payload = payload
+	return frame, nil
+}
+
+// WriteLegacy writes v as JSON prefixed with its 4-byte big-endian length, as understood by older peers.
+func WriteLegacy(w io.Writer, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint32(buf, uint32(len(payload)))
+	_, err = w.Write(append(buf, payload...))
+	return err
+}
+
+// ReadLegacy reads a length-prefixed JSON message written by WriteLegacy into v.
+func ReadLegacy(r io.Reader, maxSize int, v interface{}) error {
+	lengthBuf := make([]byte, 4)
+	if _, err := io.ReadFull(r, lengthBuf); err != nil {
+		return fmt.Errorf("error reading message length: %w", err)
+	}
+	length := binary.BigEndian.Uint32(lengthBuf)
+	if maxSize > 0 && int64(length) > int64(maxSize) {
+		return fmt.Errorf("%w: %d bytes, the limit is %d", ErrMessageTooLarge, length, maxSize)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return fmt.Errorf("error reading message: %w", err)
+	}
+	return json.Unmarshal(payload, v)
+}
+
+// cborHandle decodes maps into map[string]interface{} so decoded values can be re-encoded as JSON.
+var cborHandle = func() *codec.CborHandle {
+	h := &codec.CborHandle{}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return h
+}()
+
+// Marshal encodes v with the given codec.
+func Marshal(c Codec, v interface{}) ([]byte, error) {
+	switch c {
+	case CodecJSON:
+		return json.Marshal(v)
+	case CodecCBOR:
+		var out []byte
+		err := codec.NewEncoderBytes(&out, cborHandle).Encode(v)
+		return out, err
+	default:
+		return nil, fmt.Errorf("unsupported codec %d", c)
+	}
+}
+
+// Unmarshal decodes data encoded with the given codec into v.
+func Unmarshal(c Codec, data []byte, v interface{}) error {
+	switch c {
+	case CodecJSON:
+		return json.Unmarshal(data, v)
+	case CodecCBOR:
+		return codec.NewDecoderBytes(data, cborHandle).Decode(v)
+	default:
+		return fmt.Errorf("unsupported codec %d", c)
+	}
+}
+
+func compress(c Compression, data []byte) ([]byte, error) {
+	switch c {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %d", c)
+	}
+}
+
+func decompress(c Compression, data []byte, maxSize int) ([]byte, error) {
+	var r io.Reader
+	switch c {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	case CompressionZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unsupported compression %d", c)
+	}
+
+	if maxSize > 0 {
+		r = io.LimitReader(r, int64(maxSize)+1)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing payload: %w", err)
+	}
+	if maxSize > 0 && len(out) > maxSize {
+		return nil, fmt.Errorf("%w: more than %d bytes after decompression", ErrMessageTooLarge, maxSize)
+	}
+	return out, nil
+}
+
+// zstdEncoder is shared, EncodeAll is safe for concurrent use.
+var zstdEncoder, _ = zstd.NewWriter(nil)
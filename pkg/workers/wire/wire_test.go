@@ -0,0 +1,107 @@
+package wire
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMessage struct {
+	WorkType string      `json:"workType,omitempty"`
+	Data     []byte      `json:"data,omitempty"`
+	Result   interface{} `json:"result,omitempty"`
+	Empty    string      `json:"empty,omitempty"`
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	message := testMessage{
+		WorkType: "web",
+		Data:     []byte(`{"url":"https://masa.ai"}`),
+		Result:   map[string]interface{}{"title": "Masa", "pages": []interface{}{"a", "b"}},
+	}
+
+	for _, codec := range []Codec{CodecJSON, CodecCBOR} {
+		for _, compression := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+			var buf bytes.Buffer
+			err := WriteFrame(&buf, MessageResponse, message, WriteOptions{Codec: codec, Compression: compression, Accept: SupportedCompressions})
+			require.NoError(t, err)
+
+			frame, err := ReadFrame(&buf, 0)
+			require.NoError(t, err)
+			assert.Equal(t, Version, frame.Version)
+			assert.Equal(t, MessageResponse, frame.Type)
+			assert.Equal(t, codec, frame.Codec)
+			assert.Equal(t, SupportedCompressions, frame.Accept)
+
+			var decoded testMessage
+			require.NoError(t, frame.Decode(&decoded))
+			assert.Equal(t, message.WorkType, decoded.WorkType)
+			assert.Equal(t, message.Data, decoded.Data)
+			result, ok := decoded.Result.(map[string]interface{})
+			require.True(t, ok, "%s decodes maps with string keys", codec)
+			assert.Equal(t, "Masa", result["title"])
+		}
+	}
+}
+
+func TestCompressionIsOnlyUsedForLargePayloads(t *testing.T) {
+	large := testMessage{WorkType: strings.Repeat("masa ", 10000)}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteFrame(&buf, MessageResponse, large, WriteOptions{Codec: CodecJSON, Compression: CompressionZstd, CompressThreshold: 1024}))
+	frame, err := ReadFrame(bytes.NewReader(buf.Bytes()), 0)
+	require.NoError(t, err)
+	assert.Equal(t, CompressionZstd, frame.Compression)
+	assert.Less(t, int(frame.Length), 1024)
+
+	buf.Reset()
+	require.NoError(t, WriteFrame(&buf, MessageResponse, testMessage{WorkType: "web"}, WriteOptions{Codec: CodecJSON, Compression: CompressionZstd, CompressThreshold: 1024}))
+	frame, err = ReadFrame(&buf, 0)
+	require.NoError(t, err)
+	assert.Equal(t, CompressionNone, frame.Compression)
+}
+
+func TestSizeLimits(t *testing.T) {
+	large := testMessage{WorkType: strings.Repeat("masa ", 10000)}
+
+	var buf bytes.Buffer
+	err := WriteFrame(&buf, MessageRequest, large, WriteOptions{Codec: CodecJSON, MaxSize: 1024})
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+
+	buf.Reset()
+	require.NoError(t, WriteFrame(&buf, MessageRequest, large, WriteOptions{Codec: CodecJSON}))
+	_, err = ReadFrame(&buf, 1024)
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+
+	// The limit also applies to the decompressed payload
+	buf.Reset()
+	require.NoError(t, WriteFrame(&buf, MessageRequest, large, WriteOptions{Codec: CodecJSON, Compression: CompressionGzip}))
+	_, err = ReadFrame(&buf, 1024)
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+
+	buf.Reset()
+	require.NoError(t, WriteLegacy(&buf, large))
+	var decoded testMessage
+	assert.ErrorIs(t, ReadLegacy(&buf, 1024, &decoded), ErrMessageTooLarge)
+}
+
+func TestLegacyFraming(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteLegacy(&buf, testMessage{WorkType: "twitter"}))
+
+	_, err := ReadFrame(bytes.NewReader(buf.Bytes()), 0)
+	assert.ErrorIs(t, err, ErrNotFramed)
+
+	var decoded testMessage
+	require.NoError(t, ReadLegacy(&buf, 0, &decoded))
+	assert.Equal(t, "twitter", decoded.WorkType)
+}
+
+func TestNegotiate(t *testing.T) {
+	assert.Equal(t, CompressionZstd, SupportedCompressions.Negotiate())
+	assert.Equal(t, CompressionGzip, NewCompressionSet(CompressionGzip).Negotiate())
+	assert.Equal(t, CompressionNone, CompressionSet(0).Negotiate())
+}
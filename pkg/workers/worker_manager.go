@@ -2,22 +2,21 @@ package workers
 
 import (
 	"context"
-	"encoding/binary"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"math/rand"
-	"strings"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/dgraph-io/badger"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/sirupsen/logrus"
 
 	"github.com/masa-finance/masa-oracle/node"
 	"github.com/masa-finance/masa-oracle/pkg/event"
+	"github.com/masa-finance/masa-oracle/pkg/metrics"
 	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	"github.com/masa-finance/masa-oracle/pkg/tee"
 	"github.com/masa-finance/masa-oracle/pkg/workers/handlers"
 	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
 )
@@ -29,27 +28,95 @@ func NewWorkHandlerManager(opts ...WorkerOptionFunc) *WorkHandlerManager {
 	whm := &WorkHandlerManager{
 		handlers:     make(map[data_types.WorkerType]*WorkHandlerInfo),
 		eventTracker: event.NewEventTracker(nil),
+		selectors:    make(map[pubsub.WorkerCategory]WorkerSelector),
+		peerLoad:     newPeerLoad(),
+		resultCache:  NewResultCache(workerConfig.ResultCacheTTL, workerConfig.ResultCacheSize),
+		limiters:     make(map[data_types.WorkerType]*workLimiter),
+		busyPeers:    newBusyPeers(),
+		crashes:      newCrashLog(workerConfig.CrashLogSize),
+		breakers:     newPeerBreakers(workerConfig.BreakerThreshold, workerConfig.BreakerOpenTimeout),
+		masaDir:      options.masaDir,
 	}
 
+	concurrency, err := ParseWorkerConcurrency(options.workerConcurrency)
+	if err != nil {
+		logrus.Errorf("[-] Invalid worker concurrency %q, using the default limit: %v", options.workerConcurrency, err)
+	}
+	whm.concurrency = concurrency
+
+	selection, err := ParseWorkerSelection(options.workerSelection)
+	if err != nil {
+		logrus.Errorf("[-] Invalid worker selection %q, using the default strategies: %v", options.workerSelection, err)
+		selection = nil
+	}
+	for category, name := range defaultSelectors {
+		if configured, ok := selection[category]; ok {
+			name = configured
+		}
+		selector, _ := newWorkerSelector(name, whm.peerLoad.get)
+		whm.SetWorkerSelector(category, selector)
+		logrus.Infof("[+] Using %s worker selection for %s work", name, category)
+	}
+
+	required, err := ParseRequiredAttestation(options.requiredAttestation)
+	if err != nil {
+		logrus.Errorf("[-] Invalid required attestation %q, not requiring attestations: %v", options.requiredAttestation, err)
+	}
+	whm.requireAttestation = required
+	whm.attestationPolicy = newAttestationPolicy(options)
+	whm.attestor = options.attestor
+
+	whm.openResultStores(options)
+
+	whm.webhookAllowlist, err = ParseWebhookAllowlist(options.webhookAllowlist)
+	if err != nil {
+		logrus.Errorf("[-] Invalid webhook allowlist %q, only allowing public webhooks: %v", options.webhookAllowlist, err)
+	}
+
+	executors := newJobExecutors(options.jobExecutors, options.jobFixturesDir)
+
 	if options.isTwitterWorker {
-		whm.addWorkHandler(data_types.Twitter, &handlers.TwitterQueryHandler{MasaDir: options.masaDir})
-		whm.addWorkHandler(data_types.TwitterFollowers, &handlers.TwitterFollowersHandler{MasaDir: options.masaDir})
-		whm.addWorkHandler(data_types.TwitterProfile, &handlers.TwitterProfileHandler{MasaDir: options.masaDir})
+		whm.addJobHandler(data_types.Twitter, &handlers.TwitterQueryHandler{MasaDir: options.masaDir, Executor: executors[data_types.Twitter]}, executors)
+		whm.addJobHandler(data_types.TwitterFollowers, &handlers.TwitterFollowersHandler{MasaDir: options.masaDir, Executor: executors[data_types.TwitterFollowers]}, executors)
+		whm.addJobHandler(data_types.TwitterProfile, &handlers.TwitterProfileHandler{MasaDir: options.masaDir, Executor: executors[data_types.TwitterProfile]}, executors)
+	}
+
+	if options.isDiscordScraperWorker {
+		whm.addJobHandler(data_types.DiscordProfile, &handlers.DiscordProfileHandler{Executor: executors[data_types.DiscordProfile]}, executors)
+		whm.addJobHandler(data_types.DiscordChannelMessages, &handlers.DiscordChannelMessagesHandler{Executor: executors[data_types.DiscordChannelMessages]}, executors)
+		whm.addJobHandler(data_types.DiscordGuildChannels, &handlers.DiscordGuildChannelsHandler{Executor: executors[data_types.DiscordGuildChannels]}, executors)
+		whm.addJobHandler(data_types.DiscordUserGuilds, &handlers.DiscordUserGuildsHandler{Executor: executors[data_types.DiscordUserGuilds]}, executors)
+	}
+
+	if options.isTelegramScraperWorker {
+		whm.addJobHandler(data_types.TelegramChannelMessages, &handlers.TelegramChannelMessagesHandler{Executor: executors[data_types.TelegramChannelMessages]}, executors)
 	}
 
 	if options.isWebScraperWorker {
-		whm.addWorkHandler(data_types.Web, &handlers.WebHandler{})
+		whm.addJobHandler(data_types.Web, &handlers.WebHandler{Executor: executors[data_types.Web]}, executors)
 	}
 
 	return whm
 }
 
+// addJobHandler adds the handler of a work type if its job executor can run the handler's jobs. Handlers
+// whose jobs no executor can run are left out, so the node does not advertise work it would always fail.
+func (whm *WorkHandlerManager) addJobHandler(wType data_types.WorkerType, handler WorkHandler, executors map[data_types.WorkerType]tee.JobExecutor) {
+	if !handlers.SupportedBy(executors[wType], wType) {
+		logrus.Warnf("[-] Not handling %s work: its job executor cannot run %s jobs", wType, handlers.JobType(wType))
+		return
+	}
+	whm.addWorkHandler(wType, handler)
+}
+
 // ErrHandlerNotFound is an error returned when a work handler cannot be found.
 var ErrHandlerNotFound = errors.New("work handler not found")
 
 // WorkHandler defines the interface for handling different types of work.
 type WorkHandler interface {
-	HandleWork(data []byte) data_types.WorkResponse
+	// HandleWork executes the work described by data. Handlers should stop working and return
+	// as soon as ctx is done, as the result is no longer wanted.
+	HandleWork(ctx context.Context, data []byte) data_types.WorkResponse
 }
 
 // WorkHandlerInfo contains information about a work handler, including metrics.
@@ -57,6 +124,8 @@ type WorkHandlerInfo struct {
 	Handler      WorkHandler
 	CallCount    int64
 	TotalRuntime time.Duration
+	CrashCount   int64
+	Disabled     bool // set after repeated crashes until an operator re-enables the handler
 }
 
 // WorkHandlerManager manages work handlers and tracks their execution metrics.
@@ -64,6 +133,45 @@ type WorkHandlerManager struct {
 	handlers     map[data_types.WorkerType]*WorkHandlerInfo
 	mu           sync.RWMutex
 	eventTracker *event.EventTracker
+	selectors    map[pubsub.WorkerCategory]WorkerSelector
+	peerLoad     *peerLoad
+	resultCache  *ResultCache
+	concurrency  map[data_types.WorkerType]int
+	limiters     map[data_types.WorkerType]*workLimiter
+	busyPeers    *busyPeers
+	crashes      *crashLog
+	breakers     *peerBreakers
+
+	masaDir string
+	dbOnce  sync.Once
+	db      *badger.DB
+	dbErr   error
+
+	results            *ResultStore
+	spool              *ResultStore
+	webhookAllowlist   WebhookAllowlist
+	attestor           tee.AttestationProvider
+	attestationPolicy  tee.AttestationPolicy
+	requireAttestation map[data_types.WorkerType]bool
+
+	capabilityListener func(category pubsub.WorkerCategory, available bool)
+}
+
+// SetWorkerSelector registers the strategy used to choose workers for the given category.
+func (whm *WorkHandlerManager) SetWorkerSelector(category pubsub.WorkerCategory, selector WorkerSelector) {
+	whm.mu.Lock()
+	defer whm.mu.Unlock()
+	whm.selectors[category] = selector
+}
+
+// getWorkerSelector returns the strategy registered for the given category, or a random selector if there is none.
+func (whm *WorkHandlerManager) getWorkerSelector(category pubsub.WorkerCategory) WorkerSelector {
+	whm.mu.RLock()
+	defer whm.mu.RUnlock()
+	if selector, ok := whm.selectors[category]; ok {
+		return selector
+	}
+	return &RandomSelector{}
 }
 
 // addWorkHandler registers a new work handler under a specific name.
@@ -74,98 +182,103 @@ func (whm *WorkHandlerManager) addWorkHandler(wType data_types.WorkerType, handl
 }
 
 // getWorkHandler retrieves a registered work handler by name.
-func (whm *WorkHandlerManager) getWorkHandler(wType data_types.WorkerType) (WorkHandler, bool) {
+func (whm *WorkHandlerManager) getWorkHandler(wType data_types.WorkerType) (*WorkHandlerInfo, bool) {
 	whm.mu.RLock()
 	defer whm.mu.RUnlock()
 	info, exists := whm.handlers[wType]
-	if !exists {
-		return nil, false
-	}
-	return info.Handler, true
+	return info, exists
 }
 
-func (whm *WorkHandlerManager) DistributeWork(node *node.OracleNode, workRequest data_types.WorkRequest) (response data_types.WorkResponse) {
-	category := data_types.WorkerTypeToCategory(workRequest.WorkType)
-	var remoteWorkers []data_types.Worker
-	var localWorker *data_types.Worker
+// DistributeWork sends the work request to the eligible remote workers, falling back to
+// local execution if all of them fail, and returns the first successful response.
+// Responses are served from the result cache when possible, see ResultCache.
+// Once ctx is done the workers still busy with the request are told to stop working on it.
+func (whm *WorkHandlerManager) DistributeWork(ctx context.Context, node *node.OracleNode, workRequest data_types.WorkRequest) (response data_types.WorkResponse) {
+	return whm.distributeCachedWork(ctx, node, workRequest, nil)
+}
 
-	if category == pubsub.CategoryTwitter {
-		// Use priority-based selection for Twitter work
-		remoteWorkers, localWorker = GetEligibleWorkers(node, category, workerConfig.MaxRemoteWorkers)
-		logrus.Info("Starting priority-based worker selection for Twitter work")
-	} else {
-		// Use existing selection for other work types
-		remoteWorkers, localWorker = GetEligibleWorkers(node, category, 0)
-		// Shuffle the workers to maintain round-robin behavior
-		rand.Shuffle(len(remoteWorkers), func(i, j int) {
-			remoteWorkers[i], remoteWorkers[j] = remoteWorkers[j], remoteWorkers[i]
-		})
-		logrus.Info("Starting round-robin worker selection for non-Twitter work")
-	}
+// distributeCachedWork calls distributeWork through the result cache.
+func (whm *WorkHandlerManager) distributeCachedWork(ctx context.Context, node *node.OracleNode, workRequest data_types.WorkRequest, onRunning func(peerId string)) data_types.WorkResponse {
+	started := time.Now()
+	response := whm.resultCache.Do(ctx, workRequest, func(ctx context.Context) data_types.WorkResponse {
+		response := whm.distributeWithRetries(ctx, node, workRequest, onRunning)
+		whm.storeResult(workRequest, response)
+		return response
+	})
+	observeRequest(workRequest, response, started)
+	return response
+}
 
-	remoteWorkersAttempted := 0
-	var errorList []string
+// Results returns the result store, or nil if it is not enabled.
+func (whm *WorkHandlerManager) Results() *ResultStore {
+	return whm.results
+}
 
-	// Try remote workers first, up to MaxRemoteWorkers
-	for _, worker := range remoteWorkers {
-		if remoteWorkersAttempted >= workerConfig.MaxRemoteWorkers {
-			logrus.Infof("Reached maximum remote workers (%d), stopping remote worker attempts", workerConfig.MaxRemoteWorkers)
-			break
-		}
-		remoteWorkersAttempted++
+// ExportSpool returns the export spool, or nil if it is not enabled.
+func (whm *WorkHandlerManager) ExportSpool() *ResultStore {
+	return whm.spool
+}
 
-		// Attempt to connect to the worker
-		ctx, cancel := context.WithTimeout(context.Background(), workerConfig.FindPeerTimeout)
-		peerInfo, err := node.DHT.FindPeer(ctx, worker.NodeData.PeerId)
-		cancel()
-		if err != nil {
-			if err == context.DeadlineExceeded {
-				logrus.Warnf("Timeout while finding peer %s in DHT", worker.NodeData.PeerId.String())
-			} else {
-				logrus.Warnf("Failed to find peer %s in DHT: %v", worker.NodeData.PeerId.String(), err)
-			}
-			if category == pubsub.CategoryTwitter {
-				err := node.NodeTracker.UpdateNodeDataTwitter(worker.NodeData.PeerId.String(), pubsub.NodeData{
-					LastNotFoundTime: time.Now(),
-					NotFoundCount:    1,
-				})
-				if err != nil {
-					logrus.Warnf("Failed to update node data for peer %s: %v", worker.NodeData.PeerId.String(), err)
-				}
-			}
-			continue
-		}
+// ResultCacheStats returns the hit and miss counters of the result cache.
+func (whm *WorkHandlerManager) ResultCacheStats() CacheStats {
+	return whm.resultCache.Stats()
+}
 
-		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), workerConfig.ConnectionTimeout)
-		err = node.Host.Connect(ctxWithTimeout, peerInfo)
-		cancel()
-		if err != nil {
-			logrus.Warnf("Failed to connect to peer %s: %v", worker.NodeData.PeerId.String(), err)
-			continue
-		}
+// distributeWork implements DistributeWork. If onRunning is not nil it is called with the
+// peer ID of each worker the request is handed over to.
+func (whm *WorkHandlerManager) distributeWork(ctx context.Context, node *node.OracleNode, workRequest data_types.WorkRequest, onRunning func(peerId string)) (response data_types.WorkResponse) {
+	category := data_types.WorkerTypeToCategory(workRequest.WorkType)
+	remoteWorkers, localWorker := GetEligibleWorkers(node, whm.getWorkerSelector(category), whm.breakers.available, workRequest, workerConfig.MaxRemoteWorkers)
 
-		worker.AddrInfo = &peerInfo
+	if len(remoteWorkers) > workerConfig.MaxRemoteWorkers {
+		logrus.Infof("Limiting remote worker attempts to the maximum of %d", workerConfig.MaxRemoteWorkers)
+		remoteWorkers = remoteWorkers[:workerConfig.MaxRemoteWorkers]
+	}
+	remoteWorkers = whm.busyPeers.deprioritize(remoteWorkers)
+	if len(remoteWorkers) == 0 && localWorker == nil && !offersWorkType(node.NodeTracker.GetAllNodeData(), workRequest.WorkType) {
+		return unsupportedResponse(workRequest.WorkType)
+	}
 
-		logrus.Infof("Attempting remote worker %s (attempt %d/%d)", worker.NodeData.PeerId, remoteWorkersAttempted, workerConfig.MaxRemoteWorkers)
-		response = whm.sendWorkToWorker(node, worker, workRequest)
-		if response.Error != "" {
-			errorMsg := fmt.Sprintf("Worker %s: %s", worker.NodeData.PeerId, response.Error)
-			errorList = append(errorList, errorMsg)
+	if workRequest.Quorum != nil {
+		return whm.distributeQuorum(ctx, node, remoteWorkers, localWorker, workRequest, onRunning)
+	}
 
-			whm.eventTracker.TrackWorkerFailure(workRequest.WorkType, response.Error, worker.AddrInfo.ID.String())
-			logrus.Errorf("error sending work to worker: %s: %s", response.WorkerPeerId, response.Error)
+	var failures workFailures
+	fanOut, hedgeDelay := dispatchSettings(workRequest)
+	if fanOut > 1 || hedgeDelay > 0 {
+		// Race several remote workers against each other and keep the first success
+		var done bool
+		response, failures, done = whm.dispatchConcurrently(ctx, node, remoteWorkers, workRequest, fanOut, hedgeDelay, onRunning)
+		if done {
+			return response
+		}
+	} else {
+		// Try remote workers one after another
+		for i, worker := range remoteWorkers {
+			logrus.Infof("Attempting remote worker %s (attempt %d/%d)", worker.NodeData.PeerId, i+1, len(remoteWorkers))
+			response = whm.tryRemoteWorker(ctx, node, worker, workRequest, onRunning)
+			if response.Error == "" || ctx.Err() != nil {
+				return response
+			}
+			code := response.Code()
+			if !code.Retryable() {
+				// Another worker would fail the same way
+				logrus.Infof("Remote worker %s failed with %s, not trying other workers", worker.NodeData.PeerId, code)
+				return response
+			}
+			failures.add(fmt.Sprintf("Worker %s", worker.NodeData.PeerId), response)
 			logrus.Infof("Remote worker %s failed, moving to next worker", worker.NodeData.PeerId)
 
-			// Check if the error is related to Twitter authentication
-			if strings.Contains(response.Error, "unable to get twitter profile: there was an error authenticating with your Twitter credentials") {
-				logrus.Warnf("Worker %s failed due to Twitter authentication error. Skipping to the next worker.", worker.NodeData.PeerId)
-				continue
+			if code == data_types.ErrorAuthFailed {
+				logrus.Warnf("Worker %s failed due to an authentication error. Skipping to the next worker.", worker.NodeData.PeerId)
 			}
-		} else {
-			return response
 		}
 	}
 
+	if ctx.Err() != nil {
+		return cancelledResponse()
+	}
+
 	// Fallback to local execution if local worker is eligible and all remote workers failed
 	if localWorker != nil {
 		var reason string
@@ -176,39 +289,38 @@ func (whm *WorkHandlerManager) DistributeWork(node *node.OracleNode, workRequest
 		}
 		whm.eventTracker.TrackLocalWorkerFallback(workRequest.WorkType, reason, localWorker.AddrInfo.ID.String())
 
-		response = whm.ExecuteWork(workRequest)
+		if onRunning != nil {
+			onRunning(localWorker.AddrInfo.ID.String())
+		}
+		started := time.Now()
+		response = whm.verifyAttestation(workRequest, whm.executeWorkLimited(ctx, workRequest))
+		observeExecution(workRequest, metrics.ExecutionLocal, response, started)
 		whm.eventTracker.TrackWorkCompletion(workRequest.WorkType, response.Error == "", localWorker.AddrInfo.ID.String())
 
-		if response.Error != "" {
-			errorList = append(errorList, fmt.Sprintf("Local worker: %s", response.Error))
-		} else {
+		if response.Error == "" || !response.Code().Retryable() {
 			return response
 		}
+		failures.add("Local worker", response)
 	}
 
 	// If we reach here, all attempts failed
-	if len(errorList) == 0 {
-		response.Error = "no eligible workers found"
-	} else {
-		response.Error = fmt.Sprintf("All workers failed. Errors: %s", strings.Join(errorList, "; "))
-	}
-	return response
+	return failures.response(whm.busyPeers.nextFree)
 }
 
-func (whm *WorkHandlerManager) sendWorkToWorker(node *node.OracleNode, worker data_types.Worker, workRequest data_types.WorkRequest) (response data_types.WorkResponse) {
-	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), workerConfig.WorkerResponseTimeout)
+func (whm *WorkHandlerManager) sendWorkToWorker(ctx context.Context, node *node.OracleNode, worker data_types.Worker, workRequest data_types.WorkRequest, onRunning func(peerId string)) (response data_types.WorkResponse) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, workerConfig.WorkerResponseTimeout)
 	defer cancel() // Cancel the context when done to release resources
 
 	if err := node.Host.Connect(ctxWithTimeout, *worker.AddrInfo); err != nil {
-		response.Error = fmt.Sprintf("failed to connect to remote peer %s: %v", worker.AddrInfo.ID.String(), err)
+		response = data_types.NewErrorResponse(data_types.ErrorUpstreamUnavailable, "failed to connect to remote peer %s: %v", worker.AddrInfo.ID.String(), err)
 		whm.eventTracker.TrackWorkerFailure(workRequest.WorkType, response.Error, worker.AddrInfo.ID.String())
 		return
 	} else {
 		//whm.eventTracker.TrackRemoteWorkerConnection(worker.AddrInfo.ID.String())
 		logrus.Debugf("[+] Connection established with node: %s", worker.AddrInfo.ID.String())
-		stream, err := node.ProtocolStream(ctxWithTimeout, worker.AddrInfo.ID, node.Options.WorkerProtocol)
+		stream, err := openWorkerStream(ctxWithTimeout, node, worker.AddrInfo.ID)
 		if err != nil {
-			response.Error = fmt.Sprintf("error opening stream: %v", err)
+			response = data_types.NewErrorResponse(data_types.ErrorUpstreamUnavailable, "error opening stream: %v", err)
 			whm.eventTracker.TrackWorkerFailure(workRequest.WorkType, response.Error, worker.AddrInfo.ID.String())
 			return
 		}
@@ -219,80 +331,110 @@ func (whm *WorkHandlerManager) sendWorkToWorker(node *node.OracleNode, worker da
 				logrus.Debugf("[-] Error closing stream: %s", err)
 			}
 		}(stream) // Close the stream when done
+		framed := node.Options.WorkerEnvelopeProtocol != "" && stream.Protocol() == node.ProtocolID(node.Options.WorkerEnvelopeProtocol)
+		var writeMu sync.Mutex
+		// Once the request times out or is cancelled, tell the worker to stop and abort any pending read or write
+		stopReset := context.AfterFunc(ctxWithTimeout, func() {
+			if framed {
+				writeMu.Lock()
+				_ = stream.SetWriteDeadline(time.Now().Add(cancelWriteTimeout))
+				if err := writeCancel(stream, workRequest.RequestId); err != nil {
+					logrus.Debugf("[-] Error sending cancel to %s: %v", worker.AddrInfo.ID.String(), err)
+				}
+				writeMu.Unlock()
+			}
+			_ = stream.Reset()
+		})
+		defer stopReset()
 
-		// Write the request to the stream with length prefix
-		bytes, err := json.Marshal(workRequest)
-		if err != nil {
-			response.Error = fmt.Sprintf("error marshaling work request: %v", err)
-			return
-		}
-		lengthBuf := make([]byte, 4)
-		binary.BigEndian.PutUint32(lengthBuf, uint32(len(bytes)))
-		_, err = stream.Write(lengthBuf)
+		// Write the request to the stream
+		writeMu.Lock()
+		_ = stream.SetWriteDeadline(time.Now().Add(workerConfig.WireWriteTimeout))
+		err = writeWorkRequest(stream, framed, workRequest)
+		writeMu.Unlock()
 		if err != nil {
-			response.Error = fmt.Sprintf("error writing length to stream: %v", err)
-			return
-		}
-		_, err = stream.Write(bytes)
-		if err != nil {
-			response.Error = fmt.Sprintf("error writing to stream: %v", err)
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return cancelledResponse()
+			}
+			response = data_types.NewErrorResponse(data_types.ErrorUpstreamUnavailable, "error writing to stream: %v", err)
 			whm.eventTracker.TrackWorkerFailure(workRequest.WorkType, response.Error, worker.AddrInfo.ID.String())
 			return
 		}
 		whm.eventTracker.TrackWorkDistribution(workRequest.WorkType, true, worker.AddrInfo.ID.String())
-		// Read the response length
-		lengthBuf = make([]byte, 4)
-		_, err = io.ReadFull(stream, lengthBuf)
-		if err != nil {
-			response.Error = fmt.Sprintf("error reading response length: %v", err)
-			whm.eventTracker.TrackWorkerFailure(workRequest.WorkType, response.Error, worker.AddrInfo.ID.String())
-			return
+		if onRunning != nil {
+			onRunning(worker.AddrInfo.ID.String())
 		}
-		responseLength := binary.BigEndian.Uint32(lengthBuf)
 
-		// Read the actual response
-		responseBuf := make([]byte, responseLength)
-		_, err = io.ReadFull(stream, responseBuf)
-		if err != nil {
-			response.Error = fmt.Sprintf("error reading response: %v", err)
-			return
+		// Read the response
+		sentAt := time.Now()
+		if deadline, ok := ctxWithTimeout.Deadline(); ok {
+			_ = stream.SetReadDeadline(deadline)
 		}
-		err = json.Unmarshal(responseBuf, &response)
+		response, err = readWorkResponse(stream, framed)
 		if err != nil {
-			response.Error = fmt.Sprintf("error unmarshaling response: %v", err)
-			return
-		}
-		// Update metrics only if the work category is Twitter
-		if data_types.WorkerTypeToCategory(workRequest.WorkType) == pubsub.CategoryTwitter {
-			if response.Error == "" {
-				err = node.NodeTracker.UpdateNodeDataTwitter(worker.NodeData.PeerId.String(), pubsub.NodeData{
-					LastReturnedTweet: time.Now(),
-				})
-			} else {
-				err = node.NodeTracker.UpdateNodeDataTwitter(worker.NodeData.PeerId.String(), pubsub.NodeData{
-					TweetTimeout:     true,
-					TweetTimeouts:    1,
-					LastTweetTimeout: time.Now(),
-				})
+			if errors.Is(ctx.Err(), context.Canceled) {
+				// The request was abandoned, so this worker is not at fault
+				return cancelledResponse()
 			}
-			if err != nil {
-				logrus.Warnf("Failed to update node data for peer %s: %v", worker.NodeData.PeerId.String(), err)
+			code := data_types.ErrorUpstreamUnavailable
+			if errors.Is(ctxWithTimeout.Err(), context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+				code = data_types.ErrorTimeout
 			}
+			response = data_types.NewErrorResponse(code, "error reading response: %v", err)
+			whm.eventTracker.TrackWorkerFailure(workRequest.WorkType, response.Error, worker.AddrInfo.ID.String())
+			recordReliability(node, worker, workRequest, response, time.Since(sentAt))
+			return
 		}
+		if response.Unsealed && !workerConfig.AcceptUnsealedResults {
+			// Only results sealed by a TEE can be trusted to come from the data source
+			response = data_types.NewErrorResponse(data_types.ErrorInternal, "worker returned a result that is not sealed by a TEE")
+		}
+		response = whm.verifyAttestation(workRequest, response)
+		recordReliability(node, worker, workRequest, response, time.Since(sentAt))
 	}
 	return response
 }
 
+// recordReliability updates the reliability of the remote worker for the category of the work request with
+// its response. Failures that are not the worker's fault, such as being busy or asked for data that does not
+// exist, are not counted.
+func recordReliability(node *node.OracleNode, worker data_types.Worker, workRequest data_types.WorkRequest, response data_types.WorkResponse, latency time.Duration) {
+	code := response.Code()
+	outcome := pubsub.OutcomeSuccess
+	switch {
+	case code == "":
+	case code == data_types.ErrorTimeout:
+		outcome = pubsub.OutcomeTimeout
+	case code.WorkerFault():
+		outcome = pubsub.OutcomeFailure
+	default:
+		return
+	}
+	category := data_types.WorkerTypeToCategory(workRequest.WorkType)
+	if err := node.NodeTracker.RecordWorkOutcome(worker.NodeData.PeerId.String(), category, outcome, latency); err != nil {
+		logrus.Warnf("Failed to update node data for peer %s: %v", worker.NodeData.PeerId.String(), err)
+	}
+}
+
 // ExecuteWork finds and executes the work handler associated with the given name.
 // It tracks the call count and execution duration for the handler.
-func (whm *WorkHandlerManager) ExecuteWork(workRequest data_types.WorkRequest) (response data_types.WorkResponse) {
-	handler, exists := whm.getWorkHandler(workRequest.WorkType)
+// The handler is given a context that is cancelled when ctx is done or the execution times out.
+// A panic in the handler is recovered and reported as an internal error, see handleWork.
+func (whm *WorkHandlerManager) ExecuteWork(ctx context.Context, workRequest data_types.WorkRequest) (response data_types.WorkResponse) {
+	handlerInfo, exists := whm.getWorkHandler(workRequest.WorkType)
 	if !exists {
-		return data_types.WorkResponse{Error: ErrHandlerNotFound.Error()}
+		return data_types.NewErrorResponse(data_types.ErrorInternal, "%v", ErrHandlerNotFound)
+	}
+	whm.mu.RLock()
+	disabled := handlerInfo.Disabled
+	whm.mu.RUnlock()
+	if disabled {
+		return data_types.NewErrorResponse(data_types.ErrorInternal, "%v", ErrHandlerDisabled)
 	}
 
-	// Create a context with a 30-second timeout
-	ctx, cancel := context.WithTimeout(context.Background(), workerConfig.WorkerResponseTimeout)
+	// Limit the execution time, the handler is told to stop once it passes
+	parent := ctx
+	ctx, cancel := context.WithTimeout(parent, workerConfig.WorkerResponseTimeout)
 	defer cancel()
 
 	// Channel to receive the work response
@@ -301,13 +443,16 @@ func (whm *WorkHandlerManager) ExecuteWork(workRequest data_types.WorkRequest) (
 	// Execute the work in a separate goroutine
 	go func() {
 		startTime := time.Now()
-		workResponse := handler.HandleWork(workRequest.Data)
+		workResponse := whm.handleWork(handlerInfo, workRequest, func() data_types.WorkResponse {
+			return handlerInfo.Handler.HandleWork(ctx, workRequest.Data)
+		})
 		duration := time.Since(startTime)
 		whm.mu.Lock()
-		handlerInfo := whm.handlers[workRequest.WorkType]
 		handlerInfo.CallCount++
 		handlerInfo.TotalRuntime += duration
 		whm.mu.Unlock()
+		observeHandler(workRequest, workResponse, duration)
+		whm.attest(workRequest, &workResponse)
 
 		if workResponse.Error != "" {
 			logrus.Errorf("[-] Work error for %s: %s", workRequest.WorkType, workResponse.Error)
@@ -320,15 +465,29 @@ func (whm *WorkHandlerManager) ExecuteWork(workRequest data_types.WorkRequest) (
 
 	select {
 	case <-ctx.Done():
+		if parent.Err() != nil {
+			// The requester is no longer interested in the result
+			return cancelledResponse()
+		}
 		// Context timed out
-		return data_types.WorkResponse{Error: "work execution timed out"}
+		return data_types.NewErrorResponse(data_types.ErrorTimeout, "work execution timed out")
 	case response = <-responseChan:
 		// Work completed within the timeout
 		return response
 	}
 }
 
+// HandleWorkerStream serves a work request sent by an older peer with the legacy length-prefixed JSON framing.
 func (whm *WorkHandlerManager) HandleWorkerStream(stream network.Stream) {
+	whm.serveWorkerStream(stream, false)
+}
+
+// HandleWorkerEnvelopeStream serves a work request sent in a versioned frame, see pkg/workers/wire.
+func (whm *WorkHandlerManager) HandleWorkerEnvelopeStream(stream network.Stream) {
+	whm.serveWorkerStream(stream, true)
+}
+
+func (whm *WorkHandlerManager) serveWorkerStream(stream network.Stream, framed bool) {
 	defer func(stream network.Stream) {
 		err := stream.Close()
 		if err != nil {
@@ -336,58 +495,36 @@ func (whm *WorkHandlerManager) HandleWorkerStream(stream network.Stream) {
 		}
 	}(stream)
 
-	// Read the length of the message
-	lengthBuf := make([]byte, 4)
-	_, err := io.ReadFull(stream, lengthBuf)
+	_ = stream.SetReadDeadline(time.Now().Add(workerConfig.WireReadTimeout))
+	workRequest, reply, err := readWorkRequest(stream, framed)
 	if err != nil {
-		logrus.Errorf("error reading message length: %v", err)
+		logrus.Errorf("error reading work request: %v", err)
 		return
 	}
-	messageLength := binary.BigEndian.Uint32(lengthBuf)
+	_ = stream.SetReadDeadline(time.Time{})
 
-	// Read the actual message
-	messageBuf := make([]byte, messageLength)
-	_, err = io.ReadFull(stream, messageBuf)
-	if err != nil {
-		logrus.Errorf("error reading message: %v", err)
-		return
-	}
+	// Stop working on the request if the requester cancels it or goes away
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchForCancel(stream, framed, workRequest, cancel)
 
-	var workRequest data_types.WorkRequest
-	err = json.Unmarshal(messageBuf, &workRequest)
-	if err != nil {
-		logrus.Errorf("error unmarshaling work request: %v", err)
+	peerId := stream.Conn().LocalPeer().String()
+	workResponse := whm.executeWorkLimited(ctx, workRequest)
+	if ctx.Err() != nil {
+		logrus.Infof("[+] %s request %s was cancelled by the requester", workRequest.WorkType, workRequest.RequestId)
 		return
 	}
-	peerId := stream.Conn().LocalPeer().String()
-	workResponse := whm.ExecuteWork(workRequest)
-	if workResponse.Error != "" {
+	if workResponse.Code() == data_types.ErrorWorkerBusy {
+		logrus.Warnf("[-] Rejecting %s request, worker is at capacity", workRequest.WorkType)
+	} else if workResponse.Error != "" {
 		logrus.Errorf("error from remote worker %s: executing work: %s", peerId, workResponse.Error)
 	}
 	workResponse.WorkerPeerId = peerId
 	whm.eventTracker.TrackWorkCompletion(workRequest.WorkType, workResponse.Error == "", peerId)
 
 	// Write the response to the stream
-	responseBytes, err := json.Marshal(workResponse)
-	if err != nil {
-		logrus.Errorf("error marshaling work response: %v", err)
-		return
-	}
-
-	// Prefix the response with its length
-	responseLength := uint32(len(responseBytes))
-	lengthBuf = make([]byte, 4)
-	binary.BigEndian.PutUint32(lengthBuf, responseLength)
-
-	_, err = stream.Write(lengthBuf)
-	if err != nil {
-		logrus.Errorf("error writing response length to stream: %v", err)
-		return
-	}
-
-	_, err = stream.Write(responseBytes)
-	if err != nil {
+	_ = stream.SetWriteDeadline(time.Now().Add(workerConfig.WireWriteTimeout))
+	if err := reply(workResponse); err != nil {
 		logrus.Errorf("error writing response to stream: %v", err)
-		return
 	}
 }
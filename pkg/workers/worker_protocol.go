@@ -0,0 +1,149 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/sirupsen/logrus"
+
+	"github.com/masa-finance/masa-oracle/node"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+	"github.com/masa-finance/masa-oracle/pkg/workers/wire"
+)
+
+// openWorkerStream opens a stream to a worker, preferring the framed worker protocol and falling back
+// to the legacy one for peers that do not support it yet.
+func openWorkerStream(ctx context.Context, node *node.OracleNode, peerID peer.ID) (network.Stream, error) {
+	if node.Options.WorkerEnvelopeProtocol == "" {
+		return node.ProtocolStream(ctx, peerID, node.Options.WorkerProtocol)
+	}
+	return node.ProtocolStream(ctx, peerID, node.Options.WorkerEnvelopeProtocol, node.Options.WorkerProtocol)
+}
+
+// wireCodec returns the codec used for outgoing work requests.
+func wireCodec() wire.Codec {
+	codec, err := wire.ParseCodec(workerConfig.WireCodec)
+	if err != nil {
+		logrus.Warnf("[-] %v, using json", err)
+		return wire.CodecJSON
+	}
+	return codec
+}
+
+// writeWorkRequest writes a work request, either framed or with the legacy framing.
+func writeWorkRequest(w io.Writer, framed bool, workRequest data_types.WorkRequest) error {
+	if !framed {
+		return wire.WriteLegacy(w, workRequest)
+	}
+	return wire.WriteFrame(w, wire.MessageRequest, workRequest, wire.WriteOptions{
+		Codec:   wireCodec(),
+		Accept:  wire.SupportedCompressions,
+		MaxSize: workerConfig.MaxRequestSize,
+	})
+}
+
+// readWorkResponse reads the answer to a request written by writeWorkRequest.
+func readWorkResponse(r io.Reader, framed bool) (response data_types.WorkResponse, err error) {
+	if !framed {
+		err = wire.ReadLegacy(r, workerConfig.MaxResponseSize, &response)
+		return response, err
+	}
+	frame, err := wire.ReadFrame(r, workerConfig.MaxResponseSize)
+	if err != nil {
+		return response, err
+	}
+	if frame.Type != wire.MessageResponse {
+		return response, fmt.Errorf("unexpected message type %d", frame.Type)
+	}
+	err = frame.Decode(&response)
+	return response, err
+}
+
+// readWorkRequest reads a work request on the worker side. The returned reply function writes the response
+// the way the requester expects it: with the same framing and codec, and compressed if the requester
+// accepts a compression this node supports.
+func readWorkRequest(r io.ReadWriter, framed bool) (workRequest data_types.WorkRequest, reply func(data_types.WorkResponse) error, err error) {
+	if !framed {
+		err = wire.ReadLegacy(r, workerConfig.MaxRequestSize, &workRequest)
+		reply = func(response data_types.WorkResponse) error {
+			return wire.WriteLegacy(r, response)
+		}
+		return workRequest, reply, err
+	}
+
+	frame, err := wire.ReadFrame(r, workerConfig.MaxRequestSize)
+	if err != nil {
+		return workRequest, nil, err
+	}
+	if frame.Type != wire.MessageRequest {
+		return workRequest, nil, fmt.Errorf("unexpected message type %d", frame.Type)
+	}
+	if err = frame.Decode(&workRequest); err != nil {
+		return workRequest, nil, err
+	}
+	reply = func(response data_types.WorkResponse) error {
+		err := wire.WriteFrame(r, wire.MessageResponse, response, wire.WriteOptions{
+			Codec:             frame.Codec,
+			Compression:       frame.Accept.Negotiate(),
+			CompressThreshold: workerConfig.WireCompressThreshold,
+			MaxSize:           workerConfig.MaxResponseSize,
+		})
+		if errors.Is(err, wire.ErrMessageTooLarge) {
+			// Let the requester know why it is not getting the result
+			logrus.Errorf("error writing work response: %v", err)
+			tooLarge := data_types.NewErrorResponse(data_types.ErrorInternal, "work response too large: %v", err)
+			tooLarge.WorkerPeerId = response.WorkerPeerId
+			return wire.WriteFrame(r, wire.MessageResponse, tooLarge, wire.WriteOptions{Codec: frame.Codec})
+		}
+		return err
+	}
+	return workRequest, reply, nil
+}
+
+// cancelWriteTimeout bounds how long the requester tries to deliver a cancel message.
+const cancelWriteTimeout = time.Second
+
+// cancelMessage asks the worker to stop working on a request.
+type cancelMessage struct {
+	RequestId string `json:"requestId,omitempty"`
+}
+
+// writeCancel tells the worker on a framed stream that the request is no longer wanted.
+func writeCancel(w io.Writer, requestId string) error {
+	return wire.WriteFrame(w, wire.MessageCancel, cancelMessage{RequestId: requestId}, wire.WriteOptions{Codec: wireCodec()})
+}
+
+// watchForCancel reads from the stream after the request was received and calls cancel when the requester
+// sends a cancel message or the stream breaks. The legacy protocol has no cancel message, so only a broken
+// stream cancels the work there. It returns when the requester closes its side of the stream.
+func watchForCancel(r io.Reader, framed bool, workRequest data_types.WorkRequest, cancel context.CancelFunc) {
+	for {
+		var err error
+		if framed {
+			var frame *wire.Frame
+			frame, err = wire.ReadFrame(r, workerConfig.MaxRequestSize)
+			if err == nil {
+				if frame.Type == wire.MessageCancel {
+					logrus.Infof("[+] Requester cancelled %s request %s", workRequest.WorkType, workRequest.RequestId)
+					cancel()
+					return
+				}
+				continue
+			}
+		} else {
+			_, err = r.Read(make([]byte, 1))
+			if err == nil {
+				continue
+			}
+		}
+		if !errors.Is(err, io.EOF) {
+			cancel()
+		}
+		return
+	}
+}
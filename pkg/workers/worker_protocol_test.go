@@ -0,0 +1,89 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+	"github.com/masa-finance/masa-oracle/pkg/workers/wire"
+)
+
+// loopback lets the worker side read a request from one buffer and write its reply into another.
+type loopback struct {
+	io.Reader
+	io.Writer
+}
+
+func TestWorkerProtocolRoundTrip(t *testing.T) {
+	request := data_types.WorkRequest{
+		WorkType:  data_types.Web,
+		RequestId: "request-1",
+		Data:      []byte(`{"url":"https://masa.ai","depth":1}`),
+		Dispatch:  &data_types.DispatchOptions{FanOut: 2},
+	}
+	response := data_types.WorkResponse{
+		Data:         map[string]interface{}{"title": "Masa", "links": []interface{}{"https://masa.ai/about"}},
+		WorkerPeerId: "worker-1",
+	}
+
+	for _, framed := range []bool{false, true} {
+		var toWorker, toRequester bytes.Buffer
+		require.NoError(t, writeWorkRequest(&toWorker, framed, request))
+
+		received, reply, err := readWorkRequest(loopback{&toWorker, &toRequester}, framed)
+		require.NoError(t, err)
+		assert.Equal(t, request, received)
+
+		require.NoError(t, reply(response))
+		if framed {
+			frame, err := wire.ReadFrame(bytes.NewReader(toRequester.Bytes()), 0)
+			require.NoError(t, err)
+			assert.Equal(t, wireCodec(), frame.Codec)
+		}
+		answer, err := readWorkResponse(&toRequester, framed)
+		require.NoError(t, err)
+		assert.Equal(t, "worker-1", answer.WorkerPeerId)
+		data, ok := answer.Data.(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "Masa", data["title"])
+	}
+}
+
+func TestWorkerProtocolRejectsOversizedResponses(t *testing.T) {
+	maxSize := workerConfig.MaxResponseSize
+	workerConfig.MaxResponseSize = 256
+	defer func() { workerConfig.MaxResponseSize = maxSize }()
+
+	var toWorker, toRequester bytes.Buffer
+	require.NoError(t, writeWorkRequest(&toWorker, true, data_types.WorkRequest{WorkType: data_types.Web}))
+	_, reply, err := readWorkRequest(loopback{&toWorker, &toRequester}, true)
+	require.NoError(t, err)
+
+	// The worker answers with an error instead of a result the requester would refuse to read
+	require.NoError(t, reply(data_types.WorkResponse{Data: string(bytes.Repeat([]byte("masa"), 1000))}))
+	answer, err := readWorkResponse(&toRequester, true)
+	require.NoError(t, err)
+	assert.Contains(t, answer.Error, "work response too large")
+}
+
+func TestWatchForCancel(t *testing.T) {
+	request := data_types.WorkRequest{WorkType: data_types.Web, RequestId: "request-1"}
+
+	var stream bytes.Buffer
+	require.NoError(t, writeCancel(&stream, request.RequestId))
+	ctx, cancel := context.WithCancel(context.Background())
+	watchForCancel(&stream, true, request, cancel)
+	assert.Error(t, ctx.Err(), "a cancel message cancels the work")
+
+	// The requester closing its side of the stream does not
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	watchForCancel(&bytes.Buffer{}, true, request, cancel)
+	watchForCancel(&bytes.Buffer{}, false, request, cancel)
+	assert.NoError(t, ctx.Err())
+}
@@ -1,7 +1,15 @@
 package workers
 
 import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
 	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/sirupsen/logrus"
@@ -11,38 +19,125 @@ import (
 	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
 )
 
-// GetEligibleWorkers returns eligible workers for a given message type.
-// For Twitter workers, it uses a balanced approach between high-performing workers and fair distribution.
-// For other worker types, it returns all eligible workers without modification.
-func GetEligibleWorkers(node *node.OracleNode, category pubsub.WorkerCategory, limit int) ([]data_types.Worker, *data_types.Worker) {
-	nodes := node.NodeTracker.GetEligibleWorkerNodes(category)
+// Names of the built-in worker selection strategies, as used in the worker selection configuration.
+const (
+	SelectorReliability   = "reliability"
+	SelectorRandom        = "random"
+	SelectorRoundRobin    = "round-robin"
+	SelectorLeastLoaded   = "least-loaded"
+	SelectorStakeWeighted = "stake-weighted"
+	SelectorSticky        = "sticky"
+)
+
+// WorkerSelector decides in which order the eligible nodes are tried for a work request.
+// SelectWorkers returns the nodes to try, best candidate first. If limit is greater than zero
+// the selector may use it to narrow down the candidates.
+type WorkerSelector interface {
+	SelectWorkers(nodes []pubsub.NodeData, workRequest data_types.WorkRequest, limit int) []pubsub.NodeData
+}
 
-	logrus.Infof("Getting eligible workers for category: %s", category)
+// defaultSelectors are the strategies used for the categories that are not configured explicitly.
+var defaultSelectors = map[pubsub.WorkerCategory]string{
+	pubsub.CategoryDiscord:  SelectorReliability,
+	pubsub.CategoryTelegram: SelectorReliability,
+	pubsub.CategoryTwitter:  SelectorReliability,
+	pubsub.CategoryWeb:      SelectorReliability,
+}
 
-	if category == pubsub.CategoryTwitter {
-		return getTwitterWorkers(node, nodes, limit)
+// newWorkerSelector creates the built-in selector with the given name. load reports the number of
+// requests in flight for a peer and is only used by the least-loaded strategy.
+func newWorkerSelector(name string, load func(peerId string) int) (WorkerSelector, error) {
+	switch name {
+	case SelectorReliability:
+		return &ReliabilitySelector{}, nil
+	case SelectorRandom:
+		return &RandomSelector{}, nil
+	case SelectorRoundRobin:
+		return &RoundRobinSelector{}, nil
+	case SelectorLeastLoaded:
+		return &LeastLoadedSelector{Load: load}, nil
+	case SelectorStakeWeighted:
+		return &StakeWeightedSelector{}, nil
+	case SelectorSticky:
+		return &StickySelector{}, nil
+	default:
+		return nil, fmt.Errorf("unknown worker selector %q", name)
 	}
+}
 
-	// For non-Twitter categories, return all eligible workers without modification
-	return getAllWorkers(node, nodes, limit)
+// ParseWorkerSelection parses a worker selection configuration of the form
+// "twitter=reliability,web=least-loaded" into a strategy name per worker category.
+func ParseWorkerSelection(spec string) (map[pubsub.WorkerCategory]string, error) {
+	result := make(map[pubsub.WorkerCategory]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid worker selection entry %q, expected category=strategy", entry)
+		}
+		category, err := parseWorkerCategory(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSpace(parts[1])
+		if _, err := newWorkerSelector(name, nil); err != nil {
+			return nil, err
+		}
+		result[category] = name
+	}
+	return result, nil
 }
 
-// getTwitterWorkers selects and shuffles a pool of top-performing Twitter workers
-func getTwitterWorkers(node *node.OracleNode, nodes []pubsub.NodeData, limit int) ([]data_types.Worker, *data_types.Worker) {
-	poolSize := calculatePoolSize(len(nodes), limit)
-	topPerformers := nodes[:poolSize]
+// parseWorkerCategory converts a case-insensitive category name such as "twitter" into its WorkerCategory.
+func parseWorkerCategory(name string) (pubsub.WorkerCategory, error) {
+	for _, category := range []pubsub.WorkerCategory{pubsub.CategoryDiscord, pubsub.CategoryTelegram, pubsub.CategoryTwitter, pubsub.CategoryWeb} {
+		if strings.EqualFold(category.String(), name) {
+			return category, nil
+		}
+	}
+	return -1, fmt.Errorf("unknown worker category %q", name)
+}
 
-	// Shuffle the top performers
-	rand.Shuffle(len(topPerformers), func(i, j int) {
-		topPerformers[i], topPerformers[j] = topPerformers[j], topPerformers[i]
-	})
+// GetEligibleWorkers returns the remote workers whose advertised capabilities match the work request, ordered by
+// the given selector, and the local worker if this node is eligible as well. If available is not nil, remote nodes
+// for which it returns false, such as peers whose circuit breaker is open, are left out.
+func GetEligibleWorkers(node *node.OracleNode, selector WorkerSelector, available func(peerId string) bool, workRequest data_types.WorkRequest, limit int) ([]data_types.Worker, *data_types.Worker) {
+	requirement := workRequirement(workRequest)
+	nodes := node.NodeTracker.GetEligibleWorkerNodes(requirement)
+	if available != nil {
+		nodes = availableNodes(nodes, available, node.Host.ID().String())
+	}
+
+	logrus.Infof("Getting eligible workers for category: %s", requirement.Category)
+
+	selected := selector.SelectWorkers(nodes, workRequest, limit)
+	// Workers that recently returned data disagreeing with a quorum are only tried last
+	pubsub.DeprioritizeDivergentNodes(selected)
 
-	return createWorkerList(node, topPerformers, limit)
+	workers, localWorker := createWorkerList(node, selected, limit)
+	if localWorker == nil {
+		// The local node stays available as a fallback even if the selector left it out
+		_, localWorker = createWorkerList(node, nodes, 0)
+	}
+	return workers, localWorker
 }
 
-// getAllWorkers returns all eligible workers for non-Twitter categories
-func getAllWorkers(node *node.OracleNode, nodes []pubsub.NodeData, limit int) ([]data_types.Worker, *data_types.Worker) {
-	return createWorkerList(node, nodes, limit)
+// availableNodes drops the remote nodes that are not available. The local node is always kept.
+func availableNodes(nodes []pubsub.NodeData, available func(peerId string) bool, localPeerId string) []pubsub.NodeData {
+	result := make([]pubsub.NodeData, 0, len(nodes))
+	for _, nd := range nodes {
+		peerId := nd.PeerId.String()
+		if peerId == localPeerId || available(peerId) {
+			result = append(result, nd)
+		}
+	}
+	if skipped := len(nodes) - len(result); skipped > 0 {
+		logrus.Infof("Skipping %d unavailable workers", skipped)
+	}
+	return result
 }
 
 // createWorkerList creates a list of workers from the given nodes, respecting the limit
@@ -71,7 +166,171 @@ func createWorkerList(node *node.OracleNode, nodes []pubsub.NodeData, limit int)
 	return workers, localWorker
 }
 
-// calculatePoolSize determines the size of the top performers pool for Twitter workers
+// copyNodes returns a copy of nodes so selectors can reorder it freely.
+func copyNodes(nodes []pubsub.NodeData) []pubsub.NodeData {
+	return append([]pubsub.NodeData(nil), nodes...)
+}
+
+// ReliabilitySelector ranks the nodes by their track record and picks randomly among a pool of
+// top performers, balancing between high-performing workers and fair distribution.
+type ReliabilitySelector struct{}
+
+func (s *ReliabilitySelector) SelectWorkers(nodes []pubsub.NodeData, workRequest data_types.WorkRequest, limit int) []pubsub.NodeData {
+	ranked := copyNodes(nodes)
+	pubsub.SortNodesByReliability(ranked, data_types.WorkerTypeToCategory(workRequest.WorkType))
+
+	poolSize := calculatePoolSize(len(ranked), limit)
+	topPerformers := ranked[:poolSize]
+
+	// Shuffle the top performers
+	rand.Shuffle(len(topPerformers), func(i, j int) {
+		topPerformers[i], topPerformers[j] = topPerformers[j], topPerformers[i]
+	})
+
+	return topPerformers
+}
+
+// RandomSelector tries the nodes in random order.
+type RandomSelector struct{}
+
+func (s *RandomSelector) SelectWorkers(nodes []pubsub.NodeData, workRequest data_types.WorkRequest, limit int) []pubsub.NodeData {
+	shuffled := copyNodes(nodes)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// RoundRobinSelector cycles through the nodes, starting each request one node further along.
+type RoundRobinSelector struct {
+	next atomic.Uint64
+}
+
+func (s *RoundRobinSelector) SelectWorkers(nodes []pubsub.NodeData, workRequest data_types.WorkRequest, limit int) []pubsub.NodeData {
+	if len(nodes) == 0 {
+		return nil
+	}
+	ordered := copyNodes(nodes)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].PeerId.String() < ordered[j].PeerId.String()
+	})
+	offset := int((s.next.Add(1) - 1) % uint64(len(ordered)))
+	return append(ordered[offset:], ordered[:offset]...)
+}
+
+// LeastLoadedSelector prefers the nodes with the fewest requests in flight from this node.
+// Nodes with the same load are tried in random order.
+type LeastLoadedSelector struct {
+	Load func(peerId string) int
+}
+
+func (s *LeastLoadedSelector) SelectWorkers(nodes []pubsub.NodeData, workRequest data_types.WorkRequest, limit int) []pubsub.NodeData {
+	ordered := copyNodes(nodes)
+	rand.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	if s.Load == nil {
+		return ordered
+	}
+	loads := make(map[string]int, len(ordered))
+	for _, nd := range ordered {
+		loads[nd.PeerId.String()] = s.Load(nd.PeerId.String())
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return loads[ordered[i].PeerId.String()] < loads[ordered[j].PeerId.String()]
+	})
+	return ordered
+}
+
+// StakeWeightedSelector orders the nodes randomly, giving each node a chance to come first that is
+// proportional to its stake. Nodes that do not advertise a stake amount get the weight of one token.
+type StakeWeightedSelector struct{}
+
+// weiPerToken is used to convert stake amounts from wei into tokens.
+var weiPerToken = new(big.Float).SetFloat64(1e18)
+
+func (s *StakeWeightedSelector) SelectWorkers(nodes []pubsub.NodeData, workRequest data_types.WorkRequest, limit int) []pubsub.NodeData {
+	ordered := copyNodes(nodes)
+	keys := make(map[string]float64, len(ordered))
+	for _, nd := range ordered {
+		// Weighted random sampling without replacement (Efraimidis-Spirakis): sort by u^(1/w)
+		keys[nd.PeerId.String()] = math.Pow(rand.Float64(), 1/stakeWeight(nd))
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return keys[ordered[i].PeerId.String()] > keys[ordered[j].PeerId.String()]
+	})
+	return ordered
+}
+
+// stakeWeight returns the stake of a node in tokens, or 1 if the node does not advertise a valid stake.
+func stakeWeight(nd pubsub.NodeData) float64 {
+	amount, ok := new(big.Float).SetString(nd.StakeAmount)
+	if !ok || amount.Sign() <= 0 {
+		return 1
+	}
+	tokens, _ := new(big.Float).Quo(amount, weiPerToken).Float64()
+	if tokens <= 0 || math.IsInf(tokens, 0) {
+		return 1
+	}
+	return tokens
+}
+
+// StickySelector sends the requests of a requester to the same nodes for as long as they are eligible,
+// using rendezvous hashing so that nodes joining or leaving only affect the requesters mapped to them.
+// Requests without a requester are ordered randomly.
+type StickySelector struct{}
+
+func (s *StickySelector) SelectWorkers(nodes []pubsub.NodeData, workRequest data_types.WorkRequest, limit int) []pubsub.NodeData {
+	if workRequest.Requester == "" {
+		return (&RandomSelector{}).SelectWorkers(nodes, workRequest, limit)
+	}
+	ordered := copyNodes(nodes)
+	scores := make(map[string]uint64, len(ordered))
+	for _, nd := range ordered {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(workRequest.Requester))
+		_, _ = h.Write([]byte(nd.PeerId.String()))
+		scores[nd.PeerId.String()] = h.Sum64()
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return scores[ordered[i].PeerId.String()] > scores[ordered[j].PeerId.String()]
+	})
+	return ordered
+}
+
+// peerLoad counts the requests this node currently has in flight with each remote peer.
+type peerLoad struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+}
+
+func newPeerLoad() *peerLoad {
+	return &peerLoad{inFlight: make(map[string]int)}
+}
+
+// start records a new request to the peer and returns a function that records its completion.
+func (l *peerLoad) start(peerId string) func() {
+	l.mu.Lock()
+	l.inFlight[peerId]++
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.inFlight[peerId]--
+		if l.inFlight[peerId] <= 0 {
+			delete(l.inFlight, peerId)
+		}
+	}
+}
+
+// get returns the number of requests in flight to the peer.
+func (l *peerLoad) get(peerId string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight[peerId]
+}
+
+// calculatePoolSize determines the size of the top performers pool for reliability-based selection
 func calculatePoolSize(totalNodes, limit int) int {
 	if limit <= 0 {
 		return totalNodes // If no limit, consider all nodes
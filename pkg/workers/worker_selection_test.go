@@ -0,0 +1,116 @@
+package workers
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/masa-finance/masa-oracle/pkg/pubsub"
+	data_types "github.com/masa-finance/masa-oracle/pkg/workers/types"
+)
+
+func testNodes(ids ...string) []pubsub.NodeData {
+	nodes := make([]pubsub.NodeData, 0, len(ids))
+	for _, id := range ids {
+		nodes = append(nodes, pubsub.NodeData{PeerId: peer.ID(id)})
+	}
+	return nodes
+}
+
+func peerIds(nodes []pubsub.NodeData) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, nd := range nodes {
+		ids = append(ids, string(nd.PeerId))
+	}
+	return ids
+}
+
+func TestParseWorkerSelection(t *testing.T) {
+	selection, err := ParseWorkerSelection("twitter=reliability, Web=least-loaded")
+	require.NoError(t, err)
+	assert.Equal(t, map[pubsub.WorkerCategory]string{
+		pubsub.CategoryTwitter: SelectorReliability,
+		pubsub.CategoryWeb:     SelectorLeastLoaded,
+	}, selection)
+
+	selection, err = ParseWorkerSelection("")
+	require.NoError(t, err)
+	assert.Empty(t, selection)
+
+	_, err = ParseWorkerSelection("twitter")
+	assert.Error(t, err)
+	_, err = ParseWorkerSelection("mastodon=random")
+	assert.Error(t, err)
+	_, err = ParseWorkerSelection("web=fastest")
+	assert.Error(t, err)
+}
+
+func TestWorkerSelectors(t *testing.T) {
+	nodes := testNodes("a", "b", "c")
+	request := data_types.WorkRequest{WorkType: data_types.Web}
+
+	t.Run("round-robin starts one node further along each time", func(t *testing.T) {
+		selector := &RoundRobinSelector{}
+		assert.Equal(t, []string{"a", "b", "c"}, peerIds(selector.SelectWorkers(nodes, request, 0)))
+		assert.Equal(t, []string{"b", "c", "a"}, peerIds(selector.SelectWorkers(nodes, request, 0)))
+		assert.Equal(t, []string{"c", "a", "b"}, peerIds(selector.SelectWorkers(nodes, request, 0)))
+		assert.Equal(t, []string{"a", "b", "c"}, peerIds(selector.SelectWorkers(nodes, request, 0)))
+	})
+
+	t.Run("least-loaded prefers idle nodes", func(t *testing.T) {
+		load := newPeerLoad()
+		doneA := load.start(peer.ID("a").String())
+		load.start(peer.ID("b").String())
+		load.start(peer.ID("b").String())
+		selector := &LeastLoadedSelector{Load: load.get}
+		assert.Equal(t, []string{"c", "a", "b"}, peerIds(selector.SelectWorkers(nodes, request, 0)))
+
+		doneA()
+		assert.Equal(t, 0, load.get(peer.ID("a").String()))
+		assert.Equal(t, "b", peerIds(selector.SelectWorkers(nodes, request, 0))[2])
+	})
+
+	t.Run("stake-weighted favours the largest stake", func(t *testing.T) {
+		staked := testNodes("a", "b")
+		staked[1].StakeAmount = "1000000000000000000000000" // one million tokens
+		selector := &StakeWeightedSelector{}
+		first := 0
+		for i := 0; i < 100; i++ {
+			if string(selector.SelectWorkers(staked, request, 0)[0].PeerId) == "b" {
+				first++
+			}
+		}
+		assert.Greater(t, first, 95)
+		assert.Equal(t, float64(1), stakeWeight(pubsub.NodeData{StakeAmount: "invalid"}))
+	})
+
+	t.Run("sticky keeps a requester on the same nodes", func(t *testing.T) {
+		selector := &StickySelector{}
+		request := data_types.WorkRequest{WorkType: data_types.Web, Requester: "client-1"}
+		order := peerIds(selector.SelectWorkers(nodes, request, 0))
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, order, peerIds(selector.SelectWorkers(nodes, request, 0)))
+		}
+
+		// Removing a node that is not first does not change the first choice
+		var remaining []pubsub.NodeData
+		for _, nd := range nodes {
+			if string(nd.PeerId) != order[2] {
+				remaining = append(remaining, nd)
+			}
+		}
+		assert.Equal(t, order[0], string(selector.SelectWorkers(remaining, request, 0)[0].PeerId))
+	})
+
+	t.Run("selectors do not modify the node list", func(t *testing.T) {
+		for _, name := range []string{SelectorReliability, SelectorRandom, SelectorRoundRobin, SelectorLeastLoaded, SelectorStakeWeighted, SelectorSticky} {
+			selector, err := newWorkerSelector(name, func(string) int { return 0 })
+			require.NoError(t, err)
+			selected := selector.SelectWorkers(nodes, request, 0)
+			assert.ElementsMatch(t, []string{"a", "b", "c"}, peerIds(selected), name)
+			assert.Equal(t, []string{"a", "b", "c"}, peerIds(nodes), name)
+		}
+	})
+}
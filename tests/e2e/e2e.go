@@ -116,16 +116,21 @@ var _ = Describe("E2E tests", func() {
 			Expect(response).ToNot(BeNil())
 			Expect(string(response)).ToNot(ContainSubstring("google"))
 
+			// Results are kept sealed, wrapped in an envelope
 			dataResult := struct {
-				Data string `json:"data"`
+				Data struct {
+					Algorithm string `json:"algorithm"`
+					Data      string `json:"data"`
+				} `json:"data"`
 			}{}
 
 			err = json.Unmarshal(response, &dataResult)
 			Expect(err).ToNot(HaveOccurred())
+			Expect(dataResult.Data.Algorithm).ToNot(BeEmpty())
 
 			// Decrypt the response
 			cli := client.NewClient("http://localhost:8081")
-			decrypted, err := cli.Decrypt(dataResult.Data)
+			decrypted, err := cli.Decrypt(dataResult.Data.Data)
 			Expect(err).ToNot(HaveOccurred(), string(response))
 			Expect(decrypted).To(ContainSubstring("google"))
 		})